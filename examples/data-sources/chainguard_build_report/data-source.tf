@@ -0,0 +1,14 @@
+# Lookup a build report by its UIDP.
+data "chainguard_build_report" "by_id" {
+  id = "<build report UIDP>"
+}
+
+# Lookup the most recent build of an image by its digest.
+data "chainguard_build_report" "by_digest" {
+  repo   = "<repo UIDP>"
+  digest = "sha256:..."
+}
+
+output "locked_config" {
+  value = data.chainguard_build_report.by_digest.locked_config
+}
@@ -0,0 +1,10 @@
+# Audit what an identity can do in a group, through its rolebindings in the
+# group and the group's ancestors.
+data "chainguard_effective_access" "ci" {
+  identity = chainguard_identity.ci.id
+  group    = chainguard_group.team.id
+}
+
+output "ci_capabilities" {
+  value = data.chainguard_effective_access.ci.capabilities
+}
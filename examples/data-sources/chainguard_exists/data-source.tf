@@ -0,0 +1,9 @@
+# Check whether a repo exists without failing the plan when it does not.
+data "chainguard_exists" "repo" {
+  kind = "repo"
+  id   = var.repo_id
+}
+
+output "repo_exists" {
+  value = data.chainguard_exists.repo.exists
+}
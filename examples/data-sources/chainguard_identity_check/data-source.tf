@@ -0,0 +1,14 @@
+# Check whether a CI token may assume an identity, e.g. while debugging
+# its claim_match constraints.
+data "chainguard_identity_check" "ci" {
+  identity_id = chainguard_identity.ci.id
+  token       = var.oidc_token
+}
+
+output "ci_allowed" {
+  value = data.chainguard_identity_check.ci.allowed
+}
+
+output "ci_reason" {
+  value = data.chainguard_identity_check.ci.reason
+}
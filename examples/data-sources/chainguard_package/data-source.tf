@@ -0,0 +1,9 @@
+# Check whether a fips variant of a package exists before building with it.
+data "chainguard_package" "python_fips" {
+  package = "python"
+  variant = "fips"
+}
+
+output "python_fips_latest" {
+  value = data.chainguard_package.python_fips.exists ? data.chainguard_package.python_fips.latest_version : null
+}
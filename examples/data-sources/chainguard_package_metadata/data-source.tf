@@ -0,0 +1,4 @@
+# Look up the raw version metadata for a package.
+data "chainguard_package_metadata" "python" {
+  package = "python"
+}
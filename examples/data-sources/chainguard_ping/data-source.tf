@@ -0,0 +1,7 @@
+# Fail early when the console API is unreachable or the provider cannot
+# authenticate, e.g. as the first step of a pipeline.
+data "chainguard_ping" "this" {}
+
+output "console_api_latency_ms" {
+  value = data.chainguard_ping.this.latency_ms
+}
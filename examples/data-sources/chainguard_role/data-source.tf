@@ -8,3 +8,9 @@ data "chainguard_roles" "owner_role" {
   name   = "owner"
   parent = "/"
 }
+
+# Find the managed roles which grant pulling from the registry.
+data "chainguard_role" "pullers" {
+  parent         = "/"
+  has_capability = "registry.pull"
+}
@@ -0,0 +1,14 @@
+# List the Chainguard managed roles for pulling from or pushing to the registry.
+data "chainguard_roles" "registry" {
+  parent_id     = "/"
+  name_contains = "registry."
+}
+
+# Bind each of them to an identity.
+resource "chainguard_rolebinding" "registry" {
+  for_each = { for role in data.chainguard_roles.registry.items : role.name => role.id }
+
+  identity = chainguard_identity.ci.id
+  group    = chainguard_group.example.id
+  role     = each.value
+}
@@ -0,0 +1,7 @@
+# Reuse the provider's Chainguard token, e.g. for raw API calls.
+data "chainguard_token" "this" {}
+
+output "chainguard_token" {
+  value     = data.chainguard_token.this.token
+  sensitive = true
+}
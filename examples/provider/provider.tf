@@ -6,3 +6,13 @@ provider "chainguard" {}
 # provider "chainguard" {
 #   console_api = "https://console-api.example.com"
 # }
+
+# Allow image repos and tags to be deleted through Terraform.
+# provider "chainguard" {
+#   allow_destroy = true
+# }
+
+# Verify resources persisted as configured after creating them.
+# provider "chainguard" {
+#   strict = true
+# }
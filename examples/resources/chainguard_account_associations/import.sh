@@ -0,0 +1,6 @@
+# Account associations can be imported by specifying the exact UIDP of their group
+terraform import chainguard_account_associations.example fb694596eb1678321f94eec283e1e0be690f655c/ae3a1bdc96e6f1a4
+
+# chainguard.service_bindings is populated with the UIDPs of the bound service
+# principal identities, which can then be imported themselves, e.g.
+terraform import chainguard_identity.ingester fb694596eb1678321f94eec283e1e0be690f655c/ae3a1bdc96e6f1a4/0123456789abcdef
@@ -0,0 +1,16 @@
+resource "chainguard_apko_builds" "variants" {
+  repo = chainguard_image_repo.repo.id
+
+  builds = {
+    "base" = {
+      config = file("${path.module}/base.yaml")
+    }
+    "dev" = {
+      config = file("${path.module}/dev.yaml")
+    }
+  }
+}
+
+output "dev_image" {
+  value = chainguard_apko_builds.variants.builds["dev"].image_ref
+}
@@ -10,3 +10,24 @@ resource "chainguard_group" "example_sub" {
   description = "My example sub group."
   parent_id   = chainguard_group.example.id
 }
+
+# Example managed sub-group which binds an owner as it is created.
+variable "owner_identity_id" {
+  type        = string
+  description = "The UIDP of the identity to own the team group."
+}
+
+data "chainguard_role" "owner" {
+  name   = "owner"
+  parent = "/"
+}
+
+resource "chainguard_group" "example_team" {
+  name      = "example-team"
+  parent_id = chainguard_group.example.id
+
+  default_rolebindings = [{
+    identity = var.owner_identity_id
+    role     = data.chainguard_role.owner.items[0].id
+  }]
+}
@@ -0,0 +1,14 @@
+# Manage one GitHub Actions identity per repository in a single resource.
+resource "chainguard_identities" "ci" {
+  parent_id = "my-group-id"
+
+  identities = {
+    for repo in ["api", "web", "docs"] : "ci-${repo}" => {
+      description = "GitHub Actions on the main branch of ${repo}"
+      claim_match = {
+        issuer  = "https://token.actions.githubusercontent.com"
+        subject = "repo:my-org/${repo}:ref:refs/heads/main"
+      }
+    }
+  }
+}
@@ -0,0 +1,5 @@
+# Identity can be imported by specifying the exact UIDP of the identity
+terraform import chainguard_identity.example fb694596eb1678321f94eec283e1e0be690f655c/ae3a1bdc96e6f1a4
+
+# or by the UIDP of its parent group and its name, if the name is unique within the group
+terraform import chainguard_identity.example fb694596eb1678321f94eec283e1e0be690f655c/my-identity
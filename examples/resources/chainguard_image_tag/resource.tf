@@ -3,3 +3,10 @@ resource "chainguard_image_tag" "example" {
   repo_id = "foo/bar"
   bundles = ["a", "b"]
 }
+
+# A floating tag that tracks the digest of another tag.
+resource "chainguard_image_tag" "latest" {
+  name     = "latest"
+  repo_id  = "foo/bar"
+  alias_of = chainguard_image_tag.example.id
+}
@@ -0,0 +1,6 @@
+# Grant the viewer role in a group to every CI identity.
+resource "chainguard_rolebindings" "ci_viewers" {
+  group      = "foo/bar"
+  role       = data.chainguard_role.viewer.items[0].id
+  identities = [for id in chainguard_identities.ci.identities : id.id]
+}
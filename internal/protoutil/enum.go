@@ -9,12 +9,27 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"golang.org/x/exp/maps"
 )
 
+// EnumToQuotedString returns the names of enum, sorted, quoted and comma
+// separated for use in descriptions.
 func EnumToQuotedString(enum map[string]int32) string {
-	// Sort for stable output
+	return `"` + strings.Join(enumNames(enum), `", "`) + `"`
+}
+
+// EnumOneOf returns a validator that checks a string is one of the names of
+// enum, e.g. iam.ServicePrincipal_value. Pair it with EnumToQuotedString in
+// the attribute description.
+func EnumOneOf(enum map[string]int32) validator.String {
+	return stringvalidator.OneOf(enumNames(enum)...)
+}
+
+// enumNames returns the names of enum, sorted for stable output.
+func enumNames(enum map[string]int32) []string {
 	keys := maps.Keys(enum)
 	sort.Strings(keys)
-	return `"` + strings.Join(keys, `", "`) + `"`
+	return keys
 }
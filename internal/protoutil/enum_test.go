@@ -6,7 +6,13 @@ SPDX-License-Identifier: Apache-2.0
 package protoutil
 
 import (
+	"context"
+	"strings"
 	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 func TestEnumToQuotedString(t *testing.T) {
@@ -36,3 +42,44 @@ func TestEnumToQuotedString(t *testing.T) {
 		})
 	}
 }
+
+func TestEnumOneOf(t *testing.T) {
+	enum := map[string]int32{
+		"UNKNOWN": 0,
+		"FIRST":   1,
+		"SECOND":  2,
+	}
+	v := EnumOneOf(enum)
+
+	tests := map[string]struct {
+		value   types.String
+		wantErr bool
+	}{
+		"member":       {value: types.StringValue("FIRST")},
+		"zero member":  {value: types.StringValue("UNKNOWN")},
+		"null":         {value: types.StringNull()},
+		"not a member": {value: types.StringValue("THIRD"), wantErr: true},
+		"wrong case":   {value: types.StringValue("first"), wantErr: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := &validator.StringResponse{}
+			v.ValidateString(context.Background(), validator.StringRequest{
+				Path:        path.Root("test"),
+				ConfigValue: test.value,
+			}, resp)
+			if got := resp.Diagnostics.HasError(); got != test.wantErr {
+				t.Errorf("ValidateString() error = %t, wanted %t: %v", got, test.wantErr, resp.Diagnostics)
+			}
+		})
+	}
+
+	// Every name is listed in the validator's description.
+	desc := v.Description(context.Background())
+	for name := range enum {
+		if !strings.Contains(desc, name) {
+			t.Errorf("Description() = %q, which does not list %s", desc, name)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	v1 "chainguard.dev/sdk/proto/platform/common/v1"
+	registry "chainguard.dev/sdk/proto/platform/registry/v1"
+	"github.com/chainguard-dev/terraform-provider-chainguard/internal/validators"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &buildReportDataSource{}
+	_ datasource.DataSourceWithConfigure = &buildReportDataSource{}
+)
+
+// NewBuildReportDataSource is a helper function to simplify the provider implementation.
+func NewBuildReportDataSource() datasource.DataSource {
+	return &buildReportDataSource{}
+}
+
+// buildReportDataSource is the data source implementation.
+type buildReportDataSource struct {
+	dataSource
+}
+
+type buildReportDataSourceModel struct {
+	ID     types.String `tfsdk:"id"`
+	Repo   types.String `tfsdk:"repo"`
+	Digest types.String `tfsdk:"digest"`
+
+	MediaType    types.String `tfsdk:"media_type"`
+	Config       types.String `tfsdk:"config"`
+	LockedConfig types.String `tfsdk:"locked_config"`
+	Result       types.String `tfsdk:"result"`
+	ApkoVersion  types.String `tfsdk:"apko_version"`
+	StartedAt    types.String `tfsdk:"started_at"`
+	CompletedAt  types.String `tfsdk:"completed_at"`
+}
+
+func (m buildReportDataSourceModel) InputParams() string {
+	return fmt.Sprintf("[id=%s, repo=%s, digest=%s]", m.ID, m.Repo, m.Digest)
+}
+
+// Metadata returns the data source type name.
+func (d *buildReportDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_build_report"
+}
+
+func (d *buildReportDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.configure(ctx, req, resp)
+}
+
+// Schema defines the schema for the data source.
+func (d *buildReportDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lookup the report of an apko build, either by its UIDP or by the repo and digest of the image it built.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The UIDP of the build report.",
+				Optional:    true,
+				Computed:    true,
+				Validators: []validator.String{
+					validators.UIDP(false /* allowRootSentinel */),
+					stringvalidator.ExactlyOneOf(path.MatchRoot("repo")),
+				},
+			},
+			"repo": schema.StringAttribute{
+				Description: "The UIDP of the repository the image was built in. Requires digest.",
+				Optional:    true,
+				Validators: []validator.String{
+					validators.UIDP(false /* allowRootSentinel */),
+					stringvalidator.AlsoRequires(path.MatchRoot("digest")),
+				},
+			},
+			"digest": schema.StringAttribute{
+				Description: "The digest of the built image, either bare (sha256:...) or fully-qualified ({repo}@sha256:...). Requires repo.",
+				Optional:    true,
+				Computed:    true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("repo")),
+				},
+			},
+			"media_type": schema.StringAttribute{
+				Description: "The layer media type that was built.",
+				Computed:    true,
+			},
+			"config": schema.StringAttribute{
+				Description: "The apko configuration that was built.",
+				Computed:    true,
+			},
+			"locked_config": schema.StringAttribute{
+				Description: "The apko configuration that was built, with package versions locked.",
+				Computed:    true,
+			},
+			"result": schema.StringAttribute{
+				Description: "The result of the build.",
+				Computed:    true,
+			},
+			"apko_version": schema.StringAttribute{
+				Description: "The version of apko that performed the build.",
+				Computed:    true,
+			},
+			"started_at": schema.StringAttribute{
+				Description: "When the build started, in RFC3339 format.",
+				Computed:    true,
+			},
+			"completed_at": schema.StringAttribute{
+				Description: "When the build completed, in RFC3339 format.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *buildReportDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data buildReportDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Info(ctx, "read build report data-source request", map[string]interface{}{"input-params": data.InputParams()})
+
+	// Reports live under their repo, so look up by id the same way as
+	// chainguard_apko_build, and by digest among the repo's children.
+	filter := &v1.UIDPFilter{ChildrenOf: data.Repo.ValueString()}
+	if !data.ID.IsNull() {
+		filter = &v1.UIDPFilter{DescendantsOf: data.ID.ValueString()}
+	}
+	reports, err := d.prov.clients().Registry().Registry().ListBuildReports(ctx, &registry.BuildReportFilter{
+		Uidp: filter,
+	})
+	if err != nil {
+		resp.Diagnostics.Append(errorToDiagnostic(err, "failed to list build reports"))
+		return
+	}
+
+	var found *registry.BuildReport
+	for _, report := range reports.GetReports() {
+		if !data.ID.IsNull() && report.GetId() != data.ID.ValueString() {
+			continue
+		}
+		if !data.Digest.IsNull() && !matchesDigest(report.GetDigest(), data.Digest.ValueString()) {
+			continue
+		}
+		// The same image may have been built more than once; use the latest.
+		if found == nil || report.GetStartedAt().AsTime().After(found.GetStartedAt().AsTime()) {
+			found = report
+		}
+	}
+	if found == nil {
+		resp.Diagnostics.Append(dataNotFound("build report", "" /* extra */, data))
+		return
+	}
+
+	data.ID = types.StringValue(found.GetId())
+	data.Digest = types.StringValue(found.GetDigest())
+	data.MediaType = types.StringValue(found.GetMediaType())
+	data.Config = types.StringValue(found.GetConfig())
+	data.LockedConfig = types.StringValue(found.GetLockedConfig())
+	data.Result = types.StringValue(found.GetResult().String())
+	data.ApkoVersion = types.StringValue(found.GetApkoVersion())
+	data.StartedAt = types.StringValue(found.GetStartedAt().AsTime().Format(time.RFC3339))
+	data.CompletedAt = types.StringNull()
+	if found.GetCompletedAt() != nil {
+		data.CompletedAt = types.StringValue(found.GetCompletedAt().AsTime().Format(time.RFC3339))
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// matchesDigest reports whether the digest of a build report, which may be
+// fully-qualified, refers to the given (bare or fully-qualified) digest.
+func matchesDigest(reported, digest string) bool {
+	return reported == digest || strings.HasSuffix(reported, "@"+digest)
+}
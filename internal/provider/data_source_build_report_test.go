@@ -0,0 +1,138 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	v1 "chainguard.dev/sdk/proto/platform/common/v1"
+	registry "chainguard.dev/sdk/proto/platform/registry/v1"
+	registrytest "chainguard.dev/sdk/proto/platform/registry/v1/test"
+	platformtest "chainguard.dev/sdk/proto/platform/test"
+)
+
+func TestBuildReportDataSource_Read(t *testing.T) {
+	const (
+		repoID   = "0123456789abcdef0123456789abcdef01234567/0123456789abcdef"
+		oldID    = repoID + "/000000000000000a"
+		newID    = repoID + "/000000000000000b"
+		digest   = "sha256:deadbeef"
+		imageRef = "cgr.dev/example/repo@" + digest
+	)
+	started := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	report := func(id string, startedAt time.Time) *registry.BuildReport {
+		return &registry.BuildReport{
+			Id:           id,
+			MediaType:    "application/vnd.oci.image.layer.v1.tar+gzip",
+			Config:       "contents: {}",
+			LockedConfig: "contents: {packages: [wolfi-base=1-r5]}",
+			Result:       registry.BuildReport_Success,
+			Digest:       imageRef,
+			ApkoVersion:  "v0.20.0",
+			StartedAt:    timestamppb.New(startedAt),
+			CompletedAt:  timestamppb.New(startedAt.Add(time.Minute)),
+		}
+	}
+	clients := &platformtest.MockPlatformClients{
+		RegistryClient: registrytest.MockRegistryClients{
+			RegistryClient: registrytest.MockRegistryClient{
+				OnListBuildReports: []registrytest.BuildReportsOnList{{
+					Given: &registry.BuildReportFilter{Uidp: &v1.UIDPFilter{DescendantsOf: oldID}},
+					List:  &registry.BuildReportList{Reports: []*registry.BuildReport{report(oldID, started)}},
+				}, {
+					Given: &registry.BuildReportFilter{Uidp: &v1.UIDPFilter{ChildrenOf: repoID}},
+					List: &registry.BuildReportList{Reports: []*registry.BuildReport{
+						report(oldID, started),
+						report(newID, started.Add(time.Hour)),
+					}},
+				}},
+			},
+		},
+	}
+	want := func(id string, startedAt time.Time) buildReportDataSourceModel {
+		return buildReportDataSourceModel{
+			ID:           types.StringValue(id),
+			Digest:       types.StringValue(imageRef),
+			MediaType:    types.StringValue("application/vnd.oci.image.layer.v1.tar+gzip"),
+			Config:       types.StringValue("contents: {}"),
+			LockedConfig: types.StringValue("contents: {packages: [wolfi-base=1-r5]}"),
+			Result:       types.StringValue("Success"),
+			ApkoVersion:  types.StringValue("v0.20.0"),
+			StartedAt:    types.StringValue(startedAt.Format(time.RFC3339)),
+			CompletedAt:  types.StringValue(startedAt.Add(time.Minute).Format(time.RFC3339)),
+		}
+	}
+
+	tests := map[string]struct {
+		attrs   map[string]any
+		want    buildReportDataSourceModel
+		wantErr bool
+	}{
+		"by id": {
+			attrs: map[string]any{"id": oldID},
+			want: func() buildReportDataSourceModel {
+				m := want(oldID, started)
+				m.Repo = types.StringNull()
+				return m
+			}(),
+		},
+		"by bare digest, latest build": {
+			attrs: map[string]any{"repo": repoID, "digest": digest},
+			want: func() buildReportDataSourceModel {
+				m := want(newID, started.Add(time.Hour))
+				m.Repo = types.StringValue(repoID)
+				return m
+			}(),
+		},
+		"by image ref": {
+			attrs: map[string]any{"repo": repoID, "digest": imageRef},
+			want: func() buildReportDataSourceModel {
+				m := want(newID, started.Add(time.Hour))
+				m.Repo = types.StringValue(repoID)
+				return m
+			}(),
+		},
+		"not found": {
+			attrs:   map[string]any{"repo": repoID, "digest": "sha256:cafebabe"},
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			d := &buildReportDataSource{dataSource{prov: &providerData{client: clients}}}
+
+			config := testDataSourceConfig(t, d, test.attrs)
+
+			resp := &datasource.ReadResponse{State: tfsdk.State{Schema: config.Schema, Raw: tftypes.NewValue(config.Schema.Type().TerraformType(ctx), nil)}}
+			d.Read(ctx, datasource.ReadRequest{Config: config}, resp)
+
+			if got := resp.Diagnostics.HasError(); got != test.wantErr {
+				t.Fatalf("Read() error = %t, wanted %t: %v", got, test.wantErr, resp.Diagnostics)
+			}
+			if test.wantErr {
+				return
+			}
+			var got buildReportDataSourceModel
+			if diags := resp.State.Get(ctx, &got); diags.HasError() {
+				t.Fatalf("State.Get() = %v", diags)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Read() state mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
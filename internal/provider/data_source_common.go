@@ -11,7 +11,6 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
-	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // dataModel is an interface for data source data structures.
@@ -19,6 +18,8 @@ type dataModel interface {
 	InputParams() string
 }
 
+// dataNotFound is the error data sources report when a lookup of a single n
+// matches nothing, listing the input parameters so the lookup can be fixed.
 func dataNotFound(n, extra string, m dataModel) diag.Diagnostic {
 	detail := fmt.Sprintf("Input parameters: %s", m.InputParams())
 	if extra != "" {
@@ -30,6 +31,8 @@ func dataNotFound(n, extra string, m dataModel) diag.Diagnostic {
 	)
 }
 
+// dataTooManyFound is the error data sources report when a lookup of a single
+// n matches more than one.
 func dataTooManyFound(n, extra string, m dataModel) diag.Diagnostic {
 	detail := fmt.Sprintf("Input parameters: %s", m.InputParams())
 	if extra != "" {
@@ -59,14 +62,46 @@ func (ds *dataSource) configure(ctx context.Context, req datasource.ConfigureReq
 		return
 	}
 
-	// If the client hasn't been configured yet, configure it
-	if pd.client == nil {
-		tflog.Info(ctx, "initializing Chainguard API client (data source)")
-		if err := pd.setupClient(ctx); err != nil {
-			resp.Diagnostics.Append(errorToDiagnostic(err, "unable to setup client"))
+	ds.prov = pd
+}
+
+// ensureClient lazily sets up the shared API client. The wrappers applied when
+// registering with the provider call it before each operation that uses it.
+func (ds *dataSource) ensureClient(ctx context.Context) diag.Diagnostics {
+	return ensureClient(ctx, ds.prov)
+}
+
+// withDataSourceClient wraps each data source made by newDataSources so that
+// its API client is set up before it is read.
+func withDataSourceClient(newDataSources ...func() datasource.DataSource) []func() datasource.DataSource {
+	wrapped := make([]func() datasource.DataSource, 0, len(newDataSources))
+	for _, newDataSource := range newDataSources {
+		wrapped = append(wrapped, func() datasource.DataSource {
+			return clientDataSource{newDataSource()}
+		})
+	}
+	return wrapped
+}
+
+// clientDataSource sets up the API client of the data source it wraps before
+// it is read.
+type clientDataSource struct {
+	datasource.DataSource
+}
+
+var _ datasource.DataSourceWithConfigure = clientDataSource{}
+
+func (d clientDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if dc, ok := d.DataSource.(datasource.DataSourceWithConfigure); ok {
+		dc.Configure(ctx, req, resp)
+	}
+}
+
+func (d clientDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	if cu, ok := d.DataSource.(clientUser); ok {
+		if resp.Diagnostics.Append(cu.ensureClient(ctx)...); resp.Diagnostics.HasError() {
 			return
 		}
 	}
-
-	ds.prov = pd
+	d.DataSource.Read(ctx, req, resp)
 }
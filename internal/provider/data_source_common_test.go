@@ -0,0 +1,133 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// testDataSourceConfig builds a tfsdk.Config for d with the given top-level attributes set,
+// and every other attribute null.
+func testDataSourceConfig(t *testing.T, d datasource.DataSource, attrs map[string]any) tfsdk.Config {
+	t.Helper()
+	ctx := context.Background()
+
+	var sresp datasource.SchemaResponse
+	d.Schema(ctx, datasource.SchemaRequest{}, &sresp)
+	if sresp.Diagnostics.HasError() {
+		t.Fatalf("Schema() = %v", sresp.Diagnostics)
+	}
+	typ := sresp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	nulls := make(map[string]tftypes.Value, len(typ.AttributeTypes))
+	for name, at := range typ.AttributeTypes {
+		nulls[name] = tftypes.NewValue(at, nil)
+	}
+	// Config has no setters, so populate it by way of State.
+	state := tfsdk.State{Schema: sresp.Schema, Raw: tftypes.NewValue(typ, nulls)}
+	for name, v := range attrs {
+		if diags := state.SetAttribute(ctx, path.Root(name), v); diags.HasError() {
+			t.Fatalf("State.SetAttribute(%s) = %v", name, diags)
+		}
+	}
+	return tfsdk.Config{Schema: state.Schema, Raw: state.Raw}
+}
+
+func TestDataNotFound(t *testing.T) {
+	m := identityDataSourceModel{
+		Issuer:  types.StringValue("https://issuer.example.com"),
+		Subject: types.StringValue("alice"),
+	}
+
+	tests := map[string]struct {
+		d           diag.Diagnostic
+		wantSummary string
+		wantDetail  string
+	}{
+		"not found": {
+			d:           dataNotFound("identity", "" /* extra */, m),
+			wantSummary: "identity not found",
+			wantDetail:  `Input parameters: [issuer="https://issuer.example.com", subject="alice"]`,
+		},
+		"not found with extra": {
+			d:           dataNotFound("identity", "Check the issuer.", m),
+			wantSummary: "identity not found",
+			wantDetail:  "Input parameters: [issuer=\"https://issuer.example.com\", subject=\"alice\"]\nCheck the issuer.",
+		},
+		"too many found": {
+			d:           dataTooManyFound("identity", "" /* extra */, m),
+			wantSummary: "more than one identity found matching input",
+			wantDetail:  `Input parameters: [issuer="https://issuer.example.com", subject="alice"]`,
+		},
+		"too many found with extra": {
+			d:           dataTooManyFound("identity", "Narrow the query.", m),
+			wantSummary: "more than one identity found matching input",
+			wantDetail:  "Input parameters: [issuer=\"https://issuer.example.com\", subject=\"alice\"]\nNarrow the query.",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := test.d.Severity(); got != diag.SeverityError {
+				t.Errorf("Severity() = %v, wanted error", got)
+			}
+			if got := test.d.Summary(); got != test.wantSummary {
+				t.Errorf("Summary() = %q, wanted %q", got, test.wantSummary)
+			}
+			if got := test.d.Detail(); got != test.wantDetail {
+				t.Errorf("Detail() = %q, wanted %q", got, test.wantDetail)
+			}
+		})
+	}
+}
+
+func TestDataModels_InputParams(t *testing.T) {
+	// Listing a model here fails to compile unless it implements dataModel.
+	models := map[string]dataModel{
+		"chainguard_build_report":     buildReportDataSourceModel{},
+		"chainguard_effective_access": effectiveAccessDataSourceModel{},
+		"chainguard_exists":           existsDataSourceModel{},
+		"chainguard_group":            groupDataSourceModel{},
+		"chainguard_identity":         identityDataSourceModel{},
+		"chainguard_identity_check":   identityCheckDataSourceModel{Token: types.StringValue("secret-token")},
+		"chainguard_package":          packageDataSourceModel{},
+		"chainguard_package_metadata": packageMetadataDataSourceModel{},
+		"chainguard_ping":             pingDataSourceModel{},
+		"chainguard_role":             roleDataSourceModel{},
+		"chainguard_roles":            rolesDataSourceModel{},
+		"chainguard_token":            tokenDataSourceModel{Token: types.StringValue("secret-token")},
+		"chainguard_versions":         versionsDataSourceModel{},
+	}
+
+	// Every data source must have its model listed.
+	ctx := context.Background()
+	p := &Provider{}
+	for _, newDataSource := range p.DataSources(ctx) {
+		var resp datasource.MetadataResponse
+		newDataSource().Metadata(ctx, datasource.MetadataRequest{ProviderTypeName: "chainguard"}, &resp)
+		if _, ok := models[resp.TypeName]; !ok {
+			t.Errorf("data source %s has no model listed implementing InputParams()", resp.TypeName)
+		}
+	}
+
+	for name, m := range models {
+		got := m.InputParams()
+		if !strings.HasPrefix(got, "[") || !strings.HasSuffix(got, "]") {
+			t.Errorf("%s InputParams() = %q, wanted a bracketed list", name, got)
+		}
+		if strings.Contains(got, "secret-token") {
+			t.Errorf("%s InputParams() = %q, which includes a sensitive value", name, got)
+		}
+	}
+}
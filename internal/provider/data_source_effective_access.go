@@ -0,0 +1,169 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	common "chainguard.dev/sdk/proto/platform/common/v1"
+	iam "chainguard.dev/sdk/proto/platform/iam/v1"
+	"chainguard.dev/sdk/uidp"
+	"github.com/chainguard-dev/terraform-provider-chainguard/internal/validators"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &effectiveAccessDataSource{}
+	_ datasource.DataSourceWithConfigure = &effectiveAccessDataSource{}
+)
+
+// NewEffectiveAccessDataSource is a helper function to simplify the provider implementation.
+func NewEffectiveAccessDataSource() datasource.DataSource {
+	return &effectiveAccessDataSource{}
+}
+
+// effectiveAccessDataSource is the data source implementation.
+type effectiveAccessDataSource struct {
+	dataSource
+}
+
+type effectiveAccessDataSourceModel struct {
+	Identity     types.String `tfsdk:"identity"`
+	Group        types.String `tfsdk:"group"`
+	Rolebindings types.List   `tfsdk:"rolebindings"`
+	Roles        types.List   `tfsdk:"roles"`
+	Capabilities types.List   `tfsdk:"capabilities"`
+}
+
+func (m effectiveAccessDataSourceModel) InputParams() string {
+	return fmt.Sprintf("[identity=%s, group=%s]", m.Identity, m.Group)
+}
+
+// Metadata returns the data source type name.
+func (d *effectiveAccessDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_effective_access"
+}
+
+func (d *effectiveAccessDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.configure(ctx, req, resp)
+}
+
+// Schema defines the schema for the data source.
+func (d *effectiveAccessDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Resolve the capabilities an identity effectively has in a group, through its rolebindings in the group and the group's ancestors.",
+		Attributes: map[string]schema.Attribute{
+			"identity": schema.StringAttribute{
+				Description: "The UIDP of the identity.",
+				Required:    true,
+				Validators:  []validator.String{validators.UIDP(false /* allowRootSentinel */)},
+			},
+			"group": schema.StringAttribute{
+				Description: "The UIDP of the group.",
+				Required:    true,
+				Validators:  []validator.String{validators.UIDP(false /* allowRootSentinel */)},
+			},
+			"rolebindings": schema.ListAttribute{
+				Description: "The UIDPs of the identity's rolebindings which apply in the group, sorted.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"roles": schema.ListAttribute{
+				Description: "The UIDPs of the roles bound to the identity in the group, sorted and deduplicated.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"capabilities": schema.ListAttribute{
+				Description: "The union of the capabilities of the roles, sorted and deduplicated.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *effectiveAccessDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data effectiveAccessDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Info(ctx, "read effective access data-source request", map[string]interface{}{"input-params": data.InputParams()})
+
+	// Bindings in a group apply to all of its descendants, so collect the
+	// identity's bindings in the group and each of its ancestors.
+	identity := data.Identity.ValueString()
+	var bindings, roleIDs []string
+	for _, g := range uidp.Ancestry(data.Group.ValueString()) {
+		list, err := d.prov.clients().IAM().RoleBindings().List(ctx, &iam.RoleBindingFilter{
+			Uidp: &common.UIDPFilter{ChildrenOf: g},
+		})
+		if err != nil {
+			resp.Diagnostics.Append(errorToDiagnostic(err, fmt.Sprintf("failed to list rolebindings in group %q", g)))
+			return
+		}
+		for _, b := range list.GetItems() {
+			if b.GetIdentity() != identity {
+				continue
+			}
+			bindings = append(bindings, b.GetId())
+			roleIDs = append(roleIDs, b.GetRole().GetId())
+		}
+	}
+	roleIDs = sortedUnique(roleIDs)
+
+	var caps []string
+	for _, id := range roleIDs {
+		roles, err := d.prov.clients().IAM().Roles().List(ctx, &iam.RoleFilter{Id: id})
+		if err != nil {
+			resp.Diagnostics.Append(errorToDiagnostic(err, fmt.Sprintf("failed to list role %q", id)))
+			return
+		}
+		if len(roles.GetItems()) != 1 {
+			resp.Diagnostics.AddError("failed to resolve role", fmt.Sprintf("found %d roles with id %q", len(roles.GetItems()), id))
+			return
+		}
+		caps = append(caps, roles.GetItems()[0].GetCapabilities()...)
+	}
+
+	var diags diag.Diagnostics
+	data.Rolebindings, diags = types.ListValueFrom(ctx, types.StringType, sortedUnique(bindings))
+	resp.Diagnostics.Append(diags...)
+	data.Roles, diags = types.ListValueFrom(ctx, types.StringType, roleIDs)
+	resp.Diagnostics.Append(diags...)
+	data.Capabilities, diags = types.ListValueFrom(ctx, types.StringType, sortedUnique(caps))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// sortedUnique returns the distinct values of s in sorted order, and never nil
+// so lists are empty rather than null.
+func sortedUnique(s []string) []string {
+	seen := make(map[string]struct{}, len(s))
+	out := make([]string, 0, len(s))
+	for _, v := range s {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		out = append(out, v)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+
+	common "chainguard.dev/sdk/proto/platform/common/v1"
+	iam "chainguard.dev/sdk/proto/platform/iam/v1"
+	iamtest "chainguard.dev/sdk/proto/platform/iam/v1/test"
+	platformtest "chainguard.dev/sdk/proto/platform/test"
+)
+
+func TestEffectiveAccessDataSource_Read(t *testing.T) {
+	const (
+		root     = "0123456789abcdef0123456789abcdef01234567"
+		group    = root + "/0123456789abcdef"
+		identity = root + "/1111111111111111"
+		other    = root + "/2222222222222222"
+		viewer   = root + "/000000000000000a"
+		editor   = root + "/000000000000000b"
+		owner    = root + "/000000000000000c"
+	)
+	binding := func(id, identity, role string) *iam.RoleBindingList_Binding {
+		return &iam.RoleBindingList_Binding{Id: id, Identity: identity, Role: &iam.Role{Id: role}}
+	}
+	role := func(id string, caps ...string) iamtest.RoleOnList {
+		return iamtest.RoleOnList{
+			Given: &iam.RoleFilter{Id: id},
+			List:  &iam.RoleList{Items: []*iam.Role{{Id: id, Capabilities: caps}}},
+		}
+	}
+	// The identity is bound to viewer in the root and again, with editor, in
+	// the group. Another identity's owner binding must not count.
+	rootBindings := iamtest.RoleBindingOnList{
+		Given: &iam.RoleBindingFilter{Uidp: &common.UIDPFilter{ChildrenOf: root}},
+		List: &iam.RoleBindingList{Items: []*iam.RoleBindingList_Binding{
+			binding(root+"/b0", identity, viewer),
+			binding(root+"/b1", other, owner),
+		}},
+	}
+	groupBindings := iamtest.RoleBindingOnList{
+		Given: &iam.RoleBindingFilter{Uidp: &common.UIDPFilter{ChildrenOf: group}},
+		List: &iam.RoleBindingList{Items: []*iam.RoleBindingList_Binding{
+			binding(group+"/b2", identity, editor),
+			binding(group+"/b3", identity, viewer),
+		}},
+	}
+	roles := []iamtest.RoleOnList{
+		role(viewer, "groups.list", "repo.list"),
+		role(editor, "repo.create", "groups.list", "repo.list"),
+		role(owner, "groups.delete"),
+	}
+
+	tests := map[string]struct {
+		bindings []iamtest.RoleBindingOnList
+		wantErr  bool
+		want     effectiveAccessResult
+	}{
+		"overlapping roles": {
+			bindings: []iamtest.RoleBindingOnList{rootBindings, groupBindings},
+			want: effectiveAccessResult{
+				Rolebindings: []string{group + "/b2", group + "/b3", root + "/b0"},
+				Roles:        []string{viewer, editor},
+				Capabilities: []string{"groups.list", "repo.create", "repo.list"},
+			},
+		},
+		"no bindings": {
+			bindings: []iamtest.RoleBindingOnList{
+				{Given: rootBindings.Given, List: &iam.RoleBindingList{}},
+				{Given: groupBindings.Given, List: &iam.RoleBindingList{}},
+			},
+			want: effectiveAccessResult{Rolebindings: []string{}, Roles: []string{}, Capabilities: []string{}},
+		},
+		"list error": {
+			bindings: []iamtest.RoleBindingOnList{
+				rootBindings,
+				{Given: groupBindings.Given, Error: errors.New("boom")},
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			clients := &platformtest.MockPlatformClients{
+				IAMClient: iamtest.MockIAMClient{
+					RoleBindingsClient: iamtest.MockRoleBindingsClient{OnList: test.bindings},
+					RolesClient:        iamtest.MockRolesClient{OnList: roles},
+				},
+			}
+
+			ctx := context.Background()
+			d := &effectiveAccessDataSource{dataSource{prov: &providerData{client: clients}}}
+
+			config := testDataSourceConfig(t, d, map[string]any{"identity": identity, "group": group})
+
+			resp := &datasource.ReadResponse{State: tfsdk.State{Schema: config.Schema, Raw: config.Raw}}
+			d.Read(ctx, datasource.ReadRequest{Config: config}, resp)
+			if got := resp.Diagnostics.HasError(); got != test.wantErr {
+				t.Fatalf("Read() error = %t, wanted %t: %v", got, test.wantErr, resp.Diagnostics)
+			}
+			if test.wantErr {
+				return
+			}
+
+			var got effectiveAccessResult
+			for attr, dst := range map[string]*[]string{
+				"rolebindings": &got.Rolebindings,
+				"roles":        &got.Roles,
+				"capabilities": &got.Capabilities,
+			} {
+				if diags := resp.State.GetAttribute(ctx, path.Root(attr), dst); diags.HasError() {
+					t.Fatalf("GetAttribute(%s) = %v", attr, diags)
+				}
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Read() (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type effectiveAccessResult struct {
+	Rolebindings, Roles, Capabilities []string
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	iam "chainguard.dev/sdk/proto/platform/iam/v1"
+	registry "chainguard.dev/sdk/proto/platform/registry/v1"
+	"github.com/chainguard-dev/terraform-provider-chainguard/internal/validators"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &existsDataSource{}
+	_ datasource.DataSourceWithConfigure = &existsDataSource{}
+)
+
+// NewExistsDataSource is a helper function to simplify the provider implementation.
+func NewExistsDataSource() datasource.DataSource {
+	return &existsDataSource{}
+}
+
+// existsDataSource is the data source implementation.
+type existsDataSource struct {
+	dataSource
+}
+
+type existsDataSourceModel struct {
+	Kind   types.String `tfsdk:"kind"`
+	ID     types.String `tfsdk:"id"`
+	Exists types.Bool   `tfsdk:"exists"`
+}
+
+func (d existsDataSourceModel) InputParams() string {
+	return fmt.Sprintf("[kind=%s, id=%s]", d.Kind, d.ID)
+}
+
+// existsKinds maps each supported kind to a function returning the number
+// of resources of that kind with the given id.
+var existsKinds = map[string]func(ctx context.Context, d *existsDataSource, id string) (int, error){
+	"group": func(ctx context.Context, d *existsDataSource, id string) (int, error) {
+		list, err := d.prov.clients().IAM().Groups().List(ctx, &iam.GroupFilter{Id: id})
+		return len(list.GetItems()), err
+	},
+	"identity": func(ctx context.Context, d *existsDataSource, id string) (int, error) {
+		list, err := d.prov.clients().IAM().Identities().List(ctx, &iam.IdentityFilter{Id: id})
+		return len(list.GetItems()), err
+	},
+	"repo": func(ctx context.Context, d *existsDataSource, id string) (int, error) {
+		list, err := d.prov.clients().Registry().Registry().ListRepos(ctx, &registry.RepoFilter{Id: id})
+		return len(list.GetItems()), err
+	},
+}
+
+// Metadata returns the data source type name.
+func (d *existsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_exists"
+}
+
+func (d *existsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.configure(ctx, req, resp)
+}
+
+// Schema defines the schema for the data source.
+func (d *existsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Check whether a resource with the given id exists, without failing when it does not.",
+		Attributes: map[string]schema.Attribute{
+			"kind": schema.StringAttribute{
+				Description: fmt.Sprintf("The kind of resource to check for, one of: %v.", sortedKeys(existsKinds)),
+				Required:    true,
+				Validators:  []validator.String{stringvalidator.OneOf(sortedKeys(existsKinds)...)},
+			},
+			"id": schema.StringAttribute{
+				Description: "The UIDP of the resource to check for.",
+				Required:    true,
+				Validators:  []validator.String{validators.UIDP(false /* allowRootSentinel */)},
+			},
+			"exists": schema.BoolAttribute{
+				Description: "Whether the resource exists and is visible to the caller.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *existsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data existsDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Info(ctx, "read exists data-source request", map[string]interface{}{"input-params": data.InputParams()})
+
+	count, err := existsKinds[data.Kind.ValueString()](ctx, d, data.ID.ValueString())
+	// Only a missing resource is an answer, auth and transport errors are not.
+	if err != nil && status.Code(err) != codes.NotFound {
+		resp.Diagnostics.Append(errorToDiagnostic(err, fmt.Sprintf("failed to list %s", data.Kind.ValueString())))
+		return
+	}
+	data.Exists = types.BoolValue(count > 0)
+
+	// Set state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	iam "chainguard.dev/sdk/proto/platform/iam/v1"
+	iamtest "chainguard.dev/sdk/proto/platform/iam/v1/test"
+	registry "chainguard.dev/sdk/proto/platform/registry/v1"
+	registrytest "chainguard.dev/sdk/proto/platform/registry/v1/test"
+	platformtest "chainguard.dev/sdk/proto/platform/test"
+)
+
+func TestExistsDataSource(t *testing.T) {
+	const (
+		found   = "0123456789abcdef0123456789abcdef01234567/000000000000000a"
+		missing = "0123456789abcdef0123456789abcdef01234567/000000000000000b"
+		gone    = "0123456789abcdef0123456789abcdef01234567/000000000000000c"
+		denied  = "0123456789abcdef0123456789abcdef01234567/000000000000000d"
+	)
+	notFound := status.Error(codes.NotFound, "not found")
+	permissionDenied := status.Error(codes.PermissionDenied, "permission denied")
+	clients := &platformtest.MockPlatformClients{
+		IAMClient: iamtest.MockIAMClient{
+			GroupsClient: iamtest.MockGroupsClient{OnList: []iamtest.GroupOnList{
+				{Given: &iam.GroupFilter{Id: found}, List: &iam.GroupList{Items: []*iam.Group{{Id: found}}}},
+				{Given: &iam.GroupFilter{Id: missing}, List: &iam.GroupList{}},
+				{Given: &iam.GroupFilter{Id: gone}, Error: notFound},
+				{Given: &iam.GroupFilter{Id: denied}, Error: permissionDenied},
+			}},
+			IdentitiesClient: iamtest.MockIdentitiesClient{OnList: []iamtest.IdentityOnList{
+				{Given: &iam.IdentityFilter{Id: found}, List: &iam.IdentityList{Items: []*iam.Identity{{Id: found}}}},
+				{Given: &iam.IdentityFilter{Id: missing}, List: &iam.IdentityList{}},
+				{Given: &iam.IdentityFilter{Id: gone}, Error: notFound},
+				{Given: &iam.IdentityFilter{Id: denied}, Error: permissionDenied},
+			}},
+		},
+		RegistryClient: registrytest.MockRegistryClients{
+			RegistryClient: registrytest.MockRegistryClient{OnListRepos: []registrytest.ReposOnList{
+				{Given: &registry.RepoFilter{Id: found}, List: &registry.RepoList{Items: []*registry.Repo{{Id: found}}}},
+				{Given: &registry.RepoFilter{Id: missing}, List: &registry.RepoList{}},
+				{Given: &registry.RepoFilter{Id: gone}, Error: notFound},
+				{Given: &registry.RepoFilter{Id: denied}, Error: permissionDenied},
+			}},
+		},
+	}
+
+	tests := map[string]struct {
+		id      string
+		want    bool
+		wantErr bool
+	}{
+		"exists":         {id: found, want: true},
+		"empty list":     {id: missing},
+		"not found":      {id: gone},
+		"other failures": {id: denied, wantErr: true},
+	}
+
+	for _, kind := range sortedKeys(existsKinds) {
+		for name, test := range tests {
+			t.Run(kind+"/"+name, func(t *testing.T) {
+				ctx := context.Background()
+				d := &existsDataSource{dataSource{prov: &providerData{client: clients}}}
+
+				config := testDataSourceConfig(t, d, map[string]any{"kind": kind, "id": test.id})
+
+				resp := &datasource.ReadResponse{State: tfsdk.State{Schema: config.Schema, Raw: config.Raw}}
+				d.Read(ctx, datasource.ReadRequest{Config: config}, resp)
+				if got := resp.Diagnostics.HasError(); got != test.wantErr {
+					t.Fatalf("Read() error = %t, wanted %t: %v", got, test.wantErr, resp.Diagnostics)
+				}
+				if test.wantErr {
+					return
+				}
+
+				var got bool
+				if diags := resp.State.GetAttribute(ctx, path.Root("exists"), &got); diags.HasError() {
+					t.Fatalf("GetAttribute(exists) = %v", diags)
+				}
+				if got != test.want {
+					t.Errorf("exists = %t, wanted %t", got, test.want)
+				}
+			})
+		}
+	}
+}
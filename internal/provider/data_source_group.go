@@ -91,7 +91,7 @@ func (d *groupDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	tflog.Info(ctx, fmt.Sprintf("read group data-source request: name=%s, parent_id=%s", data.Name, data.ParentID))
+	tflog.Info(ctx, "read group data-source request", map[string]interface{}{"input-params": data.InputParams()})
 
 	uf := &common.UIDPFilter{}
 	if data.ParentID.ValueString() != "" && data.ParentID.ValueString() != "/" {
@@ -102,7 +102,7 @@ func (d *groupDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		Name: data.Name.ValueString(),
 		Uidp: uf,
 	}
-	groupList, err := d.prov.client.IAM().Groups().List(ctx, f)
+	groupList, err := d.prov.clients().IAM().Groups().List(ctx, f)
 	if err != nil {
 		resp.Diagnostics.Append(errorToDiagnostic(err, "failed to list groups"))
 		return
@@ -42,6 +42,7 @@ type identityDataSourceModel struct {
 	ID      types.String `tfsdk:"id"`
 	Issuer  types.String `tfsdk:"issuer"`
 	Subject types.String `tfsdk:"subject"`
+	Summary types.String `tfsdk:"summary"`
 }
 
 func (m identityDataSourceModel) InputParams() string {
@@ -80,6 +81,10 @@ func (d *identityDataSource) Schema(_ context.Context, _ datasource.SchemaReques
 					stringvalidator.LengthAtLeast(1),
 				},
 			},
+			"summary": schema.StringAttribute{
+				Description: "A human-readable description of who may assume this identity, derived from its relationship.",
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -91,13 +96,13 @@ func (d *identityDataSource) Read(ctx context.Context, req datasource.ReadReques
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	tflog.Info(ctx, "read identity data-source request", map[string]interface{}{"config": data})
+	tflog.Info(ctx, "read identity data-source request", map[string]interface{}{"input-params": data.InputParams()})
 
 	lr := &iam.LookupRequest{
 		Subject: data.Subject.ValueString(),
 		Issuer:  data.Issuer.ValueString(),
 	}
-	id, err := d.prov.client.IAM().Identities().Lookup(ctx, lr)
+	id, err := d.prov.clients().IAM().Identities().Lookup(ctx, lr)
 	if err != nil {
 		if status.Code(err) == codes.NotFound {
 			resp.Diagnostics.Append(dataNotFound("identity", "" /* extra */, data))
@@ -107,6 +112,7 @@ func (d *identityDataSource) Read(ctx context.Context, req datasource.ReadReques
 	} else {
 		// Set state
 		data.ID = types.StringValue(id.Id)
+		data.Summary = types.StringValue(identitySummary(id))
 		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 	}
 }
@@ -0,0 +1,123 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"chainguard.dev/sdk/sts"
+	"github.com/chainguard-dev/terraform-provider-chainguard/internal/validators"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &identityCheckDataSource{}
+	_ datasource.DataSourceWithConfigure = &identityCheckDataSource{}
+)
+
+// newExchanger is overridden in tests.
+var newExchanger = sts.New
+
+// NewIdentityCheckDataSource is a helper function to simplify the provider implementation.
+func NewIdentityCheckDataSource() datasource.DataSource {
+	return &identityCheckDataSource{}
+}
+
+// identityCheckDataSource is the data source implementation.
+type identityCheckDataSource struct {
+	dataSource
+}
+
+type identityCheckDataSourceModel struct {
+	IdentityID types.String `tfsdk:"identity_id"`
+	Token      types.String `tfsdk:"token"`
+	Allowed    types.Bool   `tfsdk:"allowed"`
+	Reason     types.String `tfsdk:"reason"`
+}
+
+// InputParams omits the token, which is sensitive.
+func (m identityCheckDataSourceModel) InputParams() string {
+	return fmt.Sprintf("[identity_id=%s]", m.IdentityID)
+}
+
+// Metadata returns the data source type name.
+func (d *identityCheckDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_identity_check"
+}
+
+func (d *identityCheckDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.configure(ctx, req, resp)
+}
+
+// Schema defines the schema for the data source.
+func (d *identityCheckDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Check whether an OIDC token may assume an identity, by attempting the token exchange with the Chainguard STS. An allowed check really performs the exchange, as the identity would, though the token it issues is discarded. The OIDC token is stored in plaintext in the Terraform plan and state, so only use short-lived tokens.",
+		Attributes: map[string]schema.Attribute{
+			"identity_id": schema.StringAttribute{
+				Description: "The UIDP of the identity to assume.",
+				Required:    true,
+				Validators:  []validator.String{validators.UIDP(false /* allowRootSentinel */)},
+			},
+			"token": schema.StringAttribute{
+				Description: "The OIDC token to exchange for the identity. It is marked sensitive, which only hides it from CLI output: like any data source input, it is stored in plaintext in the plan and state. Use a short-lived token.",
+				Required:    true,
+				Sensitive:   true,
+			},
+			"allowed": schema.BoolAttribute{
+				Description: "Whether the token may assume the identity.",
+				Computed:    true,
+			},
+			"reason": schema.StringAttribute{
+				Description: "Why the exchange was denied, as reported by the STS. Empty when allowed.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *identityCheckDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	// The exchange goes straight to the STS, so the API client is not needed.
+	if d.prov == nil {
+		resp.Diagnostics.AddError("provider not configured", "The Chainguard provider was not configured. Please report this issue to the provider developers.")
+		return
+	}
+
+	var data identityCheckDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx = maskSensitive(ctx, req.Config.Schema.AttributeAtTerraformPath, req.Config.Raw)
+	tflog.Info(ctx, "read identity check data-source request", map[string]interface{}{"input-params": data.InputParams()})
+
+	cfg := d.prov.loginConfig
+	xchg := newExchanger(cfg.Issuer, cfg.Audience, sts.WithUserAgent(cfg.UserAgent))
+	_, err := xchg.Exchange(ctx, data.Token.ValueString(), sts.WithIdentity(data.IdentityID.ValueString()))
+	switch code := status.Code(err); code {
+	case codes.OK:
+		data.Allowed = types.BoolValue(true)
+		data.Reason = types.StringValue("")
+	case codes.PermissionDenied, codes.Unauthenticated:
+		data.Allowed = types.BoolValue(false)
+		data.Reason = types.StringValue(status.Convert(err).Message())
+	default:
+		resp.Diagnostics.Append(errorToDiagnostic(err, "failed to exchange token"))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
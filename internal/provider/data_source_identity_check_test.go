@@ -0,0 +1,103 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"chainguard.dev/sdk/sts"
+	"github.com/chainguard-dev/terraform-provider-chainguard/internal/token"
+)
+
+// fakeExchanger returns err from every exchange.
+type fakeExchanger struct {
+	err error
+}
+
+func (f fakeExchanger) Exchange(context.Context, string, ...sts.ExchangerOption) (sts.TokenPair, error) {
+	return sts.TokenPair{AccessToken: "access"}, f.err
+}
+
+func (f fakeExchanger) Refresh(context.Context, string, ...sts.ExchangerOption) (string, string, error) {
+	return "", "", f.err
+}
+
+func TestIdentityCheckDataSource_Read(t *testing.T) {
+	const identityID = "0123456789abcdef0123456789abcdef01234567/0123456789abcdef"
+
+	tests := map[string]struct {
+		err         error
+		wantAllowed bool
+		wantReason  string
+		wantErr     bool
+	}{
+		"allowed": {
+			wantAllowed: true,
+		},
+		"denied": {
+			err:        status.Error(codes.PermissionDenied, `invalid "email" claim`),
+			wantReason: `invalid "email" claim`,
+		},
+		"unauthenticated": {
+			err:        status.Error(codes.Unauthenticated, "token expired"),
+			wantReason: "token expired",
+		},
+		"unavailable": {
+			err:     errors.New("connection refused"),
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var gotIssuer, gotAudience string
+			newExchanger = func(issuer, audience string, _ ...sts.ExchangerOption) sts.Exchanger {
+				gotIssuer, gotAudience = issuer, audience
+				return fakeExchanger{err: test.err}
+			}
+			t.Cleanup(func() { newExchanger = sts.New })
+
+			ctx := context.Background()
+			d := &identityCheckDataSource{dataSource{prov: &providerData{loginConfig: token.LoginConfig{
+				Issuer:   "https://issuer.example.com",
+				Audience: "https://console-api.example.com",
+			}}}}
+
+			config := testDataSourceConfig(t, d, map[string]any{"identity_id": identityID, "token": "oidc-token"})
+
+			resp := &datasource.ReadResponse{State: tfsdk.State{Schema: config.Schema, Raw: tftypes.NewValue(config.Schema.Type().TerraformType(ctx), nil)}}
+			d.Read(ctx, datasource.ReadRequest{Config: config}, resp)
+
+			if got := resp.Diagnostics.HasError(); got != test.wantErr {
+				t.Fatalf("Read() error = %t, wanted %t: %v", got, test.wantErr, resp.Diagnostics)
+			}
+			if gotIssuer != "https://issuer.example.com" || gotAudience != "https://console-api.example.com" {
+				t.Errorf("exchanger issuer, audience = %q, %q", gotIssuer, gotAudience)
+			}
+			if test.wantErr {
+				return
+			}
+			var got identityCheckDataSourceModel
+			if diags := resp.State.Get(ctx, &got); diags.HasError() {
+				t.Fatalf("State.Get() = %v", diags)
+			}
+			if got.Allowed.ValueBool() != test.wantAllowed {
+				t.Errorf("allowed = %t, wanted %t", got.Allowed.ValueBool(), test.wantAllowed)
+			}
+			if got.Reason.ValueString() != test.wantReason {
+				t.Errorf("reason = %q, wanted %q", got.Reason.ValueString(), test.wantReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	registry "chainguard.dev/sdk/proto/platform/registry/v1"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &packageDataSource{}
+	_ datasource.DataSourceWithConfigure = &packageDataSource{}
+)
+
+// NewPackageDataSource is a helper function to simplify the provider implementation.
+func NewPackageDataSource() datasource.DataSource {
+	return &packageDataSource{}
+}
+
+// packageDataSource is the data source implementation.
+type packageDataSource struct {
+	dataSource
+}
+
+type packageDataSourceModel struct {
+	Package types.String `tfsdk:"package"`
+	Variant types.String `tfsdk:"variant"`
+
+	Exists            types.Bool   `tfsdk:"exists"`
+	LatestVersion     types.String `tfsdk:"latest_version"`
+	VariantsAvailable []string     `tfsdk:"variants_available"`
+}
+
+func (m packageDataSourceModel) InputParams() string {
+	return fmt.Sprintf("[package=%s, variant=%s]", m.Package, m.Variant)
+}
+
+// Metadata returns the data source type name.
+func (d *packageDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_package"
+}
+
+func (d *packageDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.configure(ctx, req, resp)
+}
+
+// Schema defines the schema for the data source.
+func (d *packageDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Check whether a package, or a variant of it, exists. Use chainguard_versions for its full version stream.",
+		Attributes: map[string]schema.Attribute{
+			"package": schema.StringAttribute{
+				Description: "The name of the package to lookup.",
+				Required:    true,
+			},
+			"variant": schema.StringAttribute{
+				Description: "A package variant (e.g. fips).",
+				Optional:    true,
+				Validators:  []validator.String{Variant()},
+			},
+			"exists": schema.BoolAttribute{
+				Description: "Whether any version of the package, or of its variant when set, exists.",
+				Computed:    true,
+			},
+			"latest_version": schema.StringAttribute{
+				Description: "The latest version of the package, or of its variant when set. Empty when it does not exist.",
+				Computed:    true,
+			},
+			"variants_available": schema.ListAttribute{
+				Description: "The variants available for any version of the package.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *packageDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data packageDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Info(ctx, "read package data-source request", map[string]interface{}{"input-params": data.InputParams()})
+
+	v, err := d.prov.clients().Registry().Registry().GetPackageVersionMetadata(ctx, &registry.PackageVersionMetadataRequest{
+		Package: data.Package.ValueString(),
+	})
+	// A package without a version stream doesn't exist, which is not an error.
+	if err != nil && status.Code(err) != codes.NotFound {
+		resp.Diagnostics.Append(errorToDiagnostic(err, "failed to get package version metadata"))
+		return
+	}
+
+	// Versions are ordered latest first, with EOL versions after the rest.
+	fips := data.Variant.ValueString() == "fips"
+	latest := ""
+	variants := []string{}
+	for _, pv := range append(v.GetVersions(), v.GetEolVersions()...) {
+		if pv.GetFips() && len(variants) == 0 {
+			variants = append(variants, "fips")
+		}
+		// Match how chainguard_versions decides whether a version is usable.
+		if latest == "" && !pv.GetEolBroken() && ((!fips && pv.GetExists()) || (fips && pv.GetFips())) {
+			latest = pv.GetVersion()
+		}
+	}
+
+	data.Exists = types.BoolValue(latest != "")
+	data.LatestVersion = types.StringValue(latest)
+	data.VariantsAvailable = variants
+
+	// Set state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	registry "chainguard.dev/sdk/proto/platform/registry/v1"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &packageMetadataDataSource{}
+	_ datasource.DataSourceWithConfigure = &packageMetadataDataSource{}
+)
+
+// NewPackageMetadataDataSource is a helper function to simplify the provider implementation.
+func NewPackageMetadataDataSource() datasource.DataSource {
+	return &packageMetadataDataSource{}
+}
+
+// packageMetadataDataSource is the data source implementation.
+type packageMetadataDataSource struct {
+	dataSource
+}
+
+// packageMetadataDataSourceModel mirrors registry.PackageVersionMetadata
+// without the transformations applied by chainguard_versions.
+type packageMetadataDataSourceModel struct {
+	Package types.String `tfsdk:"package"`
+
+	GracePeriodMonths    types.Int64                   `tfsdk:"grace_period_months"`
+	LastUpdatedTimestamp types.String                  `tfsdk:"last_updated_timestamp"`
+	LatestVersion        types.String                  `tfsdk:"latest_version"`
+	Versions             []packageMetadataVersionModel `tfsdk:"versions"`
+	EolVersions          []packageMetadataVersionModel `tfsdk:"eol_versions"`
+}
+
+type packageMetadataVersionModel struct {
+	EolDate     types.String `tfsdk:"eol_date"`
+	EolBroken   types.Bool   `tfsdk:"eol_broken"`
+	Exists      types.Bool   `tfsdk:"exists"`
+	Fips        types.Bool   `tfsdk:"fips"`
+	Lts         types.String `tfsdk:"lts"`
+	ReleaseDate types.String `tfsdk:"release_date"`
+	Version     types.String `tfsdk:"version"`
+}
+
+func (m packageMetadataDataSourceModel) InputParams() string {
+	return fmt.Sprintf("[package=%s]", m.Package)
+}
+
+// Metadata returns the data source type name.
+func (d *packageMetadataDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_package_metadata"
+}
+
+func (d *packageMetadataDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.configure(ctx, req, resp)
+}
+
+// Schema defines the schema for the data source.
+func (d *packageMetadataDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	versionAttrs := map[string]schema.Attribute{
+		"eol_date": schema.StringAttribute{
+			Description: "The date this version stream goes EOL.",
+			Computed:    true,
+		},
+		"eol_broken": schema.BoolAttribute{
+			Description: "Whether this EOL version can no longer be supported.",
+			Computed:    true,
+		},
+		"exists": schema.BoolAttribute{
+			Description: "Whether the package exists in an APK repository.",
+			Computed:    true,
+		},
+		"fips": schema.BoolAttribute{
+			Description: "Whether a FIPS-enabled package exists in an APK repository.",
+			Computed:    true,
+		},
+		"lts": schema.StringAttribute{
+			Description: "A date, or \"true\", if this version stream is marked as LTS.",
+			Computed:    true,
+		},
+		"release_date": schema.StringAttribute{
+			Description: "The date this version stream was released.",
+			Computed:    true,
+		},
+		"version": schema.StringAttribute{
+			Description: "The version stream identifier.",
+			Computed:    true,
+		},
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "Lookup the raw version metadata of a package, without the transformations applied by chainguard_versions.",
+		Attributes: map[string]schema.Attribute{
+			"package": schema.StringAttribute{
+				Description: "The name of the package to lookup.",
+				Required:    true,
+			},
+			"grace_period_months": schema.Int64Attribute{
+				Description: "The number of months the EOL of the package is extended by.",
+				Computed:    true,
+			},
+			"last_updated_timestamp": schema.StringAttribute{
+				Description: "The last time this metadata was updated.",
+				Computed:    true,
+			},
+			"latest_version": schema.StringAttribute{
+				Description: "The latest version of the package.",
+				Computed:    true,
+			},
+			"versions": schema.ListNestedAttribute{
+				Description: "The active version streams of the package.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: versionAttrs,
+				},
+			},
+			"eol_versions": schema.ListNestedAttribute{
+				Description: "The EOL version streams of the package.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: versionAttrs,
+				},
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *packageMetadataDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data packageMetadataDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Info(ctx, "read package metadata data-source request", map[string]interface{}{"input-params": data.InputParams()})
+
+	md, err := d.prov.clients().Registry().Registry().GetPackageVersionMetadata(ctx, &registry.PackageVersionMetadataRequest{
+		Package: data.Package.ValueString(),
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			resp.Diagnostics.Append(dataNotFound("package metadata", "" /* extra */, data))
+			return
+		}
+		resp.Diagnostics.Append(errorToDiagnostic(err, "failed to get package version metadata"))
+		return
+	}
+
+	populatePackageMetadata(&data, md)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// populatePackageMetadata copies the fields of md into m as-is.
+func populatePackageMetadata(m *packageMetadataDataSourceModel, md *registry.PackageVersionMetadata) {
+	m.GracePeriodMonths = types.Int64Value(int64(md.GetGracePeriodMonths()))
+	m.LastUpdatedTimestamp = types.StringValue(md.GetLastUpdatedTimestamp())
+	m.LatestVersion = types.StringValue(md.GetLatestVersion())
+
+	m.Versions = make([]packageMetadataVersionModel, 0, len(md.GetVersions()))
+	for _, v := range md.GetVersions() {
+		m.Versions = append(m.Versions, packageMetadataVersion(v))
+	}
+	m.EolVersions = make([]packageMetadataVersionModel, 0, len(md.GetEolVersions()))
+	for _, v := range md.GetEolVersions() {
+		m.EolVersions = append(m.EolVersions, packageMetadataVersion(v))
+	}
+}
+
+func packageMetadataVersion(v *registry.PackageVersion) packageMetadataVersionModel {
+	return packageMetadataVersionModel{
+		EolDate:     types.StringValue(v.GetEolDate()),
+		EolBroken:   types.BoolValue(v.GetEolBroken()),
+		Exists:      types.BoolValue(v.GetExists()),
+		Fips:        types.BoolValue(v.GetFips()),
+		Lts:         types.StringValue(v.GetLts()),
+		ReleaseDate: types.StringValue(v.GetReleaseDate()),
+		Version:     types.StringValue(v.GetVersion()),
+	}
+}
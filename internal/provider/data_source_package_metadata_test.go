@@ -0,0 +1,119 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	registry "chainguard.dev/sdk/proto/platform/registry/v1"
+	registrytest "chainguard.dev/sdk/proto/platform/registry/v1/test"
+	platformtest "chainguard.dev/sdk/proto/platform/test"
+)
+
+func TestPackageMetadataDataSource_Read(t *testing.T) {
+	clients := &platformtest.MockPlatformClients{
+		RegistryClient: registrytest.MockRegistryClients{
+			RegistryClient: registrytest.MockRegistryClient{
+				OnGetPackageVersionMetadata: []registrytest.PackageVersionMetadataOnGet{{
+					Given: &registry.PackageVersionMetadataRequest{Package: "python"},
+					Get: &registry.PackageVersionMetadata{
+						GracePeriodMonths:    6,
+						LastUpdatedTimestamp: "2024-10-07T00:00:00Z",
+						LatestVersion:        "3.13",
+						Versions: []*registry.PackageVersion{{
+							Exists:      true,
+							Fips:        true,
+							Lts:         "true",
+							ReleaseDate: "2024-10-07",
+							Version:     "3.13",
+						}},
+						EolVersions: []*registry.PackageVersion{{
+							EolDate:   "2024-06-27",
+							EolBroken: true,
+							Version:   "3.7",
+						}},
+					},
+				}, {
+					Given: &registry.PackageVersionMetadataRequest{Package: "missing"},
+					Error: status.Error(codes.NotFound, "not found"),
+				}},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		pkg     string
+		want    packageMetadataDataSourceModel
+		wantErr bool
+	}{
+		"found": {
+			pkg: "python",
+			want: packageMetadataDataSourceModel{
+				Package:              types.StringValue("python"),
+				GracePeriodMonths:    types.Int64Value(6),
+				LastUpdatedTimestamp: types.StringValue("2024-10-07T00:00:00Z"),
+				LatestVersion:        types.StringValue("3.13"),
+				Versions: []packageMetadataVersionModel{{
+					EolDate:     types.StringValue(""),
+					EolBroken:   types.BoolValue(false),
+					Exists:      types.BoolValue(true),
+					Fips:        types.BoolValue(true),
+					Lts:         types.StringValue("true"),
+					ReleaseDate: types.StringValue("2024-10-07"),
+					Version:     types.StringValue("3.13"),
+				}},
+				EolVersions: []packageMetadataVersionModel{{
+					EolDate:     types.StringValue("2024-06-27"),
+					EolBroken:   types.BoolValue(true),
+					Exists:      types.BoolValue(false),
+					Fips:        types.BoolValue(false),
+					Lts:         types.StringValue(""),
+					ReleaseDate: types.StringValue(""),
+					Version:     types.StringValue("3.7"),
+				}},
+			},
+		},
+		"not found": {
+			pkg:     "missing",
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			d := &packageMetadataDataSource{dataSource{prov: &providerData{client: clients}}}
+
+			config := testDataSourceConfig(t, d, map[string]any{"package": test.pkg})
+
+			resp := &datasource.ReadResponse{State: tfsdk.State{Schema: config.Schema, Raw: tftypes.NewValue(config.Schema.Type().TerraformType(ctx), nil)}}
+			d.Read(ctx, datasource.ReadRequest{Config: config}, resp)
+
+			if got := resp.Diagnostics.HasError(); got != test.wantErr {
+				t.Fatalf("Read() error = %t, wanted %t: %v", got, test.wantErr, resp.Diagnostics)
+			}
+			if test.wantErr {
+				return
+			}
+			var got packageMetadataDataSourceModel
+			if diags := resp.State.Get(ctx, &got); diags.HasError() {
+				t.Fatalf("State.Get() = %v", diags)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Read() state mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
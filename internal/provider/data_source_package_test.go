@@ -0,0 +1,121 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	registry "chainguard.dev/sdk/proto/platform/registry/v1"
+	registrytest "chainguard.dev/sdk/proto/platform/registry/v1/test"
+	platformtest "chainguard.dev/sdk/proto/platform/test"
+)
+
+func TestPackageDataSource(t *testing.T) {
+	clients := &platformtest.MockPlatformClients{
+		RegistryClient: registrytest.MockRegistryClients{
+			RegistryClient: registrytest.MockRegistryClient{
+				OnGetPackageVersionMetadata: []registrytest.PackageVersionMetadataOnGet{{
+					Given: &registry.PackageVersionMetadataRequest{Package: "python"},
+					Get: &registry.PackageVersionMetadata{
+						LatestVersion: "3.13",
+						Versions: []*registry.PackageVersion{
+							{Exists: true, Version: "3.13"},
+							{Exists: true, Fips: true, Version: "3.12"},
+						},
+						EolVersions: []*registry.PackageVersion{
+							{Exists: true, Fips: true, Version: "3.8"},
+						},
+					},
+				}, {
+					Given: &registry.PackageVersionMetadataRequest{Package: "nofips"},
+					Get: &registry.PackageVersionMetadata{
+						LatestVersion: "1.2",
+						Versions:      []*registry.PackageVersion{{Exists: true, Version: "1.2"}},
+					},
+				}, {
+					Given: &registry.PackageVersionMetadataRequest{Package: "missing"},
+					Error: status.Error(codes.NotFound, "not found"),
+				}, {
+					Given: &registry.PackageVersionMetadataRequest{Package: "bad"},
+					Error: errors.New("boom"),
+				}},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		config       map[string]any
+		wantExists   bool
+		wantLatest   string
+		wantVariants []string
+		wantErr      bool
+	}{
+		"package": {
+			config:       map[string]any{"package": "python"},
+			wantExists:   true,
+			wantLatest:   "3.13",
+			wantVariants: []string{"fips"},
+		},
+		"fips variant": {
+			config:       map[string]any{"package": "python", "variant": "fips"},
+			wantExists:   true,
+			wantLatest:   "3.12",
+			wantVariants: []string{"fips"},
+		},
+		"no fips variant": {
+			config:       map[string]any{"package": "nofips", "variant": "fips"},
+			wantVariants: []string{},
+		},
+		"not found": {
+			config:       map[string]any{"package": "missing"},
+			wantVariants: []string{},
+		},
+		"error": {
+			config:  map[string]any{"package": "bad"},
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			d := &packageDataSource{dataSource{prov: &providerData{client: clients}}}
+
+			config := testDataSourceConfig(t, d, test.config)
+
+			resp := &datasource.ReadResponse{State: tfsdk.State{Schema: config.Schema, Raw: config.Raw}}
+			d.Read(ctx, datasource.ReadRequest{Config: config}, resp)
+			if got := resp.Diagnostics.HasError(); got != test.wantErr {
+				t.Fatalf("Read() error = %t, wanted %t: %v", got, test.wantErr, resp.Diagnostics)
+			}
+			if test.wantErr {
+				return
+			}
+
+			var got packageDataSourceModel
+			if diags := resp.State.Get(ctx, &got); diags.HasError() {
+				t.Fatalf("State.Get() = %v", diags)
+			}
+			if got.Exists.ValueBool() != test.wantExists {
+				t.Errorf("exists = %v, wanted %t", got.Exists, test.wantExists)
+			}
+			if got.LatestVersion.ValueString() != test.wantLatest {
+				t.Errorf("latest_version = %v, wanted %q", got.LatestVersion, test.wantLatest)
+			}
+			if diff := cmp.Diff(test.wantVariants, got.VariantsAvailable); diff != "" {
+				t.Errorf("variants_available (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
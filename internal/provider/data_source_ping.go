@@ -0,0 +1,124 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	iam "chainguard.dev/sdk/proto/platform/iam/v1"
+)
+
+const (
+	// pingGroupName narrows the authenticated call made by chainguard_ping so
+	// it returns quickly and matches nothing in practice.
+	pingGroupName = "chainguard-ping"
+
+	// pingTimeout bounds the authenticated call made by chainguard_ping.
+	pingTimeout = 30 * time.Second
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &pingDataSource{}
+	_ datasource.DataSourceWithConfigure = &pingDataSource{}
+)
+
+// NewPingDataSource is a helper function to simplify the provider implementation.
+func NewPingDataSource() datasource.DataSource {
+	return &pingDataSource{}
+}
+
+// pingDataSource is the data source implementation.
+type pingDataSource struct {
+	dataSource
+}
+
+type pingDataSourceModel struct {
+	ConsoleAPI types.String `tfsdk:"console_api"`
+	Reachable  types.Bool   `tfsdk:"reachable"`
+	LatencyMS  types.Int64  `tfsdk:"latency_ms"`
+}
+
+func (m pingDataSourceModel) InputParams() string {
+	return fmt.Sprintf("[console_api=%s]", m.ConsoleAPI)
+}
+
+// Metadata returns the data source type name.
+func (d *pingDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ping"
+}
+
+func (d *pingDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.configure(ctx, req, resp)
+}
+
+// Schema defines the schema for the data source.
+func (d *pingDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Check connectivity and authentication with the Chainguard console API by making a cheap authenticated call. Fails with a diagnostic describing how to fix the problem when the API is unreachable or the provider cannot authenticate.",
+		Attributes: map[string]schema.Attribute{
+			"console_api": schema.StringAttribute{
+				Description: "The console API that was checked.",
+				Computed:    true,
+			},
+			"reachable": schema.BoolAttribute{
+				Description: "Whether the console API answered an authenticated call.",
+				Computed:    true,
+			},
+			"latency_ms": schema.Int64Attribute{
+				Description: "The round trip time of the authenticated call, in milliseconds.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *pingDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+	data := pingDataSourceModel{
+		ConsoleAPI: types.StringValue(d.prov.consoleAPI),
+	}
+	tflog.Info(ctx, "read ping data-source request", map[string]interface{}{"input-params": data.InputParams()})
+
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+	start := time.Now()
+	_, err := d.prov.clients().IAM().Groups().List(ctx, &iam.GroupFilter{Name: pingGroupName})
+	latency := time.Since(start)
+	if err != nil {
+		resp.Diagnostics.Append(pingDiagnostic(err, d.prov.consoleAPI))
+		return
+	}
+
+	data.Reachable = types.BoolValue(true)
+	data.LatencyMS = types.Int64Value(latency.Milliseconds())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// pingDiagnostic converts a failed ping into a diagnostic suggesting how to fix it.
+func pingDiagnostic(err error, consoleAPI string) diag.Diagnostic {
+	const summary = "console API check failed"
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return diag.NewErrorDiagnostic(summary,
+			fmt.Sprintf("Unable to reach %q: %s. Please check network connectivity and the value of \"chainguard.console_api\" in your Terraform provider configuration.", consoleAPI, status.Convert(err).Message()))
+	case codes.PermissionDenied:
+		return diag.NewErrorDiagnostic(summary,
+			fmt.Sprintf("Authenticated with %q, but the identity is not allowed to list groups: %s. Please check the roles bound to the identity in login_options.", consoleAPI, status.Convert(err).Message()))
+	default:
+		return errorToDiagnostic(err, summary)
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	iam "chainguard.dev/sdk/proto/platform/iam/v1"
+	iamtest "chainguard.dev/sdk/proto/platform/iam/v1/test"
+	platformtest "chainguard.dev/sdk/proto/platform/test"
+)
+
+func TestPingDataSource_Read(t *testing.T) {
+	const consoleAPI = "https://console-api.example.com"
+
+	tests := map[string]struct {
+		err        error
+		wantDetail string
+	}{
+		"reachable": {},
+		"unreachable": {
+			err:        status.Error(codes.Unavailable, "connection refused"),
+			wantDetail: "check network connectivity",
+		},
+		"unauthenticated": {
+			err:        status.Error(codes.Unauthenticated, "token expired"),
+			wantDetail: "chainctl auth login",
+		},
+		"permission denied": {
+			err:        status.Error(codes.PermissionDenied, "missing groups.list"),
+			wantDetail: "not allowed to list groups",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			clients := &platformtest.MockPlatformClients{
+				IAMClient: iamtest.MockIAMClient{
+					GroupsClient: iamtest.MockGroupsClient{
+						OnList: []iamtest.GroupOnList{{
+							Given: &iam.GroupFilter{Name: pingGroupName},
+							List:  &iam.GroupList{},
+							Error: test.err,
+						}},
+					},
+				},
+			}
+
+			ctx := context.Background()
+			d := &pingDataSource{dataSource{prov: &providerData{client: clients, consoleAPI: consoleAPI}}}
+
+			var sresp datasource.SchemaResponse
+			d.Schema(ctx, datasource.SchemaRequest{}, &sresp)
+			raw := tftypes.NewValue(sresp.Schema.Type().TerraformType(ctx), nil)
+
+			resp := &datasource.ReadResponse{State: tfsdk.State{Schema: sresp.Schema, Raw: raw}}
+			d.Read(ctx, datasource.ReadRequest{Config: tfsdk.Config{Schema: sresp.Schema, Raw: raw}}, resp)
+
+			if test.wantDetail != "" {
+				if resp.Diagnostics.ErrorsCount() != 1 {
+					t.Fatalf("Read() = %v, wanted one error", resp.Diagnostics)
+				}
+				if got := resp.Diagnostics.Errors()[0].Detail(); !strings.Contains(got, test.wantDetail) {
+					t.Errorf("Read() detail = %q, wanted it to contain %q", got, test.wantDetail)
+				}
+				return
+			}
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Read() = %v", resp.Diagnostics)
+			}
+			var got pingDataSourceModel
+			if diags := resp.State.Get(ctx, &got); diags.HasError() {
+				t.Fatalf("State.Get() = %v", diags)
+			}
+			if !got.Reachable.ValueBool() {
+				t.Error("reachable = false, wanted true")
+			}
+			if got.LatencyMS.IsNull() || got.LatencyMS.ValueInt64() < 0 {
+				t.Errorf("latency_ms = %v, wanted a duration", got.LatencyMS)
+			}
+			if got.ConsoleAPI.ValueString() != consoleAPI {
+				t.Errorf("console_api = %s, wanted %q", got.ConsoleAPI, consoleAPI)
+			}
+		})
+	}
+}
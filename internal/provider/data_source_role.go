@@ -8,6 +8,7 @@ package provider
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -40,11 +41,13 @@ type roleDataSourceModel struct {
 	Name   types.String `tfsdk:"name"`
 	Parent types.String `tfsdk:"parent"`
 
+	HasCapability types.String `tfsdk:"has_capability"`
+
 	Items []*roleModel `tfsdk:"items"`
 }
 
 func (d roleDataSourceModel) InputParams() string {
-	return fmt.Sprintf("[id=%s, name=%s, parentd=%s]", d.ID, d.Name, d.Parent)
+	return fmt.Sprintf("[id=%s, name=%s, parent=%s, has_capability=%s]", d.ID, d.Name, d.Parent, d.HasCapability)
 }
 
 type roleModel struct {
@@ -83,6 +86,11 @@ func (d *roleDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, r
 				Optional:    true,
 				Validators:  []validator.String{validators.UIDP(true /* allowRootSentinel */)},
 			},
+			"has_capability": schema.StringAttribute{
+				Description: "Only match roles which grant this capability, e.g. \"registry.pull\".",
+				Optional:    true,
+				Validators:  []validator.String{validators.Capability()},
+			},
 			"items": schema.ListNestedAttribute{
 				Description: "Roles matched by the data source's filter.",
 				Computed:    true,
@@ -121,7 +129,7 @@ func (d *roleDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 	}
 	tflog.Info(ctx, "read role data-source request", map[string]interface{}{"input-params": data.InputParams()})
 
-	all, err := d.prov.client.IAM().Roles().List(ctx, &iam.RoleFilter{
+	all, err := d.prov.clients().IAM().Roles().List(ctx, &iam.RoleFilter{
 		Id:     data.ID.ValueString(),
 		Name:   data.Name.ValueString(),
 		Parent: data.Parent.ValueString(),
@@ -132,6 +140,11 @@ func (d *roleDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 	}
 
 	for _, role := range all.GetItems() {
+		// The API can't filter by capability, so do it here.
+		if c := data.HasCapability.ValueString(); c != "" && !slices.Contains(role.Capabilities, c) {
+			continue
+		}
+
 		caps, diags := types.ListValueFrom(ctx, types.StringType, role.Capabilities)
 		// Collect returned warnings/errors.
 		resp.Diagnostics.Append(diags...)
@@ -150,7 +163,7 @@ func (d *roleDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 		})
 	}
 	// Role wasn't found, or was deleted outside Terraform
-	if len(all.GetItems()) == 0 {
+	if len(data.Items) == 0 {
 		resp.Diagnostics.Append(dataNotFound("role", "" /* extra */, data))
 		return
 	} else if d.prov.testing {
@@ -6,11 +6,19 @@ SPDX-License-Identifier: Apache-2.0
 package provider
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 
+	iam "chainguard.dev/sdk/proto/platform/iam/v1"
+	iamtest "chainguard.dev/sdk/proto/platform/iam/v1/test"
+	platformtest "chainguard.dev/sdk/proto/platform/test"
 	"chainguard.dev/sdk/uidp"
 )
 
@@ -46,3 +54,85 @@ func TestAccRoleDataSource(t *testing.T) {
 		},
 	})
 }
+
+func TestRoleDataSource_HasCapability(t *testing.T) {
+	const (
+		root   = "0123456789abcdef0123456789abcdef01234567"
+		viewer = root + "/000000000000000a"
+		puller = root + "/000000000000000b"
+		pusher = root + "/000000000000000c"
+	)
+	roles := &iam.RoleList{Items: []*iam.Role{
+		{Id: viewer, Name: "viewer", Capabilities: []string{"groups.list"}},
+		{Id: puller, Name: "registry.pull", Capabilities: []string{"groups.list", "registry.pull"}},
+		{Id: pusher, Name: "registry.push", Capabilities: []string{"registry.pull", "registry.push"}},
+	}}
+	clients := &platformtest.MockPlatformClients{
+		IAMClient: iamtest.MockIAMClient{
+			RolesClient: iamtest.MockRolesClient{OnList: []iamtest.RoleOnList{{
+				Given: &iam.RoleFilter{Parent: "/"},
+				List:  roles,
+			}, {
+				Given: &iam.RoleFilter{Name: "viewer", Parent: "/"},
+				List:  &iam.RoleList{Items: roles.Items[:1]},
+			}}},
+		},
+	}
+
+	tests := map[string]struct {
+		config  map[string]any
+		want    []string
+		wantErr bool
+	}{
+		"no capability filter": {
+			config: map[string]any{"parent": "/"},
+			want:   []string{viewer, puller, pusher},
+		},
+		"has capability": {
+			config: map[string]any{"parent": "/", "has_capability": "registry.pull"},
+			want:   []string{puller, pusher},
+		},
+		"has capability held by one role": {
+			config: map[string]any{"parent": "/", "has_capability": "registry.push"},
+			want:   []string{pusher},
+		},
+		"combined with name": {
+			config: map[string]any{"parent": "/", "name": "viewer", "has_capability": "groups.list"},
+			want:   []string{viewer},
+		},
+		"no role has capability": {
+			config:  map[string]any{"parent": "/", "name": "viewer", "has_capability": "registry.pull"},
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			d := &roleDataSource{dataSource{prov: &providerData{client: clients}}}
+
+			config := testDataSourceConfig(t, d, test.config)
+
+			resp := &datasource.ReadResponse{State: tfsdk.State{Schema: config.Schema, Raw: config.Raw}}
+			d.Read(ctx, datasource.ReadRequest{Config: config}, resp)
+			if got := resp.Diagnostics.HasError(); got != test.wantErr {
+				t.Fatalf("Read() error = %t, wanted %t: %v", got, test.wantErr, resp.Diagnostics)
+			}
+			if test.wantErr {
+				return
+			}
+
+			var items []roleModel
+			if diags := resp.State.GetAttribute(ctx, path.Root("items"), &items); diags.HasError() {
+				t.Fatalf("GetAttribute(items) = %v", diags)
+			}
+			got := make([]string, 0, len(items))
+			for _, item := range items {
+				got = append(got, item.ID.ValueString())
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Read() items (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
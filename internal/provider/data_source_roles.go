@@ -0,0 +1,143 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	iam "chainguard.dev/sdk/proto/platform/iam/v1"
+	"github.com/chainguard-dev/terraform-provider-chainguard/internal/validators"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &rolesDataSource{}
+	_ datasource.DataSourceWithConfigure = &rolesDataSource{}
+)
+
+// NewRolesDataSource is a helper function to simplify the provider implementation.
+func NewRolesDataSource() datasource.DataSource {
+	return &rolesDataSource{}
+}
+
+// rolesDataSource is the data source implementation.
+type rolesDataSource struct {
+	dataSource
+}
+
+type rolesDataSourceModel struct {
+	ParentID     types.String `tfsdk:"parent_id"`
+	NameContains types.String `tfsdk:"name_contains"`
+
+	Items []*roleModel `tfsdk:"items"`
+}
+
+func (d rolesDataSourceModel) InputParams() string {
+	return fmt.Sprintf("[parent_id=%s, name_contains=%s]", d.ParentID, d.NameContains)
+}
+
+// Metadata returns the data source type name.
+func (d *rolesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_roles"
+}
+
+func (d *rolesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.configure(ctx, req, resp)
+}
+
+// Schema defines the schema for the data source.
+func (d *rolesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "List the roles visible to the caller. Unlike chainguard_role, matching no roles is not an error.",
+		Attributes: map[string]schema.Attribute{
+			"parent_id": schema.StringAttribute{
+				Description: "Only list roles defined in the group with this UIDP, or \"/\" for the built-in roles.",
+				Optional:    true,
+				Validators:  []validator.String{validators.UIDP(true /* allowRootSentinel */)},
+			},
+			"name_contains": schema.StringAttribute{
+				Description: "Only list roles whose name contains this string.",
+				Optional:    true,
+			},
+			"items": schema.ListNestedAttribute{
+				Description: "The roles matched by the data source's filters.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Description: "The UIDP of this role.",
+							Computed:    true,
+						},
+						"name": schema.StringAttribute{
+							Description: "The name of this role.",
+							Computed:    true,
+						},
+						"description": schema.StringAttribute{
+							Description: "The description of this role.",
+							Computed:    true,
+						},
+						"capabilities": schema.ListAttribute{
+							Description: "The capabilities granted to this role.",
+							Computed:    true,
+							ElementType: types.StringType,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *rolesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data rolesDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Info(ctx, "read roles data-source request", map[string]interface{}{"input-params": data.InputParams()})
+
+	// The API returns every match at once, it doesn't page.
+	all, err := d.prov.clients().IAM().Roles().List(ctx, &iam.RoleFilter{
+		Parent: data.ParentID.ValueString(),
+	})
+	if err != nil {
+		resp.Diagnostics.Append(errorToDiagnostic(err, "failed to list roles"))
+		return
+	}
+
+	data.Items = []*roleModel{}
+	for _, role := range all.GetItems() {
+		// The API only filters by exact name, so match substrings here.
+		if !strings.Contains(role.Name, data.NameContains.ValueString()) {
+			continue
+		}
+
+		caps, diags := types.ListValueFrom(ctx, types.StringType, role.Capabilities)
+		resp.Diagnostics.Append(diags...)
+		if diags.HasError() {
+			return
+		}
+
+		data.Items = append(data.Items, &roleModel{
+			ID:           types.StringValue(role.Id),
+			Name:         types.StringValue(role.Name),
+			Description:  types.StringValue(role.Description),
+			Capabilities: caps,
+		})
+	}
+
+	// Set state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
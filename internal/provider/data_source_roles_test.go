@@ -0,0 +1,100 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+
+	iam "chainguard.dev/sdk/proto/platform/iam/v1"
+	iamtest "chainguard.dev/sdk/proto/platform/iam/v1/test"
+	platformtest "chainguard.dev/sdk/proto/platform/test"
+)
+
+func TestRolesDataSource(t *testing.T) {
+	const (
+		root   = "0123456789abcdef0123456789abcdef01234567"
+		viewer = root + "/000000000000000a"
+		puller = root + "/000000000000000b"
+		pusher = root + "/000000000000000c"
+		custom = root + "/000000000000000d"
+	)
+	builtin := []*iam.Role{
+		{Id: viewer, Name: "viewer", Capabilities: []string{"groups.list"}},
+		{Id: puller, Name: "registry.pull", Capabilities: []string{"groups.list", "registry.pull"}},
+		{Id: pusher, Name: "registry.push", Capabilities: []string{"registry.pull", "registry.push"}},
+	}
+	clients := &platformtest.MockPlatformClients{
+		IAMClient: iamtest.MockIAMClient{
+			RolesClient: iamtest.MockRolesClient{OnList: []iamtest.RoleOnList{{
+				Given: &iam.RoleFilter{},
+				List: &iam.RoleList{Items: append(builtin,
+					&iam.Role{Id: custom, Name: "registry.mirror", Capabilities: []string{"registry.pull"}},
+				)},
+			}, {
+				Given: &iam.RoleFilter{Parent: "/"},
+				List:  &iam.RoleList{Items: builtin},
+			}}},
+		},
+	}
+
+	tests := map[string]struct {
+		config map[string]any
+		want   []string
+	}{
+		"all": {
+			want: []string{viewer, puller, pusher, custom},
+		},
+		"parent": {
+			config: map[string]any{"parent_id": "/"},
+			want:   []string{viewer, puller, pusher},
+		},
+		"name contains": {
+			config: map[string]any{"name_contains": "registry."},
+			want:   []string{puller, pusher, custom},
+		},
+		"parent and name contains": {
+			config: map[string]any{"parent_id": "/", "name_contains": "registry."},
+			want:   []string{puller, pusher},
+		},
+		"no match": {
+			config: map[string]any{"name_contains": "owner"},
+			want:   []string{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			d := &rolesDataSource{dataSource{prov: &providerData{client: clients}}}
+
+			config := testDataSourceConfig(t, d, test.config)
+
+			resp := &datasource.ReadResponse{State: tfsdk.State{Schema: config.Schema, Raw: config.Raw}}
+			d.Read(ctx, datasource.ReadRequest{Config: config}, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Read() = %v", resp.Diagnostics)
+			}
+
+			var items []roleModel
+			if diags := resp.State.GetAttribute(ctx, path.Root("items"), &items); diags.HasError() {
+				t.Fatalf("GetAttribute(items) = %v", diags)
+			}
+			got := make([]string, 0, len(items))
+			for _, item := range items {
+				got = append(got, item.ID.ValueString())
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Read() items (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
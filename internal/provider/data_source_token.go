@@ -0,0 +1,95 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &tokenDataSource{}
+	_ datasource.DataSourceWithConfigure = &tokenDataSource{}
+)
+
+// NewTokenDataSource is a helper function to simplify the provider implementation.
+func NewTokenDataSource() datasource.DataSource {
+	return &tokenDataSource{}
+}
+
+// tokenDataSource is the data source implementation.
+type tokenDataSource struct {
+	dataSource
+}
+
+type tokenDataSourceModel struct {
+	Audience types.String `tfsdk:"audience"`
+	Token    types.String `tfsdk:"token"`
+}
+
+// InputParams omits the token, which is sensitive.
+func (m tokenDataSourceModel) InputParams() string {
+	return fmt.Sprintf("[audience=%s]", m.Audience)
+}
+
+// Metadata returns the data source type name.
+func (d *tokenDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_token"
+}
+
+func (d *tokenDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.configure(ctx, req, resp)
+}
+
+// Schema defines the schema for the data source.
+func (d *tokenDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "The Chainguard token the provider authenticates with, for reuse in raw API calls. Requires that login is not disabled in the provider's login_options.",
+		Attributes: map[string]schema.Attribute{
+			"audience": schema.StringAttribute{
+				Description: "The audience of the token.",
+				Computed:    true,
+			},
+			"token": schema.StringAttribute{
+				Description: "The Chainguard token.",
+				Computed:    true,
+				Sensitive:   true,
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *tokenDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+	if d.prov == nil {
+		resp.Diagnostics.AddError("provider not configured", "The Chainguard provider was not configured. Please report this issue to the provider developers.")
+		return
+	}
+	if d.prov.loginConfig.Disabled {
+		resp.Diagnostics.AddError("login disabled",
+			"chainguard_token is only available when login is enabled. Please set provider login_options.disabled = false.")
+		return
+	}
+	data := tokenDataSourceModel{
+		Audience: types.StringValue(d.prov.audienceFor(d.prov.consoleAPI)),
+	}
+	tflog.Info(ctx, "read token data-source request", map[string]interface{}{"input-params": data.InputParams()})
+
+	tok, err := d.prov.token(ctx, d.prov.consoleAPI, false /* forceRefresh */)
+	if err != nil {
+		resp.Diagnostics.Append(errorToDiagnostic(err, "failed to get token"))
+		return
+	}
+
+	data.Token = types.StringValue(string(tok))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
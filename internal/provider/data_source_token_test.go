@@ -0,0 +1,74 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/chainguard-dev/terraform-provider-chainguard/internal/token"
+)
+
+func TestTokenDataSource_Read(t *testing.T) {
+	const audience = "https://console-api.example.com"
+	getToken = func(_ context.Context, cfg token.LoginConfig, _ bool) ([]byte, error) {
+		if cfg.Audience != audience {
+			t.Errorf("getToken() audience = %q, wanted %q", cfg.Audience, audience)
+		}
+		return []byte("chainguard-token"), nil
+	}
+	t.Cleanup(func() { getToken = token.Get })
+
+	tests := map[string]struct {
+		disabled bool
+		wantErr  bool
+	}{
+		"login enabled": {
+			disabled: false,
+		},
+		"login disabled": {
+			disabled: true,
+			wantErr:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			d := &tokenDataSource{dataSource{prov: &providerData{
+				loginConfig: token.LoginConfig{Audience: audience, Disabled: test.disabled},
+			}}}
+
+			var sresp datasource.SchemaResponse
+			d.Schema(ctx, datasource.SchemaRequest{}, &sresp)
+			raw := tftypes.NewValue(sresp.Schema.Type().TerraformType(ctx), nil)
+
+			resp := &datasource.ReadResponse{State: tfsdk.State{Schema: sresp.Schema, Raw: raw}}
+			d.Read(ctx, datasource.ReadRequest{Config: tfsdk.Config{Schema: sresp.Schema, Raw: raw}}, resp)
+
+			if got := resp.Diagnostics.HasError(); got != test.wantErr {
+				t.Fatalf("Read() error = %t, wanted %t: %v", got, test.wantErr, resp.Diagnostics)
+			}
+			if test.wantErr {
+				return
+			}
+			var got tokenDataSourceModel
+			if diags := resp.State.Get(ctx, &got); diags.HasError() {
+				t.Fatalf("State.Get() = %v", diags)
+			}
+			if got.Token.ValueString() == "" {
+				t.Error("token is empty")
+			}
+			if got.Audience.ValueString() != audience {
+				t.Errorf("audience = %s, wanted %q", got.Audience, audience)
+			}
+		})
+	}
+}
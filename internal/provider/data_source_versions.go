@@ -17,9 +17,11 @@ import (
 	"google.golang.org/grpc/status"
 
 	registry "chainguard.dev/sdk/proto/platform/registry/v1"
+	"github.com/chainguard-dev/terraform-provider-chainguard/internal/validators"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
@@ -43,37 +45,46 @@ type versionsDataSource struct {
 type versionsDataSourceModel struct {
 	Package types.String `tfsdk:"package"`
 	Variant types.String `tfsdk:"variant"`
+	Fips    types.Bool   `tfsdk:"fips"`
+
+	ReleasedAfter  types.String `tfsdk:"released_after"`
+	ReleasedBefore types.String `tfsdk:"released_before"`
 
 	Versions    *versionsDataSourceProtoModel                `tfsdk:"versions"`
 	VersionMap  map[string]versionsDataSourceVersionMapModel `tfsdk:"version_map"`
 	OrderedKeys []string                                     `tfsdk:"ordered_keys"`
+
+	SupportedCount types.Int64 `tfsdk:"supported_count"`
+	EolCount       types.Int64 `tfsdk:"eol_count"`
+
+	JSON types.String `tfsdk:"json"`
 }
 
 // versionsDataSourceProtoModel is the schema for the "proto" version
 // achieved through the versions proto. This is provided for backwards
-// compatibility.
+// compatibility. Its JSON encoding matches the proto's.
 type versionsDataSourceProtoModel struct {
-	GracePeriodMonths    int64                                      `tfsdk:"grace_period_months"`
-	LastUpdatedTimestamp string                                     `tfsdk:"last_updated_timestamp"`
-	LatestVersion        string                                     `tfsdk:"latest_version"`
-	EolVersions          []*versionsDataSourceProtoEolVersionsModel `tfsdk:"eol_versions"`
-	Versions             []*versionsDataSourceProtoVersionsModel    `tfsdk:"versions"`
+	GracePeriodMonths    int64                                      `tfsdk:"grace_period_months" json:"gracePeriodMonths"`
+	LastUpdatedTimestamp string                                     `tfsdk:"last_updated_timestamp" json:"lastUpdatedTimestamp"`
+	LatestVersion        string                                     `tfsdk:"latest_version" json:"latestVersion"`
+	EolVersions          []*versionsDataSourceProtoEolVersionsModel `tfsdk:"eol_versions" json:"eolVersions"`
+	Versions             []*versionsDataSourceProtoVersionsModel    `tfsdk:"versions" json:"versions"`
 }
 
 type versionsDataSourceProtoEolVersionsModel struct {
-	EolDate     string `tfsdk:"eol_date"`
-	EolBroken   bool   `tfsdk:"eol_broken"`
-	Exists      bool   `tfsdk:"exists"`
-	Fips        bool   `tfsdk:"fips"`
-	ReleaseDate string `tfsdk:"release_date"`
-	Version     string `tfsdk:"version"`
+	EolDate     string `tfsdk:"eol_date" json:"eolDate"`
+	EolBroken   bool   `tfsdk:"eol_broken" json:"eolBroken"`
+	Exists      bool   `tfsdk:"exists" json:"exists"`
+	Fips        bool   `tfsdk:"fips" json:"fips"`
+	ReleaseDate string `tfsdk:"release_date" json:"releaseDate"`
+	Version     string `tfsdk:"version" json:"version"`
 }
 
 type versionsDataSourceProtoVersionsModel struct {
-	Exists      bool   `tfsdk:"exists"`
-	Fips        bool   `tfsdk:"fips"`
-	ReleaseDate string `tfsdk:"release_date"`
-	Version     string `tfsdk:"version"`
+	Exists      bool   `tfsdk:"exists" json:"exists"`
+	Fips        bool   `tfsdk:"fips" json:"fips"`
+	ReleaseDate string `tfsdk:"release_date" json:"releaseDate"`
+	Version     string `tfsdk:"version" json:"version"`
 }
 
 // versionsDataSourceVersionMapModel is the schema for the "legacy" version
@@ -92,7 +103,7 @@ type versionsDataSourceVersionMapModel struct {
 }
 
 func (m versionsDataSourceModel) InputParams() string {
-	return fmt.Sprintf("[package=%s, variant=%s]", m.Package, m.Variant)
+	return fmt.Sprintf("[package=%s, variant=%s, fips=%s, released_after=%s, released_before=%s]", m.Package, m.Variant, m.Fips, m.ReleasedAfter, m.ReleasedBefore)
 }
 
 // Metadata returns the data source type name.
@@ -118,8 +129,22 @@ func (d *versionsDataSource) Schema(_ context.Context, _ datasource.SchemaReques
 				Optional:    true,
 				Validators:  []validator.String{Variant()},
 			},
+			"fips": schema.BoolAttribute{
+				Description: "Lookup the fips variant, equivalent to variant = \"fips\".",
+				Optional:    true,
+			},
+			"released_after": schema.StringAttribute{
+				Description: "Only include versions released on or after this date (YYYY-MM-DD).",
+				Optional:    true,
+				Validators:  []validator.String{validators.ValidateStringFuncs(checkDateOnly)},
+			},
+			"released_before": schema.StringAttribute{
+				Description: "Only include versions released on or before this date (YYYY-MM-DD).",
+				Optional:    true,
+				Validators:  []validator.String{validators.ValidateStringFuncs(checkDateOnly)},
+			},
 			"versions": schema.SingleNestedAttribute{
-				Description: "The versions output of the package.",
+				Description: "The versions output of the package. This mirrors the registry's package version metadata, and is the stable output to use in new configurations.",
 				Computed:    true,
 				Attributes: map[string]schema.Attribute{
 					"grace_period_months": schema.Int64Attribute{
@@ -193,7 +218,7 @@ func (d *versionsDataSource) Schema(_ context.Context, _ datasource.SchemaReques
 				},
 			},
 			"version_map": schema.MapNestedAttribute{
-				Description: "The version map.",
+				Description: "The version map, as output by the legacy versions module. Null when the provider sets omit_legacy_versions.",
 				Computed:    true,
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
@@ -237,10 +262,22 @@ func (d *versionsDataSource) Schema(_ context.Context, _ datasource.SchemaReques
 				},
 			},
 			"ordered_keys": schema.ListAttribute{
-				Description: "A list of keys as they appear in the versions output, sorted semantically.",
+				Description: "A list of keys as they appear in the versions output, sorted semantically, as output by the legacy versions module. Null when the provider sets omit_legacy_versions.",
 				Computed:    true,
 				ElementType: types.StringType,
 			},
+			"supported_count": schema.Int64Attribute{
+				Description: "The number of versions which are not EOL, after filtering.",
+				Computed:    true,
+			},
+			"eol_count": schema.Int64Attribute{
+				Description: "The number of EOL versions still inside their grace period, after filtering.",
+				Computed:    true,
+			},
+			"json": schema.StringAttribute{
+				Description: "The versions output encoded as JSON, with the field names of the registry's package version metadata, for external tooling. Treat it as opaque: decode it rather than matching on its formatting.",
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -252,26 +289,78 @@ func (d *versionsDataSource) Read(ctx context.Context, req datasource.ReadReques
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	tflog.Info(ctx, "read versions data-source request", map[string]interface{}{"config": data})
+	tflog.Info(ctx, "read versions data-source request", map[string]interface{}{"input-params": data.InputParams()})
 
 	pkg := data.Package.ValueString()
 	variant := data.Variant.ValueString()
+	if data.Fips.ValueBool() {
+		if variant != "" && variant != "fips" {
+			resp.Diagnostics.AddAttributeError(path.Root("fips"), "conflicting variant",
+				fmt.Sprintf("fips = true cannot be combined with variant %q.", variant))
+			return
+		}
+		variant = "fips"
+	}
 
-	vproto, vmap, orderedKeys, diags := calculate(ctx, d.prov.client.Registry().Registry(), pkg, variant, d.prov.versionStreamAllows)
+	var window releaseWindow
+	// Both are validated as dates, so parse errors leave the bound open.
+	window.after, _ = time.Parse(time.DateOnly, data.ReleasedAfter.ValueString())
+	window.before, _ = time.Parse(time.DateOnly, data.ReleasedBefore.ValueString())
+
+	vproto, vmap, orderedKeys, diags := calculate(ctx, d.prov.clients().Registry().Registry(), pkg, variant, d.prov.versionStreamAllows, window)
 	resp.Diagnostics.Append(diags...)
 	if diags.HasError() {
 		return
 	}
 
 	data.Versions = vproto
-	data.VersionMap = vmap
-	data.OrderedKeys = orderedKeys
+	raw, err := json.Marshal(vproto)
+	if err != nil {
+		resp.Diagnostics.Append(errorToDiagnostic(err, "failed to marshal versions"))
+		return
+	}
+	data.JSON = types.StringValue(string(raw))
+	supported, eol := countVersions(vmap)
+	data.SupportedCount = types.Int64Value(supported)
+	data.EolCount = types.Int64Value(eol)
+	if !d.prov.omitLegacyVersions {
+		data.VersionMap = vmap
+		data.OrderedKeys = orderedKeys
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
+// releaseWindow bounds the release dates of versions, inclusively. A zero
+// bound is open.
+type releaseWindow struct {
+	after, before time.Time
+}
+
+func (w releaseWindow) open() bool {
+	return w.after.IsZero() && w.before.IsZero()
+}
+
+// contains reports whether the version released on releaseDate falls within
+// the window.
+func (w releaseWindow) contains(releaseDate string) (bool, error) {
+	t, err := time.Parse(time.DateOnly, releaseDate)
+	if err != nil {
+		return false, err
+	}
+	return !t.Before(w.after) && (w.before.IsZero() || !t.After(w.before)), nil
+}
+
+// checkDateOnly implements validators.ValidateStringFunc.
+func checkDateOnly(raw string) error {
+	if _, err := time.Parse(time.DateOnly, raw); err != nil {
+		return fmt.Errorf("failed to parse %s as YYYY-MM-DD: %w", raw, err)
+	}
+	return nil
+}
+
 // Responsible for the generation of all calculated fields (i.e. Versions, VersionMap, OrderedKeys).
-func calculate(ctx context.Context, client registry.RegistryClient, pkg string, variant string, allows map[string]struct{}) (*versionsDataSourceProtoModel, map[string]versionsDataSourceVersionMapModel, []string, diag.Diagnostics) {
+func calculate(ctx context.Context, client registry.RegistryClient, pkg string, variant string, allows map[string]struct{}, window releaseWindow) (*versionsDataSourceProtoModel, map[string]versionsDataSourceVersionMapModel, []string, diag.Diagnostics) {
 	diags := make(diag.Diagnostics, 0)
 
 	// If variant provided (i.e. "fips"), modify the key names to include it
@@ -369,6 +458,39 @@ func calculate(ctx context.Context, client registry.RegistryClient, pkg string,
 		vproto.Versions = fv
 	}
 
+	// if a release window is set, filter out any {eol-}versions released
+	// outside of it, or whose release date can't be determined.
+	if !window.open() {
+		fv, fev := []*versionsDataSourceProtoVersionsModel{}, []*versionsDataSourceProtoEolVersionsModel{}
+		for _, v := range vproto.EolVersions {
+			ok, err := window.contains(v.ReleaseDate)
+			if err != nil {
+				diags.AddWarning(
+					fmt.Sprintf("skipping eol version %s-%s with unknown release date", key, v.Version),
+					fmt.Sprintf("failed to parse release date %q: %v", v.ReleaseDate, err),
+				)
+			}
+			if ok {
+				fev = append(fev, v)
+			}
+		}
+		for _, v := range vproto.Versions {
+			ok, err := window.contains(v.ReleaseDate)
+			if err != nil {
+				diags.AddWarning(
+					fmt.Sprintf("skipping version %s-%s with unknown release date", key, v.Version),
+					fmt.Sprintf("failed to parse release date %q: %v", v.ReleaseDate, err),
+				)
+			}
+			if ok {
+				fv = append(fv, v)
+			}
+		}
+
+		vproto.EolVersions = fev
+		vproto.Versions = fv
+	}
+
 	// everything below is for backwards compatibility with the versions module
 
 	vmap := make(map[string]versionsDataSourceVersionMapModel)
@@ -451,20 +573,33 @@ func calculate(ctx context.Context, client registry.RegistryClient, pkg string,
 	return vproto, vmap, orderedKeys, diags
 }
 
+// countVersions returns the number of supported and EOL versions in vmap.
+func countVersions(vmap map[string]versionsDataSourceVersionMapModel) (supported, eol int64) {
+	for _, v := range vmap {
+		if v.Eol {
+			eol++
+		} else {
+			supported++
+		}
+	}
+	return supported, eol
+}
+
 // returns whether we are eol, whether we are in the grace period window, and any error.
+// Dates are whole UTC days, so a version is supported through the end of its
+// EOL date, and inside its grace period through the end of its last day.
 func checkEOLGracePeriodWindow(eolDate string, gracePeriodMonths int64) (bool, bool, error) {
 	t, err := time.Parse(time.DateOnly, eolDate)
 	if err != nil {
 		return false, false, err
 	}
-	// Take the parsed EOL date, fast forward it to X months in the future
-	// and ensure that it is greater than or equal to right now.
-	eol := t.AddDate(0, int(gracePeriodMonths), 0)
-	now := time.Now().UTC()
-
-	// We are EOL if the EOL date is before the current time.
-	// We are in the grace period window if the EOL grace period date is after the current time.
-	return t.Before(now), eol.After(now), nil
+	// The first instant after the EOL day, and after the last day of the
+	// grace period, i.e. X months after the EOL day.
+	eolEnd := t.AddDate(0, 0, 1)
+	graceEnd := t.AddDate(0, int(gracePeriodMonths), 1)
+	now := timeNow().UTC()
+
+	return !now.Before(eolEnd), now.Before(graceEnd), nil
 }
 
 // Variant validates the string value is a valid variant.
@@ -7,11 +7,17 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -321,7 +327,7 @@ func Test_calculate(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			_, versionsMap, orderedKeys, diagnostic := calculate(ctx, testClient, test.pkg, test.variant, test.allow)
+			_, versionsMap, orderedKeys, diagnostic := calculate(ctx, testClient, test.pkg, test.variant, test.allow, releaseWindow{})
 			if !diagnostic.HasError() && test.wantError {
 				t.Fatalf("%s: wanted error/diag returned but was nil", test.name)
 			}
@@ -337,3 +343,318 @@ func Test_calculate(t *testing.T) {
 		})
 	}
 }
+
+func TestVersionsDataSource_OmitLegacy(t *testing.T) {
+	clients := &platformtest.MockPlatformClients{
+		RegistryClient: registrytest.MockRegistryClients{
+			RegistryClient: registrytest.MockRegistryClient{
+				OnGetPackageVersionMetadata: []registrytest.PackageVersionMetadataOnGet{{
+					Given: &registry.PackageVersionMetadataRequest{Package: "found"},
+					Get: &registry.PackageVersionMetadata{
+						LatestVersion: "3.13",
+						Versions: []*registry.PackageVersion{
+							{EolDate: "2929-10-31", Exists: true, Version: "3.13"},
+							{EolDate: "2928-10-31", Exists: true, Version: "3.12"},
+						},
+					},
+				}},
+			},
+		},
+	}
+
+	for _, omit := range []bool{false, true} {
+		t.Run(fmt.Sprintf("omit=%t", omit), func(t *testing.T) {
+			ctx := context.Background()
+			d := &versionsDataSource{dataSource{prov: &providerData{client: clients, omitLegacyVersions: omit}}}
+
+			config := testDataSourceConfig(t, d, map[string]any{"package": "found"})
+
+			resp := &datasource.ReadResponse{State: tfsdk.State{Schema: config.Schema, Raw: config.Raw}}
+			d.Read(ctx, datasource.ReadRequest{Config: config}, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Read() = %v", resp.Diagnostics)
+			}
+
+			var got versionsDataSourceModel
+			if diags := resp.State.Get(ctx, &got); diags.HasError() {
+				t.Fatalf("State.Get() = %v", diags)
+			}
+			if got.Versions == nil || got.Versions.LatestVersion != "3.13" {
+				t.Errorf("versions = %+v, wanted latest_version 3.13", got.Versions)
+			}
+			if gotLegacy := got.VersionMap != nil || got.OrderedKeys != nil; gotLegacy == omit {
+				t.Errorf("version_map = %v, ordered_keys = %v, wanted omitted = %t", got.VersionMap, got.OrderedKeys, omit)
+			}
+
+			// json is the versions output, using the proto's field names.
+			var fromJSON versionsDataSourceProtoModel
+			if err := json.Unmarshal([]byte(got.JSON.ValueString()), &fromJSON); err != nil {
+				t.Fatalf("json = %q is not valid: %v", got.JSON.ValueString(), err)
+			}
+			if diff := cmp.Diff(got.Versions, &fromJSON); diff != "" {
+				t.Errorf("json (-versions +json):\n%s", diff)
+			}
+			if !strings.Contains(got.JSON.ValueString(), `"latestVersion":"3.13"`) {
+				t.Errorf("json = %s, wanted latestVersion key", got.JSON.ValueString())
+			}
+		})
+	}
+}
+
+func TestVersionsDataSource_Fips(t *testing.T) {
+	clients := &platformtest.MockPlatformClients{
+		RegistryClient: registrytest.MockRegistryClients{
+			RegistryClient: registrytest.MockRegistryClient{
+				OnGetPackageVersionMetadata: []registrytest.PackageVersionMetadataOnGet{{
+					Given: &registry.PackageVersionMetadataRequest{Package: "found"},
+					Get: &registry.PackageVersionMetadata{
+						Versions: []*registry.PackageVersion{
+							{EolDate: "2929-10-31", Exists: true, Fips: true, Version: "3.13"},
+							{EolDate: "2928-10-31", Exists: true, Version: "3.12"},
+						},
+					},
+				}},
+			},
+		},
+	}
+
+	read := func(t *testing.T, config map[string]any) (versionsDataSourceModel, diag.Diagnostics) {
+		t.Helper()
+		ctx := context.Background()
+		d := &versionsDataSource{dataSource{prov: &providerData{client: clients}}}
+
+		cfg := testDataSourceConfig(t, d, config)
+
+		resp := &datasource.ReadResponse{State: tfsdk.State{Schema: cfg.Schema, Raw: cfg.Raw}}
+		d.Read(ctx, datasource.ReadRequest{Config: cfg}, resp)
+		var got versionsDataSourceModel
+		if !resp.Diagnostics.HasError() {
+			resp.Diagnostics.Append(resp.State.Get(ctx, &got)...)
+		}
+		return got, resp.Diagnostics
+	}
+
+	byVariant, diags := read(t, map[string]any{"package": "found", "variant": "fips"})
+	if diags.HasError() {
+		t.Fatalf("Read(variant = fips) = %v", diags)
+	}
+	byFips, diags := read(t, map[string]any{"package": "found", "fips": true})
+	if diags.HasError() {
+		t.Fatalf("Read(fips = true) = %v", diags)
+	}
+	if diff := cmp.Diff(byVariant.VersionMap, byFips.VersionMap); diff != "" {
+		t.Errorf("version_map (-variant +fips):\n%s", diff)
+	}
+	if diff := cmp.Diff(byVariant.OrderedKeys, byFips.OrderedKeys); diff != "" {
+		t.Errorf("ordered_keys (-variant +fips):\n%s", diff)
+	}
+	if diff := cmp.Diff(byVariant.Versions, byFips.Versions); diff != "" {
+		t.Errorf("versions (-variant +fips):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"found-fips-3.13"}, byFips.OrderedKeys); diff != "" {
+		t.Errorf("ordered_keys (-want +got):\n%s", diff)
+	}
+
+	// fips = false leaves the variant alone.
+	plain, diags := read(t, map[string]any{"package": "found", "fips": false})
+	if diags.HasError() {
+		t.Fatalf("Read(fips = false) = %v", diags)
+	}
+	if diff := cmp.Diff([]string{"found-3.12", "found-3.13"}, plain.OrderedKeys); diff != "" {
+		t.Errorf("ordered_keys (-want +got):\n%s", diff)
+	}
+}
+
+func Test_calculate_releaseWindow(t *testing.T) {
+	clients := &platformtest.MockPlatformClients{
+		RegistryClient: registrytest.MockRegistryClients{
+			RegistryClient: registrytest.MockRegistryClient{
+				OnGetPackageVersionMetadata: []registrytest.PackageVersionMetadataOnGet{{
+					Given: &registry.PackageVersionMetadataRequest{Package: "found"},
+					Get: &registry.PackageVersionMetadata{
+						GracePeriodMonths: 6,
+						EolVersions: []*registry.PackageVersion{
+							{EolDate: "2924-10-07", Exists: true, Version: "3.9", ReleaseDate: "2020-10-05"},
+						},
+						Versions: []*registry.PackageVersion{
+							{EolDate: "2929-10-31", Exists: true, Version: "3.13", ReleaseDate: "2024-10-07"},
+							{EolDate: "2928-10-31", Exists: true, Version: "3.12", ReleaseDate: "2023-10-02"},
+							{EolDate: "2927-10-31", Exists: true, Version: "3.11", ReleaseDate: "2022-10-24"},
+							{EolDate: "2926-10-31", Exists: true, Version: "3.10"},
+						},
+					},
+				}},
+			},
+		},
+	}
+	date := func(s string) time.Time {
+		t.Helper()
+		d, err := time.Parse(time.DateOnly, s)
+		if err != nil {
+			t.Fatalf("time.Parse() = %v", err)
+		}
+		return d
+	}
+
+	tests := []struct {
+		name         string
+		window       releaseWindow
+		want         []string
+		wantWarnings int
+	}{{
+		name: "open",
+		want: []string{"found-3.9", "found-3.10", "found-3.11", "found-3.12", "found-3.13"},
+	}, {
+		name:         "after",
+		window:       releaseWindow{after: date("2023-01-01")},
+		want:         []string{"found-3.12", "found-3.13"},
+		wantWarnings: 1,
+	}, {
+		name:         "before",
+		window:       releaseWindow{before: date("2022-12-31")},
+		want:         []string{"found-3.9", "found-3.11"},
+		wantWarnings: 1,
+	}, {
+		name:         "between, inclusive",
+		window:       releaseWindow{after: date("2022-10-24"), before: date("2023-10-02")},
+		want:         []string{"found-3.11", "found-3.12"},
+		wantWarnings: 1,
+	}, {
+		name:         "empty",
+		window:       releaseWindow{after: date("2025-01-01")},
+		want:         []string{},
+		wantWarnings: 1,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			vproto, _, orderedKeys, diags := calculate(context.Background(), clients.Registry().Registry(), "found", "", nil, test.window)
+			if diags.HasError() {
+				t.Fatalf("calculate() = %v", diags)
+			}
+			if diff := cmp.Diff(test.want, orderedKeys); diff != "" {
+				t.Errorf("ordered keys (-want +got):\n%s", diff)
+			}
+			if got := len(vproto.Versions) + len(vproto.EolVersions); got != len(test.want) {
+				t.Errorf("versions = %d, wanted %d", got, len(test.want))
+			}
+			if got := diags.WarningsCount(); got != test.wantWarnings {
+				t.Errorf("warnings = %d, wanted %d: %v", got, test.wantWarnings, diags)
+			}
+		})
+	}
+}
+
+func Test_countVersions(t *testing.T) {
+	eolDate := time.Now().Add(-30 * 24 * time.Hour).Format(time.DateOnly)
+	clients := &platformtest.MockPlatformClients{
+		RegistryClient: registrytest.MockRegistryClients{
+			RegistryClient: registrytest.MockRegistryClient{
+				OnGetPackageVersionMetadata: []registrytest.PackageVersionMetadataOnGet{{
+					Given: &registry.PackageVersionMetadataRequest{Package: "found"},
+					Get: &registry.PackageVersionMetadata{
+						GracePeriodMonths: 6,
+						EolVersions: []*registry.PackageVersion{
+							// EOL, inside the grace period.
+							{EolDate: eolDate, Exists: true, Version: "3.8"},
+							// EOL, outside the grace period.
+							{EolDate: "2001-06-27", Exists: true, Version: "3.7"},
+							// Broken.
+							{EolDate: eolDate, Exists: true, Version: "3.6", EolBroken: true},
+						},
+						Versions: []*registry.PackageVersion{
+							{EolDate: "2929-10-31", Exists: true, Version: "3.13"},
+							{EolDate: "2928-10-31", Exists: true, Version: "3.12"},
+							// Doesn't exist.
+							{EolDate: "2927-10-31", Version: "3.11"},
+						},
+					},
+				}},
+			},
+		},
+	}
+
+	_, vmap, orderedKeys, diags := calculate(context.Background(), clients.Registry().Registry(), "found", "", nil, releaseWindow{})
+	if diags.HasError() {
+		t.Fatalf("calculate() = %v", diags)
+	}
+
+	supported, eol := countVersions(vmap)
+	if supported != 2 || eol != 1 {
+		t.Errorf("countVersions() = (%d, %d), wanted (2, 1)", supported, eol)
+	}
+	if got := int(supported + eol); got != len(orderedKeys) {
+		t.Errorf("countVersions() total = %d, wanted %d to match ordered keys %v", got, len(orderedKeys), orderedKeys)
+	}
+}
+
+func Test_checkEOLGracePeriodWindow(t *testing.T) {
+	t.Cleanup(func() { timeNow = time.Now })
+
+	tests := []struct {
+		name        string
+		now         string
+		eolDate     string
+		wantEOL     bool
+		wantInGrace bool
+	}{{
+		name:        "before the EOL date",
+		now:         "2024-06-14T12:00:00Z",
+		eolDate:     "2024-06-15",
+		wantInGrace: true,
+	}, {
+		name:        "start of the EOL date",
+		now:         "2024-06-15T00:00:00Z",
+		eolDate:     "2024-06-15",
+		wantInGrace: true,
+	}, {
+		name:        "end of the EOL date",
+		now:         "2024-06-15T23:59:59Z",
+		eolDate:     "2024-06-15",
+		wantInGrace: true,
+	}, {
+		name:        "EOL date in another timezone",
+		now:         "2024-06-15T20:00:00-05:00", // 2024-06-16T01:00:00Z
+		eolDate:     "2024-06-15",
+		wantEOL:     true,
+		wantInGrace: true,
+	}, {
+		name:        "day after the EOL date",
+		now:         "2024-06-16T00:00:00Z",
+		eolDate:     "2024-06-15",
+		wantEOL:     true,
+		wantInGrace: true,
+	}, {
+		name:        "end of the last day of grace",
+		now:         "2024-12-15T23:59:59Z",
+		eolDate:     "2024-06-15",
+		wantEOL:     true,
+		wantInGrace: true,
+	}, {
+		name:    "day after the grace period",
+		now:     "2024-12-16T00:00:00Z",
+		eolDate: "2024-06-15",
+		wantEOL: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			now, err := time.Parse(time.RFC3339, test.now)
+			if err != nil {
+				t.Fatalf("time.Parse() = %v", err)
+			}
+			timeNow = func() time.Time { return now }
+
+			isEOL, inGrace, err := checkEOLGracePeriodWindow(test.eolDate, 6)
+			if err != nil {
+				t.Fatalf("checkEOLGracePeriodWindow() = %v", err)
+			}
+			if isEOL != test.wantEOL {
+				t.Errorf("checkEOLGracePeriodWindow() eol = %t, wanted %t", isEOL, test.wantEOL)
+			}
+			if inGrace != test.wantInGrace {
+				t.Errorf("checkEOLGracePeriodWindow() in grace = %t, wanted %t", inGrace, test.wantInGrace)
+			}
+		})
+	}
+}
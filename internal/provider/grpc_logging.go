@@ -0,0 +1,36 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provider
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// grpcDebugEnabled reports whether gRPC call logging was requested with EnvChainguardDebugGRPC.
+func grpcDebugEnabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv(EnvChainguardDebugGRPC))
+	return enabled
+}
+
+// loggingUnaryInterceptor logs the method, latency and status code of each unary gRPC call.
+// Request and response payloads are never logged since they may contain credentials.
+func loggingUnaryInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	start := time.Now()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	tflog.Debug(ctx, "grpc call", map[string]any{
+		"grpc.method":      method,
+		"grpc.duration_ms": time.Since(start).Milliseconds(),
+		"grpc.code":        status.Code(err).String(),
+	})
+	return err
+}
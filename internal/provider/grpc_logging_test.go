@@ -0,0 +1,61 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLoggingUnaryInterceptor(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &buf)
+
+	const method = "/chainguard.platform.registry.Registry/ListRepos"
+	var called bool
+	invoker := func(_ context.Context, m string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		called = true
+		if m != method {
+			t.Errorf("invoker method = %q, wanted %q", m, method)
+		}
+		return status.Error(codes.NotFound, "secret-payload")
+	}
+
+	err := loggingUnaryInterceptor(ctx, method, "secret-request", nil, nil, invoker)
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("loggingUnaryInterceptor() = %v, wanted the invoker's error", err)
+	}
+	if !called {
+		t.Fatal("invoker was not called")
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&buf)
+	if err != nil {
+		t.Fatalf("MultilineJSONDecode() = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("got %d log entries, wanted 1: %v", len(entries), entries)
+	}
+	entry := entries[0]
+	if got := entry["grpc.method"]; got != method {
+		t.Errorf("grpc.method = %v, wanted %q", got, method)
+	}
+	if got := entry["grpc.code"]; got != codes.NotFound.String() {
+		t.Errorf("grpc.code = %v, wanted %q", got, codes.NotFound.String())
+	}
+	if _, ok := entry["grpc.duration_ms"]; !ok {
+		t.Error("grpc.duration_ms was not logged")
+	}
+	if bytes.Contains(buf.Bytes(), []byte("secret")) {
+		t.Errorf("log contains request or response payload: %s", buf.String())
+	}
+}
@@ -6,11 +6,37 @@ SPDX-License-Identifier: Apache-2.0
 package provider
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"github.com/sigstore/cosign/v2/pkg/providers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"chainguard.dev/sdk/proto/platform"
+	"github.com/chainguard-dev/terraform-provider-chainguard/internal/token"
 )
 
 var (
@@ -34,3 +60,651 @@ func testAccPreCheck(t *testing.T) {
 		}
 	}
 }
+
+// TestProviderSchemas checks that every resource and data source registered
+// with the provider has a valid, documented schema under the provider's
+// type name prefix.
+func TestProviderSchemas(t *testing.T) {
+	ctx := context.Background()
+	p := New("test")()
+
+	var mresp provider.MetadataResponse
+	p.Metadata(ctx, provider.MetadataRequest{}, &mresp)
+	prefix := mresp.TypeName + "_"
+
+	for _, f := range p.Resources(ctx) {
+		r := f()
+		var md resource.MetadataResponse
+		r.Metadata(ctx, resource.MetadataRequest{ProviderTypeName: mresp.TypeName}, &md)
+		t.Run(md.TypeName, func(t *testing.T) {
+			if !strings.HasPrefix(md.TypeName, prefix) {
+				t.Errorf("type name %q does not start with %q", md.TypeName, prefix)
+			}
+			var resp resource.SchemaResponse
+			r.Schema(ctx, resource.SchemaRequest{}, &resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Schema() = %v", resp.Diagnostics)
+			}
+			if diags := resp.Schema.ValidateImplementation(ctx); diags.HasError() {
+				t.Errorf("ValidateImplementation() = %v", diags)
+			}
+			if resp.Schema.GetDescription() == "" && resp.Schema.GetMarkdownDescription() == "" {
+				t.Error("schema has no description")
+			}
+			for name, attr := range resp.Schema.Attributes {
+				if attr.GetDescription() == "" && attr.GetMarkdownDescription() == "" {
+					t.Errorf("attribute %q has no description", name)
+				}
+			}
+		})
+	}
+
+	for _, f := range p.DataSources(ctx) {
+		d := f()
+		var md datasource.MetadataResponse
+		d.Metadata(ctx, datasource.MetadataRequest{ProviderTypeName: mresp.TypeName}, &md)
+		t.Run(md.TypeName, func(t *testing.T) {
+			if !strings.HasPrefix(md.TypeName, prefix) {
+				t.Errorf("type name %q does not start with %q", md.TypeName, prefix)
+			}
+			var resp datasource.SchemaResponse
+			d.Schema(ctx, datasource.SchemaRequest{}, &resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Schema() = %v", resp.Diagnostics)
+			}
+			if diags := resp.Schema.ValidateImplementation(ctx); diags.HasError() {
+				t.Errorf("ValidateImplementation() = %v", diags)
+			}
+			if resp.Schema.GetDescription() == "" && resp.Schema.GetMarkdownDescription() == "" {
+				t.Error("schema has no description")
+			}
+			for name, attr := range resp.Schema.Attributes {
+				if attr.GetDescription() == "" && attr.GetMarkdownDescription() == "" {
+					t.Errorf("attribute %q has no description", name)
+				}
+			}
+		})
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	platform := fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH)
+
+	tests := map[string]struct {
+		suffix string
+		want   string
+	}{
+		"no suffix": {
+			want: "terraform-provider-chainguard/1.2.3 " + platform,
+		},
+		"suffix": {
+			suffix: "pipeline/deploy-42",
+			want:   "terraform-provider-chainguard/1.2.3 " + platform + " pipeline/deploy-42",
+		},
+		"whitespace suffix": {
+			suffix: "  ",
+			want:   "terraform-provider-chainguard/1.2.3 " + platform,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := userAgent("terraform-provider-chainguard", "1.2.3", test.suffix); got != test.want {
+				t.Errorf("userAgent() = %q, wanted %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestConfigure_UserAgent(t *testing.T) {
+	ctx := context.Background()
+	p := New("1.2.3")().(*Provider)
+	var sresp provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &sresp)
+
+	// Configure the same provider as two aliases with their own suffixes.
+	configure := func(suffix string) *providerData {
+		t.Helper()
+		// Config has no setters, so populate it by way of State.
+		state := tfsdk.State{
+			Schema: sresp.Schema,
+			Raw:    tftypes.NewValue(sresp.Schema.Type().TerraformType(ctx), nil),
+		}
+		if diags := state.SetAttribute(ctx, path.Root("user_agent_suffix"), suffix); diags.HasError() {
+			t.Fatalf("SetAttribute(user_agent_suffix) = %v", diags)
+		}
+		resp := &provider.ConfigureResponse{}
+		p.Configure(ctx, provider.ConfigureRequest{Config: tfsdk.Config{Schema: state.Schema, Raw: state.Raw}}, resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("Configure() = %v", resp.Diagnostics)
+		}
+		return resp.ResourceData.(*providerData)
+	}
+	first, second := configure("first"), configure("second")
+
+	platform := fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH)
+	for pd, want := range map[*providerData]string{
+		first:  "terraform-provider-chainguard/1.2.3 " + platform + " first",
+		second: "terraform-provider-chainguard/1.2.3 " + platform + " second",
+	} {
+		if pd.userAgent != want {
+			t.Errorf("userAgent = %q, wanted %q", pd.userAgent, want)
+		}
+		if pd.loginConfig.UserAgent != want {
+			t.Errorf("loginConfig.UserAgent = %q, wanted %q", pd.loginConfig.UserAgent, want)
+		}
+	}
+	if UserAgent != "terraform-provider-chainguard" {
+		t.Errorf("UserAgent = %q, wanted it left unchanged", UserAgent)
+	}
+}
+
+func TestErrorToDiagnostic(t *testing.T) {
+	tests := map[string]struct {
+		err        error
+		wantDetail string
+	}{
+		"non-grpc": {
+			err:        errors.New("boom"),
+			wantDetail: "boom",
+		},
+		"unauthenticated": {
+			err:        status.Error(codes.Unauthenticated, "token expired"),
+			wantDetail: "chainctl auth login",
+		},
+		"resource exhausted": {
+			err:        status.Error(codes.ResourceExhausted, "grpc: received message larger than max (110100480 vs. 104857600)"),
+			wantDetail: "max_message_size_mb",
+		},
+		"other": {
+			err:        status.Error(codes.NotFound, "no such group"),
+			wantDetail: "NotFound: no such group",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := errorToDiagnostic(test.err, "summary")
+			if d.Summary() != "summary" {
+				t.Errorf("Summary() = %q, wanted %q", d.Summary(), "summary")
+			}
+			if !strings.Contains(d.Detail(), test.wantDetail) {
+				t.Errorf("Detail() = %q, wanted it to contain %q", d.Detail(), test.wantDetail)
+			}
+		})
+	}
+}
+
+func TestConfigure_MaxMessageSize(t *testing.T) {
+	ctx := context.Background()
+	p := New("test")().(*Provider)
+	var sresp provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &sresp)
+	state := tfsdk.State{
+		Schema: sresp.Schema,
+		Raw:    tftypes.NewValue(sresp.Schema.Type().TerraformType(ctx), nil),
+	}
+	if diags := state.SetAttribute(ctx, path.Root("max_message_size_mb"), int64(200)); diags.HasError() {
+		t.Fatalf("SetAttribute(max_message_size_mb) = %v", diags)
+	}
+	resp := &provider.ConfigureResponse{}
+	p.Configure(ctx, provider.ConfigureRequest{Config: tfsdk.Config{Schema: state.Schema, Raw: state.Raw}}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Configure() = %v", resp.Diagnostics)
+	}
+	if got, want := resp.ResourceData.(*providerData).maxMessageSize, 200<<20; got != want {
+		t.Errorf("maxMessageSize = %d, wanted %d", got, want)
+	}
+
+	// Unset keeps the platform clients' defaults.
+	if got := testConfigure(ctx, t, nil).maxMessageSize; got != 0 {
+		t.Errorf("maxMessageSize = %d, wanted 0", got)
+	}
+
+	// Values below the default would lower the limit, so are rejected.
+	attr := sresp.Schema.Attributes["max_message_size_mb"].(schema.Int64Attribute)
+	for size, valid := range map[int64]bool{16: false, defaultMaxMessageSizeMB: true, 200: true} {
+		vresp := &validator.Int64Response{}
+		for _, v := range attr.Validators {
+			v.ValidateInt64(ctx, validator.Int64Request{Path: path.Root("max_message_size_mb"), ConfigValue: types.Int64Value(size)}, vresp)
+		}
+		if got := !vresp.Diagnostics.HasError(); got != valid {
+			t.Errorf("max_message_size_mb = %d: valid = %t, wanted %t", size, got, valid)
+		}
+	}
+}
+
+func TestSetupClient_Concurrent(t *testing.T) {
+	var calls atomic.Int32
+	getToken = func(context.Context, token.LoginConfig, bool) ([]byte, error) {
+		calls.Add(1)
+		return []byte("token"), nil
+	}
+	t.Cleanup(func() { getToken = token.Get })
+
+	pd := &providerData{consoleAPI: "https://console-api.example.com"}
+
+	const n = 20
+	var wg sync.WaitGroup
+	clients := make([]platform.Clients, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := pd.setupClient(context.Background()); err != nil {
+				t.Errorf("setupClient() = %v", err)
+			}
+			pd.mu.Lock()
+			defer pd.mu.Unlock()
+			clients[i] = pd.client
+		}(i)
+	}
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("token fetched %d times, wanted 1", got)
+	}
+	for i, c := range clients {
+		if c == nil || c != clients[0] {
+			t.Errorf("goroutine %d got client %v, wanted the shared client %v", i, c, clients[0])
+		}
+	}
+}
+
+func TestProviderData_Token(t *testing.T) {
+	var got []string
+	getToken = func(_ context.Context, cfg token.LoginConfig, _ bool) ([]byte, error) {
+		got = append(got, cfg.Audience)
+		return []byte("token"), nil
+	}
+	t.Cleanup(func() { getToken = token.Get })
+
+	pd := &providerData{
+		consoleAPI:  "https://console-api.example.com",
+		loginConfig: token.LoginConfig{Audience: "https://audience.example.com"},
+	}
+	for _, endpoint := range []string{pd.consoleAPI, "https://apk.example.com"} {
+		if _, err := pd.token(context.Background(), endpoint, false /* forceRefresh */); err != nil {
+			t.Fatalf("token(%q) = %v", endpoint, err)
+		}
+	}
+	want := []string{"https://audience.example.com", "https://apk.example.com"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("token audiences = %v, wanted %v", got, want)
+	}
+}
+
+// Run with -race: reads of the shared client must not race with the swap
+// performed after creating a root group.
+func TestRefreshClient_ConcurrentReads(t *testing.T) {
+	getToken = func(context.Context, token.LoginConfig, bool) ([]byte, error) {
+		return []byte("token"), nil
+	}
+	t.Cleanup(func() { getToken = token.Get })
+
+	pd := &providerData{consoleAPI: "https://console-api.example.com"}
+	if err := pd.setupClient(context.Background()); err != nil {
+		t.Fatalf("setupClient() = %v", err)
+	}
+	original := pd.clients()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if pd.clients() == nil {
+				t.Error("clients() = nil")
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if err := pd.refreshClient(context.Background()); err != nil {
+				t.Errorf("refreshClient() = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if pd.clients() == original {
+		t.Error("refreshClient() did not swap the shared client")
+	}
+}
+
+// fakeAmbient is an ambient credential provider that is only enabled while
+// the test that uses it sets enabled, and fails to provide a token while it
+// sets failing.
+type fakeAmbient struct {
+	enabled, failing *atomic.Bool
+}
+
+func (f fakeAmbient) Enabled(context.Context) bool {
+	return f.enabled.Load()
+}
+
+func (f fakeAmbient) Provide(context.Context, string) (string, error) {
+	if f.failing.Load() {
+		return "", errors.New("no ambient credentials")
+	}
+	return "ambient-token", nil
+}
+
+var (
+	fakeAmbientEnabled  atomic.Bool
+	fakeAmbientFailing  atomic.Bool
+	registerFakeAmbient sync.Once
+)
+
+func TestConfigure_DisableAmbient(t *testing.T) {
+	registerFakeAmbient.Do(func() {
+		providers.Register("fake-ambient", fakeAmbient{enabled: &fakeAmbientEnabled, failing: &fakeAmbientFailing})
+	})
+	fakeAmbientEnabled.Store(true)
+	t.Cleanup(func() { fakeAmbientEnabled.Store(false) })
+
+	// Make sure only the fake provides ambient credentials.
+	t.Setenv("TF_CHAINGUARD_IDENTITY_TOKEN", "")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", "")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN", "")
+
+	tests := map[string]struct {
+		loginOptions map[string]any
+		want         string
+	}{
+		"default": {
+			loginOptions: map[string]any{"identity_token": "explicit-token"},
+			want:         "ambient-token",
+		},
+		"enabled": {
+			loginOptions: map[string]any{"identity_token": "explicit-token", "disable_ambient": false},
+			want:         "ambient-token",
+		},
+		"disabled": {
+			loginOptions: map[string]any{"identity_token": "explicit-token", "disable_ambient": true},
+			want:         "explicit-token",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			pd := testConfigure(context.Background(), t, test.loginOptions)
+			if got := pd.loginConfig.IdentityToken; got != test.want {
+				t.Errorf("IdentityToken = %q, wanted %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestConfigure_AudienceIssuer(t *testing.T) {
+	t.Setenv(EnvChainguardConsoleAPI, "https://console-api.example.dev")
+
+	tests := map[string]struct {
+		env          string
+		loginOptions map[string]any
+		wantAudience string
+		wantIssuer   string
+	}{
+		"derived": {
+			wantAudience: "https://console-api.example.dev",
+			wantIssuer:   "https://issuer.example.dev",
+		},
+		"config": {
+			loginOptions: map[string]any{"audience": "https://api.internal.example", "issuer": "https://auth.internal.example"},
+			wantAudience: "https://api.internal.example",
+			wantIssuer:   "https://auth.internal.example",
+		},
+		"env over config": {
+			env:          "https://env.example.dev",
+			loginOptions: map[string]any{"audience": "https://api.internal.example"},
+			wantAudience: "https://env.example.dev",
+			wantIssuer:   "https://issuer.example.dev",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(EnvChainguardAudience, test.env)
+
+			pd := testConfigure(context.Background(), t, test.loginOptions)
+			if got := pd.loginConfig.Audience; got != test.wantAudience {
+				t.Errorf("Audience = %q, wanted %q", got, test.wantAudience)
+			}
+			if got := pd.loginConfig.Issuer; got != test.wantIssuer {
+				t.Errorf("Issuer = %q, wanted %q", got, test.wantIssuer)
+			}
+		})
+	}
+}
+
+func TestDeriveIssuer(t *testing.T) {
+	tests := map[string]struct {
+		consoleAPI string
+		want       string
+		wantErr    bool
+	}{
+		"default": {
+			consoleAPI: "https://console-api.enforce.dev",
+			want:       "https://issuer.enforce.dev",
+		},
+		"staging": {
+			consoleAPI: "https://console-api.chainops.dev",
+			want:       "https://issuer.chainops.dev",
+		},
+		"port": {
+			consoleAPI: "http://console-api.localhost:8080",
+			want:       "http://issuer.localhost:8080",
+		},
+		"only the hostname": {
+			consoleAPI: "https://console-api.example.dev/console-api",
+			want:       "https://issuer.example.dev/console-api",
+		},
+		"custom hostname": {
+			consoleAPI: "https://api.chainguard.internal",
+			wantErr:    true,
+		},
+		"console-api only in path": {
+			consoleAPI: "https://gateway.example.dev/console-api",
+			wantErr:    true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := deriveIssuer(test.consoleAPI)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("deriveIssuer() error = %v, wanted error %t", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("deriveIssuer() = %q, wanted %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestConfigure_CustomConsoleHostname(t *testing.T) {
+	t.Setenv(EnvChainguardConsoleAPI, "https://api.chainguard.internal")
+	t.Setenv(EnvChainguardAudience, "")
+	t.Setenv("TF_CHAINGUARD_IDENTITY_TOKEN", "")
+	ctx := context.Background()
+
+	// Without an explicit issuer, configuration still succeeds, since it is
+	// only needed to log in.
+	pd := testConfigure(ctx, t, map[string]any{"disabled": true})
+	if got := pd.loginConfig.Issuer; got != "" {
+		t.Errorf("Issuer = %q, wanted none", got)
+	}
+
+	// Logging in fails, pointing at login_options.issuer.
+	pd = testConfigure(ctx, t, map[string]any{"disable_ambient": true, "disable_token_cache": true, "identity_token": "identity-token"})
+	_, err := pd.token(ctx, pd.consoleAPI, false /* forceRefresh */)
+	if !errors.Is(err, token.ErrNoIssuer) {
+		t.Fatalf("token() = %v, wanted %v", err, token.ErrNoIssuer)
+	}
+	if !strings.Contains(err.Error(), "login_options.issuer") {
+		t.Errorf("token() = %v, wanted it to mention login_options.issuer", err)
+	}
+
+	// With one, it is used as-is.
+	pd = testConfigure(ctx, t, map[string]any{"issuer": "https://auth.chainguard.internal"})
+	if got, want := pd.loginConfig.Issuer, "https://auth.chainguard.internal"; got != want {
+		t.Errorf("Issuer = %q, wanted %q", got, want)
+	}
+}
+
+func TestConfigure_TokenSource(t *testing.T) {
+	registerFakeAmbient.Do(func() {
+		providers.Register("fake-ambient", fakeAmbient{enabled: &fakeAmbientEnabled, failing: &fakeAmbientFailing})
+	})
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", "")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN", "")
+
+	const identity = "0123456789abcdef0123456789abcdef01234567/0123456789abcdef"
+	tests := map[string]struct {
+		env          string
+		ambient      bool
+		failing      bool
+		loginOptions map[string]any
+		want         string
+	}{
+		"env": {
+			env:          "secret-env-token",
+			ambient:      true,
+			loginOptions: map[string]any{"identity_token": "secret-explicit-token"},
+			want:         "TF_CHAINGUARD_IDENTITY_TOKEN",
+		},
+		"ambient": {
+			ambient:      true,
+			loginOptions: map[string]any{"identity_token": "secret-explicit-token"},
+			want:         "ambient",
+		},
+		"ambient failed": {
+			ambient:      true,
+			failing:      true,
+			loginOptions: map[string]any{"identity_token": "secret-explicit-token"},
+			want:         "login_options.identity_token",
+		},
+		"ambient failed without identity_token": {
+			ambient: true,
+			failing: true,
+			want:    "login",
+		},
+		"login_options": {
+			loginOptions: map[string]any{"identity_token": "secret-explicit-token", "identity_id": identity},
+			want:         "login_options.identity_token",
+		},
+		"login": {
+			want: "login",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("TF_CHAINGUARD_IDENTITY_TOKEN", test.env)
+			fakeAmbientEnabled.Store(test.ambient)
+			fakeAmbientFailing.Store(test.failing)
+			t.Cleanup(func() {
+				fakeAmbientEnabled.Store(false)
+				fakeAmbientFailing.Store(false)
+			})
+
+			var buf bytes.Buffer
+			testConfigure(tflogtest.RootLogger(context.Background(), &buf), t, test.loginOptions)
+
+			entries, err := tflogtest.MultilineJSONDecode(&buf)
+			if err != nil {
+				t.Fatalf("MultilineJSONDecode() = %v", err)
+			}
+			var found bool
+			for _, entry := range entries {
+				if entry["@message"] != "selected token source" {
+					continue
+				}
+				found = true
+				if got := entry["chainguard.token_source"]; got != test.want {
+					t.Errorf("chainguard.token_source = %v, wanted %q", got, test.want)
+				}
+				if got, want := entry["chainguard.identity_id"], test.loginOptions["identity_id"]; want != nil && got != want {
+					t.Errorf("chainguard.identity_id = %v, wanted %q", got, want)
+				}
+			}
+			if !found {
+				t.Fatalf("token source was not logged: %v", entries)
+			}
+			if bytes.Contains(buf.Bytes(), []byte("secret")) {
+				t.Errorf("log contains an identity token: %s", buf.String())
+			}
+		})
+	}
+}
+
+func TestConfigure_TokenCache(t *testing.T) {
+	dir := t.TempDir()
+	pd := testConfigure(context.Background(), t, map[string]any{"token_cache_dir": dir})
+	if got := pd.loginConfig.CacheDir; got != dir {
+		t.Errorf("CacheDir = %q, wanted %q", got, dir)
+	}
+	if pd.loginConfig.DisableCache {
+		t.Error("DisableCache = true, wanted false")
+	}
+
+	pd = testConfigure(context.Background(), t, map[string]any{"disable_token_cache": true})
+	if !pd.loginConfig.DisableCache {
+		t.Error("DisableCache = false, wanted true")
+	}
+
+	// A directory which cannot be created fails on login_options.token_cache_dir.
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	p := New("test")().(*Provider)
+	var sresp provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &sresp)
+	state := tfsdk.State{
+		Schema: sresp.Schema,
+		Raw:    tftypes.NewValue(sresp.Schema.Type().TerraformType(ctx), nil),
+	}
+	if diags := state.SetAttribute(ctx, path.Root("login_options").AtName("token_cache_dir"), filepath.Join(file, "tokens")); diags.HasError() {
+		t.Fatalf("SetAttribute(token_cache_dir) = %v", diags)
+	}
+	resp := &provider.ConfigureResponse{}
+	p.Configure(ctx, provider.ConfigureRequest{Config: tfsdk.Config{Schema: state.Schema, Raw: state.Raw}}, resp)
+	if got := resp.Diagnostics.ErrorsCount(); got != 1 {
+		t.Fatalf("Configure() errors = %d, wanted 1: %v", got, resp.Diagnostics)
+	}
+	if d, ok := resp.Diagnostics[0].(diag.DiagnosticWithPath); !ok || !d.Path().Equal(path.Root("login_options").AtName("token_cache_dir")) {
+		t.Errorf("Configure() = %v, wanted an error on login_options.token_cache_dir", resp.Diagnostics)
+	}
+}
+
+// testConfigure configures a provider with the given login_options and
+// returns the resulting provider data.
+func testConfigure(ctx context.Context, t *testing.T, loginOptions map[string]any) *providerData {
+	t.Helper()
+	p := New("test")().(*Provider)
+
+	var sresp provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &sresp)
+	// Config has no setters, so populate it by way of State.
+	state := tfsdk.State{
+		Schema: sresp.Schema,
+		Raw:    tftypes.NewValue(sresp.Schema.Type().TerraformType(ctx), nil),
+	}
+	// Setting any attribute, even to null, makes the config non-null.
+	if diags := state.SetAttribute(ctx, path.Root("console_api"), types.StringNull()); diags.HasError() {
+		t.Fatalf("SetAttribute(console_api) = %v", diags)
+	}
+	for name, v := range loginOptions {
+		if diags := state.SetAttribute(ctx, path.Root("login_options").AtName(name), v); diags.HasError() {
+			t.Fatalf("SetAttribute(%s) = %v", name, diags)
+		}
+	}
+
+	resp := &provider.ConfigureResponse{}
+	p.Configure(ctx, provider.ConfigureRequest{Config: tfsdk.Config{Schema: state.Schema, Raw: state.Raw}}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Configure() = %v", resp.Diagnostics)
+	}
+	return resp.ResourceData.(*providerData)
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provider
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+	"google.golang.org/grpc/resolver"
+)
+
+// proxyConfig returns the proxy configuration from the standard environment
+// variables (HTTPS_PROXY, HTTP_PROXY and NO_PROXY), with proxyURL, if set,
+// overriding the proxy to use. NO_PROXY is honored either way.
+func proxyConfig(proxyURL string) *httpproxy.Config {
+	cfg := httpproxy.FromEnvironment()
+	if proxyURL != "" {
+		cfg.HTTPProxy, cfg.HTTPSProxy = proxyURL, proxyURL
+	}
+	return cfg
+}
+
+// proxyDialer returns a gRPC dialer which tunnels connections through the
+// proxy chosen by proxy for each address, using HTTP CONNECT.
+func proxyDialer(proxy func(*url.URL) (*url.URL, error), userAgent string) func(context.Context, string) (net.Conn, error) {
+	return func(ctx context.Context, addr string) (net.Conn, error) {
+		proxyURL, err := proxy(&url.URL{Scheme: "https", Host: addr})
+		if err != nil {
+			return nil, fmt.Errorf("finding proxy for %s: %w", addr, err)
+		}
+
+		var d net.Dialer
+		if proxyURL == nil {
+			return d.DialContext(ctx, "tcp", addr)
+		}
+		proxyAddr, err := proxyAddress(proxyURL)
+		if err != nil {
+			return nil, err
+		}
+		conn, err := d.DialContext(ctx, "tcp", proxyAddr)
+		if err != nil {
+			return nil, fmt.Errorf("dialing proxy %s: %w", proxyURL.Redacted(), err)
+		}
+		tunnel, err := connect(ctx, conn, addr, proxyURL, userAgent)
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("connecting to %s through proxy %s: %w", addr, proxyURL.Redacted(), err)
+		}
+		return tunnel, nil
+	}
+}
+
+// proxyAddress returns the host:port to dial for proxyURL, defaulting the port
+// as HTTP does. The CONNECT request is sent in plaintext, so only http://
+// proxies are supported.
+func proxyAddress(proxyURL *url.URL) (string, error) {
+	switch proxyURL.Scheme {
+	case "http":
+	case "https":
+		return "", fmt.Errorf("proxy %s: https proxies are not supported, use an http:// proxy URL", proxyURL.Redacted())
+	default:
+		return "", fmt.Errorf("proxy %s: unsupported scheme %q, use an http:// proxy URL", proxyURL.Redacted(), proxyURL.Scheme)
+	}
+	if proxyURL.Port() == "" {
+		return net.JoinHostPort(proxyURL.Hostname(), "80"), nil
+	}
+	return proxyURL.Host, nil
+}
+
+// connect asks the proxy on conn to tunnel to addr.
+func connect(ctx context.Context, conn net.Conn, addr string, proxyURL *url.URL, userAgent string) (net.Conn, error) {
+	req := (&http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Host: addr},
+		Host:   addr,
+		Header: http.Header{"User-Agent": {userAgent}},
+	}).WithContext(ctx)
+	if u := proxyURL.User; u != nil {
+		p, _ := u.Password()
+		req.Header.Set("Proxy-Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(u.Username()+":"+p)))
+	}
+
+	// Bound the handshake by ctx, which gRPC gives its connect deadline, so
+	// a proxy which never responds doesn't hang the dial.
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return nil, err
+		}
+	}
+	if err := req.Write(conn); err != nil {
+		return nil, err
+	}
+
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("proxy responded %s", resp.Status)
+	}
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		return nil, err
+	}
+	// The proxy may have sent bytes from the target along with its response.
+	if r.Buffered() != 0 {
+		return &bufferedConn{Conn: conn, r: r}, nil
+	}
+	return conn, nil
+}
+
+// bufferedConn reads from r, which holds bytes already read from Conn.
+type bufferedConn struct {
+	net.Conn
+	r io.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.r.Read(b)
+}
+
+// unresolvedBuilder stands in for the dns resolver when dialing through a
+// proxy, so the proxy is asked for the target hostname rather than addresses
+// which may not resolve, or may resolve differently, outside the proxy.
+type unresolvedBuilder struct{}
+
+func (unresolvedBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	if err := cc.UpdateState(resolver.State{Addresses: []resolver.Address{{Addr: target.Endpoint()}}}); err != nil {
+		return nil, err
+	}
+	return unresolved{}, nil
+}
+
+func (unresolvedBuilder) Scheme() string {
+	return "dns"
+}
+
+type unresolved struct{}
+
+func (unresolved) ResolveNow(resolver.ResolveNowOptions) {}
+
+func (unresolved) Close() {}
@@ -79,7 +79,7 @@ func (r *accountAssociationsResource) Metadata(_ context.Context, req resource.M
 // Schema defines the schema for the resource.
 func (r *accountAssociationsResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "IAM group invite on the Chainguard platform.",
+		Description: "Cloud account associations of an IAM group on the Chainguard platform. A group has a single association, which this resource owns in full: provider blocks added outside of Terraform show up as drift and are removed on the next apply.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Description:   "The id of the account association.",
@@ -133,17 +133,18 @@ func (r *accountAssociationsResource) Schema(_ context.Context, _ resource.Schem
 				Validators: []validator.Object{
 					objectvalidator.AlsoRequires(
 						path.Root("google").AtName("project_id").Expression(),
-						path.Root("google").AtName("project_number").Expression(),
 					),
 				},
 				Attributes: map[string]schema.Attribute{
 					"project_id": schema.StringAttribute{
 						Description: "GCP project id",
-						Optional:    true,
+						Optional:    true, // This attribute is required, but only if the block is defined. See Validators.
 					},
 					"project_number": schema.StringAttribute{
-						Description: "GCP project number",
-						Optional:    true,
+						Description:   "GCP project number. If omitted, it is resolved from the project id by the Chainguard platform.",
+						Optional:      true,
+						Computed:      true,
+						PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 					},
 				},
 			},
@@ -224,6 +225,29 @@ func populateAccountAssociation(ctx context.Context, m accountAssociationsResour
 	return assoc, nil
 }
 
+// resolveGoogleProjectNumber sets a google project_number left unknown in m
+// to the one in assoc, as returned by the API, or null if it has none.
+func resolveGoogleProjectNumber(ctx context.Context, m *accountAssociationsResourceModel, assoc *iam.AccountAssociations) diag.Diagnostics {
+	if m.Google.IsNull() || m.Google.IsUnknown() {
+		return nil
+	}
+	var gm googleAccountModel
+	if diags := m.Google.As(ctx, &gm, basetypes.ObjectAsOptions{}); diags.HasError() {
+		return diags
+	}
+	if !gm.ProjectNumber.IsUnknown() {
+		return nil
+	}
+
+	gm.ProjectNumber = types.StringNull()
+	if n := assoc.GetGoogle().GetProjectNumber(); n != "" {
+		gm.ProjectNumber = types.StringValue(n)
+	}
+	var diags diag.Diagnostics
+	m.Google, diags = types.ObjectValueFrom(ctx, m.Google.AttributeTypes(ctx), gm)
+	return diags
+}
+
 // Create creates the resource and sets the initial Terraform state.
 func (r *accountAssociationsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	// Read the plan data into the resource model.
@@ -240,7 +264,7 @@ func (r *accountAssociationsResource) Create(ctx context.Context, req resource.C
 		return
 	}
 
-	created, err := r.prov.client.IAM().AccountAssociations().Create(ctx, assoc)
+	created, err := r.prov.clients().IAM().AccountAssociations().Create(ctx, assoc)
 	if err != nil {
 		resp.Diagnostics.Append(errorToDiagnostic(err, "failed to create account association"))
 		return
@@ -250,6 +274,7 @@ func (r *accountAssociationsResource) Create(ctx context.Context, req resource.C
 	// Account associations have no "id". They are one per group so we use the
 	// group as id.
 	plan.ID = types.StringValue(created.Group)
+	resp.Diagnostics.Append(resolveGoogleProjectNumber(ctx, &plan, created)...)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
@@ -267,7 +292,7 @@ func (r *accountAssociationsResource) Read(ctx context.Context, req resource.Rea
 	id := state.ID.ValueString()
 	tflog.Info(ctx, fmt.Sprintf("read account association request for group: %s", id))
 
-	assocList, err := r.prov.client.IAM().AccountAssociations().List(ctx, &iam.AccountAssociationsFilter{
+	assocList, err := r.prov.clients().IAM().AccountAssociations().List(ctx, &iam.AccountAssociationsFilter{
 		Group: id,
 	})
 	if err != nil {
@@ -327,14 +352,7 @@ func (r *accountAssociationsResource) Read(ctx context.Context, req resource.Rea
 				return
 			}
 
-			// Assume they're equal, until proven otherwise.
-			update = false
-			for k, sv := range cm.ServiceBindings.Elements() {
-				if v, ok := assoc.Chainguard.ServiceBindings[k]; !ok || v != sv.String() {
-					update = true
-					break
-				}
-			}
+			update = serviceBindingsChanged(cm.ServiceBindings, assoc.Chainguard.ServiceBindings)
 		}
 
 		if update {
@@ -373,6 +391,22 @@ func (r *accountAssociationsResource) Read(ctx context.Context, req resource.Rea
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
+// serviceBindingsChanged reports whether the service bindings in state differ
+// from those returned by the API, regardless of order.
+func serviceBindingsChanged(state types.Map, remote map[string]string) bool {
+	elems := state.Elements()
+	if len(elems) != len(remote) {
+		return true
+	}
+	for k, sv := range elems {
+		s, ok := sv.(types.String)
+		if v, found := remote[k]; !ok || !found || v != s.ValueString() {
+			return true
+		}
+	}
+	return false
+}
+
 // Update updates the resource and sets the updated Terraform state on success.
 func (r *accountAssociationsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	// Read the plan into the resource model.
@@ -383,18 +417,22 @@ func (r *accountAssociationsResource) Update(ctx context.Context, req resource.U
 	}
 	tflog.Info(ctx, fmt.Sprintf("update account association request: group=%s, amazon=%t, google=%t, chainguard=%t", data.Group, !data.Google.IsNull(), !data.Amazon.IsNull(), !data.Chainguard.IsNull()))
 
+	// The API replaces the whole association, which is what we want: Read
+	// brings blocks managed elsewhere into state, so omitting one from the
+	// plan is a visible change that clears it.
 	assoc, diags := populateAccountAssociation(ctx, data)
 	if diags.HasError() {
 		resp.Diagnostics.Append(diags...)
 		return
 	}
 
-	_, err := r.prov.client.IAM().AccountAssociations().Update(ctx, assoc)
+	updated, err := r.prov.clients().IAM().AccountAssociations().Update(ctx, assoc)
 	if err != nil {
 		resp.Diagnostics.Append(errorToDiagnostic(err, "failed to update account associations"))
 		return
 	}
 
+	resp.Diagnostics.Append(resolveGoogleProjectNumber(ctx, &data, updated)...)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
@@ -409,7 +447,7 @@ func (r *accountAssociationsResource) Delete(ctx context.Context, req resource.D
 	id := state.Group.ValueString()
 	tflog.Info(ctx, fmt.Sprintf("delete account associations request for group: %s", id))
 
-	_, err := r.prov.client.IAM().AccountAssociations().Delete(ctx, &iam.DeleteAccountAssociationsRequest{
+	_, err := r.prov.clients().IAM().AccountAssociations().Delete(ctx, &iam.DeleteAccountAssociationsRequest{
 		Group: id,
 	})
 	if err != nil {
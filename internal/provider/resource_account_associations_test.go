@@ -6,14 +6,25 @@ SPDX-License-Identifier: Apache-2.0
 package provider
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"regexp"
 	"testing"
 
 	"chainguard.dev/sdk/uidp"
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	tfresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	iam "chainguard.dev/sdk/proto/platform/iam/v1"
+	iamtest "chainguard.dev/sdk/proto/platform/iam/v1/test"
+	platformtest "chainguard.dev/sdk/proto/platform/test"
 )
 
 func TestAccResourceAccountAssociations(t *testing.T) {
@@ -61,6 +72,22 @@ func TestAccResourceAccountAssociations(t *testing.T) {
 				ImportState:       true,
 				ImportStateVerify: true,
 			},
+			// Importing surfaces the bound identities.
+			{
+				ResourceName: "chainguard_account_associations.example",
+				ImportState:  true,
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if len(states) != 1 {
+						return fmt.Errorf("imported %d resources, wanted 1", len(states))
+					}
+					for _, service := range []string{"INGESTER", "COSIGNED"} {
+						if id := states[0].Attributes["chainguard.service_bindings."+service]; !uidp.Valid(id) {
+							return fmt.Errorf("imported %s binding is not a UIDP: %q", service, id)
+						}
+					}
+					return nil
+				},
+			},
 
 			// Update and Read testing.
 			{
@@ -214,3 +241,255 @@ resource "chainguard_account_associations" "example" {
 `
 	return fmt.Sprintf(tmpl, group, subgroup, name, awsAccount)
 }
+
+func TestAccountAssociationsGoogleProjectIDOnly(t *testing.T) {
+	const (
+		group     = "0123456789abcdef0123456789abcdef01234567"
+		projectID = "my-project"
+	)
+	given := &iam.AccountAssociations{
+		Group:  group,
+		Name:   "example",
+		Google: &iam.AccountAssociations_Google{ProjectId: projectID},
+	}
+
+	tests := map[string]struct {
+		// resolved is the project number filled in by the server.
+		resolved string
+		want     types.String
+	}{
+		"resolved by server": {
+			resolved: "123456789012",
+			want:     types.StringValue("123456789012"),
+		},
+		"not resolved": {
+			want: types.StringNull(),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			created := &iam.AccountAssociations{
+				Group:  group,
+				Name:   "example",
+				Google: &iam.AccountAssociations_Google{ProjectId: projectID, ProjectNumber: test.resolved},
+			}
+			clients := &platformtest.MockPlatformClients{
+				IAMClient: iamtest.MockIAMClient{
+					GroupAccountAssociationsClient: iamtest.MockGroupAccountAssociationsClient{
+						OnCreate: []iamtest.AccountAssociationsOnCreate{{Given: given, Created: created}},
+						OnList: []iamtest.AccountAssociationsOnList{{
+							Given: &iam.AccountAssociationsFilter{Group: group},
+							List:  &iam.AccountAssociationsList{Items: []*iam.AccountAssociations{created}},
+						}},
+					},
+				},
+			}
+			ctx := context.Background()
+			r := &accountAssociationsResource{managedResource{prov: &providerData{client: clients}}}
+
+			plan := testResourcePlan(t, r, map[string]any{
+				"group": group,
+				"name":  "example",
+				"google": googleAccountModel{
+					ProjectID:     types.StringValue(projectID),
+					ProjectNumber: types.StringUnknown(),
+				},
+			})
+			cresp := &tfresource.CreateResponse{State: tfsdk.State{Schema: plan.Schema, Raw: plan.Raw}}
+			r.Create(ctx, tfresource.CreateRequest{Plan: plan}, cresp)
+			if cresp.Diagnostics.HasError() {
+				t.Fatalf("Create() = %v", cresp.Diagnostics)
+			}
+			var got googleAccountModel
+			if diags := cresp.State.GetAttribute(ctx, path.Root("google"), &got); diags.HasError() {
+				t.Fatalf("GetAttribute(google) = %v", diags)
+			}
+			if !got.ProjectNumber.Equal(test.want) {
+				t.Errorf("Create() project_number = %v, wanted %v", got.ProjectNumber, test.want)
+			}
+
+			// Reading back what was created is stable.
+			rresp := &tfresource.ReadResponse{State: cresp.State}
+			r.Read(ctx, tfresource.ReadRequest{State: cresp.State}, rresp)
+			if rresp.Diagnostics.HasError() {
+				t.Fatalf("Read() = %v", rresp.Diagnostics)
+			}
+			if diags := rresp.State.GetAttribute(ctx, path.Root("google"), &got); diags.HasError() {
+				t.Fatalf("GetAttribute(google) = %v", diags)
+			}
+			if !got.ProjectNumber.Equal(test.want) {
+				t.Errorf("Read() project_number = %v, wanted %v", got.ProjectNumber, test.want)
+			}
+		})
+	}
+}
+
+func TestAccountAssociationsOwnership(t *testing.T) {
+	const (
+		group   = "0123456789abcdef0123456789abcdef01234567"
+		account = "123456789012"
+	)
+	amazon := &iam.AccountAssociations_Amazon{Account: account}
+	// The google block was added outside of Terraform.
+	remote := &iam.AccountAssociations{
+		Group:  group,
+		Name:   "example",
+		Amazon: amazon,
+		Google: &iam.AccountAssociations_Google{ProjectId: "my-project", ProjectNumber: "987654321098"},
+	}
+	// Update must send exactly the planned blocks.
+	planned := &iam.AccountAssociations{
+		Group:  group,
+		Name:   "example",
+		Amazon: amazon,
+	}
+	clients := &platformtest.MockPlatformClients{
+		IAMClient: iamtest.MockIAMClient{
+			GroupAccountAssociationsClient: iamtest.MockGroupAccountAssociationsClient{
+				OnList: []iamtest.AccountAssociationsOnList{{
+					Given: &iam.AccountAssociationsFilter{Group: group},
+					List:  &iam.AccountAssociationsList{Items: []*iam.AccountAssociations{remote}},
+				}},
+				OnUpdate: []iamtest.AccountAssociationsOnUpdate{{Given: planned, Updated: planned}},
+			},
+		},
+	}
+	ctx := context.Background()
+	r := &accountAssociationsResource{managedResource{prov: &providerData{client: clients}}}
+
+	config := map[string]any{
+		"id":     group,
+		"group":  group,
+		"name":   "example",
+		"amazon": amazonAccountModel{Account: types.StringValue(account)},
+	}
+
+	// Read surfaces the unmanaged block, so dropping it is a planned change.
+	state := testResourceState(t, r, config)
+	rresp := &tfresource.ReadResponse{State: state}
+	r.Read(ctx, tfresource.ReadRequest{State: state}, rresp)
+	if rresp.Diagnostics.HasError() {
+		t.Fatalf("Read() = %v", rresp.Diagnostics)
+	}
+	var google types.Object
+	if diags := rresp.State.GetAttribute(ctx, path.Root("google"), &google); diags.HasError() {
+		t.Fatalf("GetAttribute(google) = %v", diags)
+	}
+	if google.IsNull() {
+		t.Error("Read() google = null, wanted the block added outside of Terraform")
+	}
+
+	// Updating without it clears it, rather than leaving it in place.
+	plan := testResourcePlan(t, r, config)
+	uresp := &tfresource.UpdateResponse{State: tfsdk.State{Schema: plan.Schema, Raw: plan.Raw}}
+	r.Update(ctx, tfresource.UpdateRequest{Plan: plan, State: rresp.State}, uresp)
+	if uresp.Diagnostics.HasError() {
+		t.Fatalf("Update() = %v", uresp.Diagnostics)
+	}
+	if diags := uresp.State.GetAttribute(ctx, path.Root("google"), &google); diags.HasError() {
+		t.Fatalf("GetAttribute(google) = %v", diags)
+	}
+	if !google.IsNull() {
+		t.Errorf("Update() google = %v, wanted null", google)
+	}
+}
+
+func TestServiceBindingsChanged(t *testing.T) {
+	const (
+		a = "0123456789abcdef0123456789abcdef01234567/000000000000000a"
+		b = "0123456789abcdef0123456789abcdef01234567/000000000000000b"
+	)
+	tests := map[string]struct {
+		state  map[string]string
+		remote map[string]string
+		want   bool
+	}{
+		"identical": {
+			state:  map[string]string{"INGESTER": a, "REGISTRY": b},
+			remote: map[string]string{"REGISTRY": b, "INGESTER": a},
+		},
+		"both empty": {
+			state:  map[string]string{},
+			remote: nil,
+		},
+		"value changed": {
+			state:  map[string]string{"INGESTER": a},
+			remote: map[string]string{"INGESTER": b},
+			want:   true,
+		},
+		"binding added remotely": {
+			state:  map[string]string{"INGESTER": a},
+			remote: map[string]string{"INGESTER": a, "REGISTRY": b},
+			want:   true,
+		},
+		"binding removed remotely": {
+			state:  map[string]string{"INGESTER": a, "REGISTRY": b},
+			remote: map[string]string{"INGESTER": a},
+			want:   true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			state, diags := types.MapValueFrom(context.Background(), types.StringType, test.state)
+			if diags.HasError() {
+				t.Fatalf("MapValueFrom() = %v", diags)
+			}
+			if got := serviceBindingsChanged(state, test.remote); got != test.want {
+				t.Errorf("serviceBindingsChanged() = %t, wanted %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestAccountAssociationsImport(t *testing.T) {
+	const (
+		group    = "0123456789abcdef0123456789abcdef01234567"
+		ingester = group + "/000000000000000a"
+		cosigned = group + "/000000000000000b"
+	)
+	clients := &platformtest.MockPlatformClients{
+		IAMClient: iamtest.MockIAMClient{
+			GroupAccountAssociationsClient: iamtest.MockGroupAccountAssociationsClient{
+				OnList: []iamtest.AccountAssociationsOnList{{
+					Given: &iam.AccountAssociationsFilter{Group: group},
+					List: &iam.AccountAssociationsList{Items: []*iam.AccountAssociations{{
+						Group: group,
+						Name:  "example",
+						Chainguard: &iam.AccountAssociations_Chainguard{
+							ServiceBindings: map[string]string{"INGESTER": ingester, "COSIGNED": cosigned},
+						},
+					}}},
+				}},
+			},
+		},
+	}
+	ctx := context.Background()
+	r := &accountAssociationsResource{managedResource{prov: &providerData{client: clients}}}
+
+	empty := testResourceState(t, r, nil)
+	iresp := &tfresource.ImportStateResponse{State: empty}
+	r.ImportState(ctx, tfresource.ImportStateRequest{ID: group}, iresp)
+	if iresp.Diagnostics.HasError() {
+		t.Fatalf("ImportState() = %v", iresp.Diagnostics)
+	}
+
+	rresp := &tfresource.ReadResponse{State: iresp.State}
+	r.Read(ctx, tfresource.ReadRequest{State: iresp.State}, rresp)
+	if rresp.Diagnostics.HasError() {
+		t.Fatalf("Read() = %v", rresp.Diagnostics)
+	}
+
+	var got map[string]string
+	if diags := rresp.State.GetAttribute(ctx, path.Root("chainguard").AtName("service_bindings"), &got); diags.HasError() {
+		t.Fatalf("GetAttribute(chainguard.service_bindings) = %v", diags)
+	}
+	if diff := cmp.Diff(map[string]string{"INGESTER": ingester, "COSIGNED": cosigned}, got); diff != "" {
+		t.Errorf("service_bindings (-want +got):\n%s", diff)
+	}
+	var gotGroup string
+	if diags := rresp.State.GetAttribute(ctx, path.Root("group"), &gotGroup); diags.HasError() || gotGroup != group {
+		t.Errorf("group = %q, wanted %q: %v", gotGroup, group, diags)
+	}
+}
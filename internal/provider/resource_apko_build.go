@@ -3,22 +3,34 @@ package provider
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
 	apkotypes "chainguard.dev/apko/pkg/build/types"
+	"chainguard.dev/sdk/proto/platform"
 	v1 "chainguard.dev/sdk/proto/platform/common/v1"
 	registry "chainguard.dev/sdk/proto/platform/registry/v1"
 	"github.com/chainguard-dev/terraform-provider-chainguard/internal/validators"
 	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/testing/protocmp"
 	"gopkg.in/yaml.v2"
 )
@@ -26,6 +38,13 @@ import (
 var _ resource.Resource = &BuildResource{}
 var _ resource.ResourceWithImportState = &BuildResource{}
 
+// layerMediaTypes are the OCI layer media types that builds can produce.
+var layerMediaTypes = []string{
+	"application/vnd.oci.image.layer.v1.tar",
+	"application/vnd.oci.image.layer.v1.tar+gzip",
+	"application/vnd.oci.image.layer.v1.tar+zstd",
+}
+
 func NewBuildResource() resource.Resource {
 	return &BuildResource{}
 }
@@ -35,11 +54,20 @@ type BuildResource struct {
 }
 
 type BuildResourceModel struct {
-	Id        types.String `tfsdk:"id"`
-	Repo      types.String `tfsdk:"repo"`
-	Config    types.String `tfsdk:"config"`
-	MediaType types.String `tfsdk:"media_type"`
-	ImageRef  types.String `tfsdk:"image_ref"`
+	Id        types.String   `tfsdk:"id"`
+	Repo      types.String   `tfsdk:"repo"`
+	Config    types.String   `tfsdk:"config"`
+	MediaType types.String   `tfsdk:"media_type"`
+	ImageRef  types.String   `tfsdk:"image_ref"`
+	Ready     types.Bool     `tfsdk:"ready"`
+	Timeouts  timeouts.Value `tfsdk:"timeouts"`
+
+	Annotations     types.Map    `tfsdk:"annotations"`
+	RebuildTriggers types.Map    `tfsdk:"rebuild_triggers"`
+	ResolveOnly     types.Bool   `tfsdk:"resolve_only"`
+	VerifyDigest    types.Bool   `tfsdk:"verify_digest"`
+	LockedConfig    types.String `tfsdk:"locked_config"`
+	Packages        types.List   `tfsdk:"packages"`
 }
 
 func (r *BuildResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -80,14 +108,12 @@ func (r *BuildResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				},
 			},
 			"media_type": schema.StringAttribute{
-				MarkdownDescription: "The layer media type to build.",
+				MarkdownDescription: fmt.Sprintf("The layer media type to build, one of: %v.", layerMediaTypes),
 				Computed:            true,
 				Optional:            true,
 				Required:            false,
 				Default:             stringdefault.StaticString("application/vnd.oci.image.layer.v1.tar+gzip"),
-				Validators:          []validator.String{
-					// TODO(mattmoor): IANA?
-				},
+				Validators:          []validator.String{stringvalidator.OneOf(layerMediaTypes...)},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
@@ -96,6 +122,56 @@ func (r *BuildResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				MarkdownDescription: "The resulting fully-qualified digest (e.g. {repo}@sha256:deadbeef).",
 				Computed:            true,
 			},
+			"ready": schema.BoolAttribute{
+				MarkdownDescription: "Whether the built image can be fetched from the registry yet. The registry is eventually consistent with builds, so this may be false just after a build, and is rechecked on refresh. Dependents which pull the image can gate on it.",
+				Computed:            true,
+			},
+			"verify_digest": schema.BoolAttribute{
+				MarkdownDescription: "When true, refresh checks that the digest in `image_ref` can still be fetched from the registry, and rebuilds the image if it no longer can (e.g. after a change to the repo's tag policy) rather than keep a stale digest. This costs an extra registry call per refresh.",
+				Optional:            true,
+			},
+			"annotations": schema.MapAttribute{
+				MarkdownDescription: "OCI annotations to set on the built image, keyed in reverse domain notation (e.g. `org.opencontainers.image.revision`). These are merged into, and take precedence over, any annotations in `config`.",
+				Optional:            true,
+				ElementType:         types.StringType,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(validators.AnnotationKey()),
+				},
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"rebuild_triggers": schema.MapAttribute{
+				MarkdownDescription: "Arbitrary values that force a rebuild whenever any of them changes, even if `config` does not (e.g. the hash of a file the build depends on).",
+				Optional:            true,
+				ElementType:         types.StringType,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"resolve_only": schema.BoolAttribute{
+				MarkdownDescription: "When true, only resolve the configuration and record the result in `locked_config` and `packages`, without building an image.",
+				Optional:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"locked_config": schema.StringAttribute{
+				MarkdownDescription: "The resolved apko configuration, with package versions locked, of the most recent build (or resolution, when `resolve_only` is set).",
+				Computed:            true,
+			},
+			"packages": schema.ListAttribute{
+				MarkdownDescription: "The locked package set of the resolved configuration. Only populated when `resolve_only` is set.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+			}),
 		},
 	}
 }
@@ -106,15 +182,30 @@ func (r *BuildResource) Create(ctx context.Context, req resource.CreateRequest,
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	// parse yaml to apkotypes.ImageConfiguration
-	ic := &apkotypes.ImageConfiguration{}
-	if err := yaml.Unmarshal([]byte(data.Config.ValueString()), &ic); err != nil {
-		resp.Diagnostics.Append(errorToDiagnostic(err, "failed to parse configuration"))
+
+	createTimeout, diags := data.Timeouts.Create(ctx, 0 /* none */)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withTimeout(ctx, createTimeout)
+	defer cancel()
+
+	if data.ResolveOnly.ValueBool() {
+		resp.Diagnostics.Append(r.resolve(ctx, data)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		tflog.Trace(ctx, "resolved a resource")
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+
+	cfg, diags := data.apkoConfig(ctx)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
 		return
 	}
-	cfg := registry.ToApkoProto(*ic)
 
-	build, err := r.prov.client.Registry().Apko().BuildImage(ctx, &registry.BuildImageRequest{
+	build, err := r.prov.clients().Registry().Apko().BuildImage(ctx, &registry.BuildImageRequest{
 		Config:    cfg,
 		RepoUidp:  data.Repo.ValueString(),
 		MediaType: data.MediaType.ValueString(),
@@ -124,12 +215,15 @@ func (r *BuildResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 	if build.UserError != "" {
-		resp.Diagnostics.Append(diag.NewErrorDiagnostic("error performing build", build.UserError))
+		resp.Diagnostics.Append(userErrorDiagnostic(path.Root("config"), data.Config.ValueString(), build.UserError))
 		return
 	}
 
 	data.Id = types.StringValue(build.BuildReportId)
 	data.ImageRef = types.StringValue(build.Digest)
+	data.LockedConfig = r.lockedConfig(ctx, build.BuildReportId, &resp.Diagnostics)
+	data.Packages = types.ListNull(types.StringType)
+	data.Ready = types.BoolValue(r.imageReady(ctx, data.Repo.ValueString(), build.Digest, buildReadyAttempts))
 
 	tflog.Trace(ctx, "created a resource")
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -142,59 +236,49 @@ func (r *BuildResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	// The id is the BuildReportID, with which we can fetch a significant amount
-	// of metadata about the previous build.  So we should:
-	// 1. Fetch the build report.
-	// 2. Re-resolve the build config.
-	// 3. Compare the locked configurations to see if a rebuild is needed.
+	readTimeout, diags := data.Timeouts.Read(ctx, 0 /* none */)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withTimeout(ctx, readTimeout)
+	defer cancel()
+
+	// Nothing was built, so refresh the resolved configuration instead.
+	if data.ResolveOnly.ValueBool() {
+		resp.Diagnostics.Append(r.resolve(ctx, data)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+
 	if !data.Id.IsNull() {
-		reports, err := r.prov.client.Registry().Registry().ListBuildReports(ctx, &registry.BuildReportFilter{
-			Uidp: &v1.UIDPFilter{
-				DescendantsOf: data.Id.ValueString(),
-			},
-		})
-		if err != nil {
-			// When it's not found it should be an empty list, not an error,
-			// so make this fatal.
-			resp.Diagnostics.Append(errorToDiagnostic(err, "failed to list build reports"))
+		cfg, diags := data.apkoConfig(ctx)
+		if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
 			return
 		}
-		if len(reports.Reports) != 1 {
-			// Force a rebuild
-			data.Id = types.StringNull()
-		} else if report := reports.Reports[0]; report.Config != data.Config.ValueString() {
+		report, diags := currentBuildReport(ctx, r.prov.clients(), data.Id.ValueString(), data.Repo.ValueString(), data.Config.ValueString(), cfg)
+		if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+			return
+		}
+		if report == nil {
 			// Force a rebuild
 			data.Id = types.StringNull()
 		} else {
-			// parse yaml to apkotypes.ImageConfiguration
-			cfgRaw := &apkotypes.ImageConfiguration{}
-			if err := yaml.Unmarshal([]byte(data.Config.ValueString()), &cfgRaw); err != nil {
-				resp.Diagnostics.Append(errorToDiagnostic(err, "failed to parse configuration"))
-				return
-			}
-			cfg := registry.ToApkoProto(*cfgRaw)
-			want, err := r.prov.client.Registry().Apko().ResolveConfig(ctx, &registry.ResolveConfigRequest{
-				Config:   cfg,
-				RepoUidp: data.Repo.ValueString(),
-			})
-			if err != nil {
-				resp.Diagnostics.Append(errorToDiagnostic(err, "failed to resolve configuration"))
-				return
-			}
-
-			gotRaw := &apkotypes.ImageConfiguration{}
-			if err := yaml.Unmarshal([]byte(report.LockedConfig), &gotRaw); err != nil {
-				resp.Diagnostics.Append(errorToDiagnostic(err, "failed to parse configuration"))
-				return
-			}
-			got := registry.ToApkoProto(*gotRaw)
-
-			if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
-				tflog.Trace(ctx, fmt.Sprintf("triggering rebuild due to diff: %s", diff))
-
-				// Force a rebuild
-				data.Id = types.StringNull()
-			}
+			data.LockedConfig = types.StringValue(report.LockedConfig)
+		}
+	}
+	// Builds which weren't ready yet are checked again, once, on refresh.
+	if !data.Id.IsNull() && !data.Ready.ValueBool() && data.ImageRef.ValueString() != "" {
+		data.Ready = types.BoolValue(r.imageReady(ctx, data.Repo.ValueString(), data.ImageRef.ValueString(), 1))
+	} else if !data.Id.IsNull() && data.VerifyDigest.ValueBool() && data.ImageRef.ValueString() != "" {
+		// A ready image which is now missing has moved, so rebuild it. Any
+		// other error may be transient, and isn't worth a rebuild.
+		if err := r.getImageConfig(ctx, data.Repo.ValueString(), data.ImageRef.ValueString()); status.Code(err) == codes.NotFound {
+			tflog.Info(ctx, fmt.Sprintf("triggering rebuild as image %s is no longer found", data.ImageRef.ValueString()))
+			data.Id = types.StringNull()
+			data.Ready = types.BoolValue(false)
 		}
 	}
 
@@ -208,15 +292,29 @@ func (r *BuildResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
-	// parse yaml to apkotypes.ImageConfiguration
-	ic := &apkotypes.ImageConfiguration{}
-	if err := yaml.Unmarshal([]byte(data.Config.ValueString()), &ic); err != nil {
-		resp.Diagnostics.Append(errorToDiagnostic(err, "failed to parse configuration"))
+	updateTimeout, diags := data.Timeouts.Update(ctx, 0 /* none */)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
 		return
 	}
-	cfg := registry.ToApkoProto(*ic)
+	ctx, cancel := withTimeout(ctx, updateTimeout)
+	defer cancel()
 
-	build, err := r.prov.client.Registry().Apko().BuildImage(ctx, &registry.BuildImageRequest{
+	if data.ResolveOnly.ValueBool() {
+		resp.Diagnostics.Append(r.resolve(ctx, data)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		tflog.Trace(ctx, "resolved a resource")
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+
+	cfg, diags := data.apkoConfig(ctx)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	build, err := r.prov.clients().Registry().Apko().BuildImage(ctx, &registry.BuildImageRequest{
 		Config:    cfg,
 		RepoUidp:  data.Repo.ValueString(),
 		MediaType: data.MediaType.ValueString(),
@@ -226,17 +324,241 @@ func (r *BuildResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 	if build.UserError != "" {
-		resp.Diagnostics.Append(diag.NewErrorDiagnostic("error performing build", build.UserError))
+		resp.Diagnostics.Append(userErrorDiagnostic(path.Root("config"), data.Config.ValueString(), build.UserError))
 		return
 	}
 
 	data.Id = types.StringValue(build.BuildReportId)
 	data.ImageRef = types.StringValue(build.Digest)
+	data.LockedConfig = r.lockedConfig(ctx, build.BuildReportId, &resp.Diagnostics)
+	data.Packages = types.ListNull(types.StringType)
+	data.Ready = types.BoolValue(r.imageReady(ctx, data.Repo.ValueString(), build.Digest, buildReadyAttempts))
 
 	tflog.Trace(ctx, "updated a resource")
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
+// apkoConfig parses the configuration, merging in any annotations set on the
+// resource. Annotations on the resource take precedence over those in the
+// configuration.
+func (m *BuildResourceModel) apkoConfig(ctx context.Context) (*registry.ApkoConfig, diag.Diagnostics) {
+	cfg, diags := parseApkoConfig(path.Root("config"), m.Config.ValueString())
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	annotations := make(map[string]string, len(m.Annotations.Elements()))
+	if diags := m.Annotations.ElementsAs(ctx, &annotations, false); diags.HasError() {
+		return nil, diags
+	}
+	if len(annotations) > 0 && cfg.Annotations == nil {
+		cfg.Annotations = make(map[string]string, len(annotations))
+	}
+	for k, v := range annotations {
+		cfg.Annotations[k] = v
+	}
+	return cfg, nil
+}
+
+// parseApkoConfig parses a YAML apko configuration, held by the attribute at
+// p. Parse errors are reported on p, quoting the offending line of config.
+func parseApkoConfig(p path.Path, config string) (*registry.ApkoConfig, diag.Diagnostics) {
+	// parse yaml to apkotypes.ImageConfiguration
+	ic := &apkotypes.ImageConfiguration{}
+	if err := yaml.Unmarshal([]byte(config), &ic); err != nil {
+		line := 0
+		if m := yamlLinePattern.FindStringSubmatch(err.Error()); m != nil {
+			line, _ = strconv.Atoi(m[1])
+		}
+		return nil, diag.Diagnostics{diag.NewAttributeErrorDiagnostic(p, "failed to parse configuration", withConfigLine(err.Error(), config, line))}
+	}
+	return registry.ToApkoProto(*ic), nil
+}
+
+// currentBuildReport returns the report of the build with the given id if
+// that build is still current, or nil if a rebuild is needed.
+//
+// The id is the BuildReportID, with which we can fetch a significant amount
+// of metadata about the previous build.  So we should:
+// 1. Fetch the build report.
+// 2. Re-resolve the build config.
+// 3. Compare the locked configurations to see if a rebuild is needed.
+func currentBuildReport(ctx context.Context, clients platform.Clients, id, repo, config string, cfg *registry.ApkoConfig) (*registry.BuildReport, diag.Diagnostics) {
+	reports, err := clients.Registry().Registry().ListBuildReports(ctx, &registry.BuildReportFilter{
+		Uidp: &v1.UIDPFilter{
+			DescendantsOf: id,
+		},
+	})
+	if err != nil {
+		// When it's not found it should be an empty list, not an error,
+		// so make this fatal.
+		return nil, diag.Diagnostics{errorToDiagnostic(err, "failed to list build reports")}
+	}
+	if len(reports.Reports) != 1 {
+		return nil, nil
+	}
+	report := reports.Reports[0]
+	if report.Config != config {
+		return nil, nil
+	}
+
+	want, err := clients.Registry().Apko().ResolveConfig(ctx, &registry.ResolveConfigRequest{
+		Config:   cfg,
+		RepoUidp: repo,
+	})
+	if err != nil {
+		return nil, diag.Diagnostics{errorToDiagnostic(err, "failed to resolve configuration")}
+	}
+
+	got, diags := parseApkoConfig(path.Root("locked_config"), report.LockedConfig)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		tflog.Trace(ctx, fmt.Sprintf("triggering rebuild due to diff: %s", diff))
+		return nil, nil
+	}
+	return report, nil
+}
+
+var (
+	// yamlLinePattern matches the line number in YAML parse errors, e.g.
+	// "yaml: line 3: mapping values are not allowed in this context" or
+	// "yaml: unmarshal errors:\n  line 6: cannot unmarshal !!map into string".
+	yamlLinePattern = regexp.MustCompile(`\bline (\d+):`)
+	// quotedPattern matches the quoted names (packages, repositories, ...)
+	// that apko includes in resolution errors.
+	quotedPattern = regexp.MustCompile(`"([^"]+)"|'([^']+)'`)
+)
+
+// userErrorDiagnostic turns the UserError of a build into a diagnostic on the
+// config attribute at p. The server builds from the parsed configuration, not
+// its text, so the error is traced back to the first line of config which
+// mentions a name it quotes, if any.
+func userErrorDiagnostic(p path.Path, config, userError string) diag.Diagnostic {
+	line := 0
+search:
+	for _, m := range quotedPattern.FindAllStringSubmatch(userError, -1) {
+		name := m[1] + m[2]
+		for i, l := range strings.Split(config, "\n") {
+			if strings.Contains(l, name) {
+				line = i + 1
+				break search
+			}
+		}
+	}
+	return diag.NewAttributeErrorDiagnostic(p, "error performing build", withConfigLine(userError, config, line))
+}
+
+// withConfigLine appends the given line of config to detail, so the offending
+// field is easy to find. Lines out of range, including 0, are not quoted.
+func withConfigLine(detail, config string, line int) string {
+	lines := strings.Split(config, "\n")
+	if line < 1 || line > len(lines) {
+		return detail
+	}
+	return fmt.Sprintf("%s\n\nAt line %d of config:\n  %d | %s", detail, line, line, lines[line-1])
+}
+
+// lockedConfig fetches the locked configuration from the build report with
+// the given id. The build has already happened, so failing to fetch it is
+// reported as a warning and leaves the attribute null.
+func (r *BuildResource) lockedConfig(ctx context.Context, id string, diags *diag.Diagnostics) types.String {
+	reports, err := r.prov.clients().Registry().Registry().ListBuildReports(ctx, &registry.BuildReportFilter{
+		Uidp: &v1.UIDPFilter{
+			DescendantsOf: id,
+		},
+	})
+	if err != nil {
+		diags.AddWarning("failed to fetch build report", err.Error())
+		return types.StringNull()
+	}
+	if len(reports.Reports) != 1 {
+		diags.AddWarning("failed to fetch build report", fmt.Sprintf("found %d build reports for %s", len(reports.Reports), id))
+		return types.StringNull()
+	}
+	return types.StringValue(reports.Reports[0].LockedConfig)
+}
+
+// resolve resolves the configuration in data without building it, and
+// records the locked configuration and package set in data.
+func (r *BuildResource) resolve(ctx context.Context, data *BuildResourceModel) diag.Diagnostics {
+	cfg, diags := data.apkoConfig(ctx)
+	if diags.HasError() {
+		return diags
+	}
+
+	locked, err := r.prov.clients().Registry().Apko().ResolveConfig(ctx, &registry.ResolveConfigRequest{
+		Config:   cfg,
+		RepoUidp: data.Repo.ValueString(),
+	})
+	if err != nil {
+		return diag.Diagnostics{errorToDiagnostic(err, "failed to resolve configuration")}
+	}
+
+	raw, err := yaml.Marshal(registry.ToApkoNative(locked))
+	if err != nil {
+		return diag.Diagnostics{errorToDiagnostic(err, "failed to marshal locked configuration")}
+	}
+	packages, ds := types.ListValueFrom(ctx, types.StringType, locked.GetContents().GetPackages())
+	if diags.Append(ds...); diags.HasError() {
+		return diags
+	}
+
+	data.Id = types.StringNull()
+	data.ImageRef = types.StringNull()
+	data.Ready = types.BoolValue(false)
+	data.LockedConfig = types.StringValue(string(raw))
+	data.Packages = packages
+	return diags
+}
+
+// buildReadyAttempts and buildReadyBackoff bound how long a build waits for the
+// image to be fetchable from the registry before reporting it as not ready.
+var (
+	buildReadyAttempts = 3
+	buildReadyBackoff  = time.Second
+)
+
+// imageReady reports whether the image at ref, of the form {repo}@{digest},
+// can be fetched from the registry, making up to attempts checks with
+// exponential backoff. Failures only mean the image isn't ready yet.
+func (r *BuildResource) imageReady(ctx context.Context, repo, ref string, attempts int) bool {
+	backoff := buildReadyBackoff
+	for attempt := 1; ; attempt++ {
+		err := r.getImageConfig(ctx, repo, ref)
+		if err == nil {
+			return true
+		}
+		tflog.Debug(ctx, fmt.Sprintf("image %s not ready (attempt %d): %v", ref, attempt, err))
+		if attempt >= attempts {
+			return false
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(backoff):
+			backoff *= 2
+		}
+	}
+}
+
+// getImageConfig fetches the config of the image at ref, of the form
+// {repo}@{digest}, from the registry, returning only the error.
+func (r *BuildResource) getImageConfig(ctx context.Context, repo, ref string) error {
+	_, digest, ok := strings.Cut(ref, "@")
+	if !ok {
+		digest = ref
+	}
+	_, err := r.prov.clients().Registry().Registry().GetImageConfig(ctx, &registry.ImageConfigRequest{
+		RepoId: repo,
+		Digest: digest,
+	})
+	return err
+}
+
 func (r *BuildResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var data *BuildResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
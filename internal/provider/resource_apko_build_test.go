@@ -0,0 +1,698 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	tfresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1 "chainguard.dev/sdk/proto/platform/common/v1"
+	registry "chainguard.dev/sdk/proto/platform/registry/v1"
+	registrytest "chainguard.dev/sdk/proto/platform/registry/v1/test"
+	platformtest "chainguard.dev/sdk/proto/platform/test"
+)
+
+func TestBuildCreate(t *testing.T) {
+	const (
+		repoID   = "0123456789abcdef0123456789abcdef01234567/0123456789abcdef"
+		reportID = repoID + "/0123456789abcdef"
+		config   = `
+contents:
+  packages:
+    - wolfi-base
+`
+	)
+	cfg := &registry.ApkoConfig{
+		Contents:   &registry.ApkoConfig_Contents{Packages: []string{"wolfi-base"}},
+		Accounts:   &registry.ApkoConfig_Accounts{},
+		Entrypoint: &registry.ApkoConfig_Entrypoint{},
+	}
+	locked := &registry.ApkoConfig{
+		Contents: &registry.ApkoConfig_Contents{Packages: []string{"wolfi-base=1-r5", "busybox=1.37.0-r0"}},
+	}
+
+	tests := map[string]struct {
+		resolveOnly  bool
+		wantID       string
+		wantRef      string
+		wantPackages []string
+		wantLocked   string
+		wantReady    bool
+	}{
+		"build": {
+			wantID:     reportID,
+			wantRef:    "cgr.dev/example/repo@sha256:deadbeef",
+			wantLocked: "contents:\n  packages:\n  - wolfi-base=1-r5\n",
+			wantReady:  true,
+		},
+		"resolve only": {
+			resolveOnly:  true,
+			wantPackages: []string{"wolfi-base=1-r5", "busybox=1.37.0-r0"},
+			wantLocked:   "contents:\n  packages:\n  - wolfi-base=1-r5\n  - busybox=1.37.0-r0\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			apko := registrytest.MockApkoClient{}
+			reg := registrytest.MockRegistryClient{}
+			if test.resolveOnly {
+				// No BuildImage mock: any build attempt fails.
+				apko.OnResolveConfig = []registrytest.OnResolveConfig{{
+					Given:  &registry.ResolveConfigRequest{Config: cfg, RepoUidp: repoID},
+					Result: locked,
+				}}
+			} else {
+				apko.OnBuildImage = []registrytest.OnBuildImage{{
+					Given: &registry.BuildImageRequest{
+						Config:    cfg,
+						RepoUidp:  repoID,
+						MediaType: "application/vnd.oci.image.layer.v1.tar+gzip",
+					},
+					Result: &registry.BuildImageResponse{BuildReportId: reportID, Digest: test.wantRef},
+				}}
+				reg.OnListBuildReports = []registrytest.BuildReportsOnList{{
+					Given: &registry.BuildReportFilter{Uidp: &v1.UIDPFilter{DescendantsOf: reportID}},
+					List: &registry.BuildReportList{Reports: []*registry.BuildReport{{
+						Id:           reportID,
+						Config:       config,
+						LockedConfig: test.wantLocked,
+					}}},
+				}}
+				reg.OnGetImageConfig = []registrytest.ImageConfigOnGet{{
+					Given: &registry.ImageConfigRequest{RepoId: repoID, Digest: "sha256:deadbeef"},
+					Get:   &registry.ImageConfig{},
+				}}
+			}
+			clients := &platformtest.MockPlatformClients{
+				RegistryClient: registrytest.MockRegistryClients{ApkoClient: apko, RegistryClient: reg},
+			}
+
+			ctx := context.Background()
+			r := &BuildResource{managedResource{prov: &providerData{client: clients}}}
+			plan := testResourcePlan(t, r, map[string]any{
+				"repo":         repoID,
+				"config":       config,
+				"media_type":   "application/vnd.oci.image.layer.v1.tar+gzip",
+				"resolve_only": test.resolveOnly,
+			})
+			resp := &tfresource.CreateResponse{State: testResourceState(t, r, nil)}
+			r.Create(ctx, tfresource.CreateRequest{Plan: plan}, resp)
+			if len(resp.Diagnostics) != 0 {
+				t.Fatalf("Create() = %v", resp.Diagnostics)
+			}
+
+			var got BuildResourceModel
+			if diags := resp.State.Get(ctx, &got); diags.HasError() {
+				t.Fatalf("State.Get() = %v", diags)
+			}
+			if got.Id.ValueString() != test.wantID {
+				t.Errorf("id = %s, wanted %q", got.Id, test.wantID)
+			}
+			if got.ImageRef.ValueString() != test.wantRef {
+				t.Errorf("image_ref = %s, wanted %q", got.ImageRef, test.wantRef)
+			}
+			var packages []string
+			if diags := got.Packages.ElementsAs(ctx, &packages, false); diags.HasError() {
+				t.Fatalf("ElementsAs() = %v", diags)
+			}
+			if diff := cmp.Diff(test.wantPackages, packages); diff != "" {
+				t.Errorf("packages (-want, +got) = %s", diff)
+			}
+			if got.LockedConfig.ValueString() != test.wantLocked {
+				t.Errorf("locked_config = %s, wanted %q", got.LockedConfig, test.wantLocked)
+			}
+			if got.Ready.ValueBool() != test.wantReady {
+				t.Errorf("ready = %s, wanted %t", got.Ready, test.wantReady)
+			}
+		})
+	}
+}
+
+func TestBuildCreate_Annotations(t *testing.T) {
+	const (
+		repoID   = "0123456789abcdef0123456789abcdef01234567/0123456789abcdef"
+		reportID = repoID + "/0123456789abcdef"
+		config   = `
+contents:
+  packages:
+    - wolfi-base
+annotations:
+  org.opencontainers.image.vendor: Chainguard
+  org.opencontainers.image.revision: overridden
+`
+	)
+	clients := &platformtest.MockPlatformClients{
+		RegistryClient: registrytest.MockRegistryClients{
+			ApkoClient: registrytest.MockApkoClient{
+				OnBuildImage: []registrytest.OnBuildImage{{
+					Given: &registry.BuildImageRequest{
+						Config: &registry.ApkoConfig{
+							Contents:   &registry.ApkoConfig_Contents{Packages: []string{"wolfi-base"}},
+							Accounts:   &registry.ApkoConfig_Accounts{},
+							Entrypoint: &registry.ApkoConfig_Entrypoint{},
+							Annotations: map[string]string{
+								"org.opencontainers.image.vendor":   "Chainguard",
+								"org.opencontainers.image.revision": "5f3c1e2",
+							},
+						},
+						RepoUidp:  repoID,
+						MediaType: "application/vnd.oci.image.layer.v1.tar+gzip",
+					},
+					Result: &registry.BuildImageResponse{BuildReportId: reportID, Digest: "cgr.dev/example/repo@sha256:deadbeef"},
+				}},
+			},
+			RegistryClient: registrytest.MockRegistryClient{
+				OnListBuildReports: []registrytest.BuildReportsOnList{{
+					Given: &registry.BuildReportFilter{Uidp: &v1.UIDPFilter{DescendantsOf: reportID}},
+					List:  &registry.BuildReportList{Reports: []*registry.BuildReport{{Id: reportID, Config: config}}},
+				}},
+				OnGetImageConfig: []registrytest.ImageConfigOnGet{{
+					Given: &registry.ImageConfigRequest{RepoId: repoID, Digest: "sha256:deadbeef"},
+					Get:   &registry.ImageConfig{},
+				}},
+			},
+		},
+	}
+
+	ctx := context.Background()
+	r := &BuildResource{managedResource{prov: &providerData{client: clients}}}
+	plan := testResourcePlan(t, r, map[string]any{
+		"repo":        repoID,
+		"config":      config,
+		"media_type":  "application/vnd.oci.image.layer.v1.tar+gzip",
+		"annotations": map[string]string{"org.opencontainers.image.revision": "5f3c1e2"},
+	})
+	resp := &tfresource.CreateResponse{State: testResourceState(t, r, nil)}
+	r.Create(ctx, tfresource.CreateRequest{Plan: plan}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Create() = %v", resp.Diagnostics)
+	}
+}
+
+func TestBuildReady(t *testing.T) {
+	const (
+		repoID   = "0123456789abcdef0123456789abcdef01234567/0123456789abcdef"
+		reportID = repoID + "/0123456789abcdef"
+		ref      = "cgr.dev/example/repo@sha256:deadbeef"
+		config   = "contents:\n  packages:\n    - wolfi-base\n"
+		locked   = "contents:\n  packages:\n  - wolfi-base=1-r5\n"
+	)
+	buildReadyBackoff = time.Millisecond
+	t.Cleanup(func() { buildReadyBackoff = time.Second })
+
+	cfg := &registry.ApkoConfig{
+		Contents:   &registry.ApkoConfig_Contents{Packages: []string{"wolfi-base"}},
+		Accounts:   &registry.ApkoConfig_Accounts{},
+		Entrypoint: &registry.ApkoConfig_Entrypoint{},
+	}
+	// Resolving again matches the build report, so no rebuild is needed.
+	lockedCfg, diags := parseApkoConfig(path.Root("locked_config"), locked)
+	if diags.HasError() {
+		t.Fatalf("parseApkoConfig() = %v", diags)
+	}
+	notFound := registrytest.ImageConfigOnGet{
+		Given: &registry.ImageConfigRequest{RepoId: repoID, Digest: "sha256:deadbeef"},
+		Error: status.Error(codes.NotFound, "manifest unknown"),
+	}
+	found := registrytest.ImageConfigOnGet{
+		Given: notFound.Given,
+		Get:   &registry.ImageConfig{},
+	}
+	clients := func(imageConfig registrytest.ImageConfigOnGet) *platformtest.MockPlatformClients {
+		return &platformtest.MockPlatformClients{
+			RegistryClient: registrytest.MockRegistryClients{
+				ApkoClient: registrytest.MockApkoClient{
+					OnBuildImage: []registrytest.OnBuildImage{{
+						Given:  &registry.BuildImageRequest{Config: cfg, RepoUidp: repoID, MediaType: "application/vnd.oci.image.layer.v1.tar+gzip"},
+						Result: &registry.BuildImageResponse{BuildReportId: reportID, Digest: ref},
+					}},
+					OnResolveConfig: []registrytest.OnResolveConfig{{
+						Given:  &registry.ResolveConfigRequest{Config: cfg, RepoUidp: repoID},
+						Result: lockedCfg,
+					}},
+				},
+				RegistryClient: registrytest.MockRegistryClient{
+					OnListBuildReports: []registrytest.BuildReportsOnList{{
+						Given: &registry.BuildReportFilter{Uidp: &v1.UIDPFilter{DescendantsOf: reportID}},
+						List:  &registry.BuildReportList{Reports: []*registry.BuildReport{{Id: reportID, Config: config, LockedConfig: locked}}},
+					}},
+					OnGetImageConfig: []registrytest.ImageConfigOnGet{imageConfig},
+				},
+			},
+		}
+	}
+	ready := func(t *testing.T, state tfsdk.State) bool {
+		t.Helper()
+		var got BuildResourceModel
+		if diags := state.Get(context.Background(), &got); diags.HasError() {
+			t.Fatalf("State.Get() = %v", diags)
+		}
+		return got.Ready.ValueBool()
+	}
+
+	ctx := context.Background()
+	r := &BuildResource{managedResource{prov: &providerData{client: clients(notFound)}}}
+	plan := testResourcePlan(t, r, map[string]any{
+		"repo":       repoID,
+		"config":     config,
+		"media_type": "application/vnd.oci.image.layer.v1.tar+gzip",
+	})
+
+	// The image isn't fetchable yet, which isn't an error.
+	cresp := &tfresource.CreateResponse{State: testResourceState(t, r, nil)}
+	r.Create(ctx, tfresource.CreateRequest{Plan: plan}, cresp)
+	if cresp.Diagnostics.HasError() {
+		t.Fatalf("Create() = %v", cresp.Diagnostics)
+	}
+	if ready(t, cresp.State) {
+		t.Fatal("Create() ready = true, wanted false while the image is not found")
+	}
+
+	// Still not ready on refresh.
+	rresp := &tfresource.ReadResponse{State: cresp.State}
+	r.Read(ctx, tfresource.ReadRequest{State: cresp.State}, rresp)
+	if rresp.Diagnostics.HasError() {
+		t.Fatalf("Read() = %v", rresp.Diagnostics)
+	}
+	if ready(t, rresp.State) {
+		t.Fatal("Read() ready = true, wanted false while the image is not found")
+	}
+
+	// Ready once the image is fetchable.
+	r.prov.client = clients(found)
+	rresp = &tfresource.ReadResponse{State: cresp.State}
+	r.Read(ctx, tfresource.ReadRequest{State: cresp.State}, rresp)
+	if rresp.Diagnostics.HasError() {
+		t.Fatalf("Read() = %v", rresp.Diagnostics)
+	}
+	if !ready(t, rresp.State) {
+		t.Fatal("Read() ready = false, wanted true once the image is found")
+	}
+}
+
+func TestBuildVerifyDigest(t *testing.T) {
+	const (
+		repoID   = "0123456789abcdef0123456789abcdef01234567/0123456789abcdef"
+		reportID = repoID + "/0123456789abcdef"
+		rebuilt  = repoID + "/fedcba9876543210"
+		ref      = "cgr.dev/example/repo@sha256:deadbeef"
+		newRef   = "cgr.dev/example/repo@sha256:cafebabe"
+		config   = "contents:\n  packages:\n    - wolfi-base\n"
+		locked   = "contents:\n  packages:\n  - wolfi-base=1-r5\n"
+		gzip     = "application/vnd.oci.image.layer.v1.tar+gzip"
+	)
+	buildReadyBackoff = time.Millisecond
+	t.Cleanup(func() { buildReadyBackoff = time.Second })
+
+	cfg := &registry.ApkoConfig{
+		Contents:   &registry.ApkoConfig_Contents{Packages: []string{"wolfi-base"}},
+		Accounts:   &registry.ApkoConfig_Accounts{},
+		Entrypoint: &registry.ApkoConfig_Entrypoint{},
+	}
+	// Resolving again matches the build report, so only a moved digest
+	// forces a rebuild.
+	lockedCfg, diags := parseApkoConfig(path.Root("locked_config"), locked)
+	if diags.HasError() {
+		t.Fatalf("parseApkoConfig() = %v", diags)
+	}
+	clients := func(imageErr error) *platformtest.MockPlatformClients {
+		return &platformtest.MockPlatformClients{
+			RegistryClient: registrytest.MockRegistryClients{
+				ApkoClient: registrytest.MockApkoClient{
+					OnBuildImage: []registrytest.OnBuildImage{{
+						Given:  &registry.BuildImageRequest{Config: cfg, RepoUidp: repoID, MediaType: gzip},
+						Result: &registry.BuildImageResponse{BuildReportId: rebuilt, Digest: newRef},
+					}},
+					OnResolveConfig: []registrytest.OnResolveConfig{{
+						Given:  &registry.ResolveConfigRequest{Config: cfg, RepoUidp: repoID},
+						Result: lockedCfg,
+					}},
+				},
+				RegistryClient: registrytest.MockRegistryClient{
+					OnListBuildReports: []registrytest.BuildReportsOnList{{
+						Given: &registry.BuildReportFilter{Uidp: &v1.UIDPFilter{DescendantsOf: reportID}},
+						List:  &registry.BuildReportList{Reports: []*registry.BuildReport{{Id: reportID, Config: config, LockedConfig: locked}}},
+					}, {
+						Given: &registry.BuildReportFilter{Uidp: &v1.UIDPFilter{DescendantsOf: rebuilt}},
+						List:  &registry.BuildReportList{Reports: []*registry.BuildReport{{Id: rebuilt, Config: config, LockedConfig: locked}}},
+					}},
+					OnGetImageConfig: []registrytest.ImageConfigOnGet{{
+						Given: &registry.ImageConfigRequest{RepoId: repoID, Digest: "sha256:deadbeef"},
+						Get:   &registry.ImageConfig{},
+						Error: imageErr,
+					}, {
+						Given: &registry.ImageConfigRequest{RepoId: repoID, Digest: "sha256:cafebabe"},
+						Get:   &registry.ImageConfig{},
+					}},
+				},
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		verify      bool
+		imageErr    error
+		wantRebuild bool
+	}{
+		"not verified": {
+			imageErr: status.Error(codes.NotFound, "manifest unknown"),
+		},
+		"verified, still found": {
+			verify: true,
+		},
+		"verified, moved": {
+			verify:      true,
+			imageErr:    status.Error(codes.NotFound, "manifest unknown"),
+			wantRebuild: true,
+		},
+		"verified, transient error": {
+			verify:   true,
+			imageErr: status.Error(codes.Unavailable, "try again"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			r := &BuildResource{managedResource{prov: &providerData{client: clients(test.imageErr)}}}
+			attrs := map[string]any{
+				"id":            reportID,
+				"repo":          repoID,
+				"config":        config,
+				"media_type":    gzip,
+				"image_ref":     ref,
+				"ready":         true,
+				"verify_digest": test.verify,
+			}
+			state := testResourceState(t, r, attrs)
+
+			rresp := &tfresource.ReadResponse{State: state}
+			r.Read(ctx, tfresource.ReadRequest{State: state}, rresp)
+			if rresp.Diagnostics.HasError() {
+				t.Fatalf("Read() = %v", rresp.Diagnostics)
+			}
+			var got BuildResourceModel
+			if diags := rresp.State.Get(ctx, &got); diags.HasError() {
+				t.Fatalf("State.Get() = %v", diags)
+			}
+			// A null id is planned as unknown, which forces a rebuild.
+			if gotRebuild := got.Id.IsNull(); gotRebuild != test.wantRebuild {
+				t.Fatalf("Read() rebuild = %t, wanted %t", gotRebuild, test.wantRebuild)
+			}
+			if !test.wantRebuild {
+				return
+			}
+
+			// The rebuild replaces the stale digest.
+			plan := testResourcePlan(t, r, attrs)
+			uresp := &tfresource.UpdateResponse{State: rresp.State}
+			r.Update(ctx, tfresource.UpdateRequest{Plan: plan, State: rresp.State}, uresp)
+			if uresp.Diagnostics.HasError() {
+				t.Fatalf("Update() = %v", uresp.Diagnostics)
+			}
+			if diags := uresp.State.Get(ctx, &got); diags.HasError() {
+				t.Fatalf("State.Get() = %v", diags)
+			}
+			if got.ImageRef.ValueString() != newRef || got.Id.ValueString() != rebuilt || !got.Ready.ValueBool() {
+				t.Errorf("Update() image_ref, id, ready = %v, %v, %v, wanted %s, %s, true", got.ImageRef, got.Id, got.Ready, newRef, rebuilt)
+			}
+		})
+	}
+}
+
+func TestBuildMaps_RequiresReplace(t *testing.T) {
+	const config = "contents:\n  packages:\n    - wolfi-base\n"
+	ctx := context.Background()
+	r := &BuildResource{}
+
+	var sresp tfresource.SchemaResponse
+	r.Schema(ctx, tfresource.SchemaRequest{}, &sresp)
+
+	value := func(k, v string) types.Map {
+		return types.MapValueMust(types.StringType, map[string]attr.Value{k: types.StringValue(v)})
+	}
+
+	// Each of these forces a rebuild when it changes, even though config
+	// stays the same.
+	tests := map[string]struct {
+		attr    string
+		current types.Map
+		planned types.Map
+		want    bool
+	}{
+		"annotations unchanged": {
+			attr:    "annotations",
+			current: value("org.opencontainers.image.revision", "5f3c1e2"),
+			planned: value("org.opencontainers.image.revision", "5f3c1e2"),
+			want:    false,
+		},
+		"annotations changed": {
+			attr:    "annotations",
+			current: value("org.opencontainers.image.revision", "5f3c1e2"),
+			planned: value("org.opencontainers.image.revision", "9a8b7c6"),
+			want:    true,
+		},
+		"rebuild_triggers unchanged": {
+			attr:    "rebuild_triggers",
+			current: value("packages_hash", "abc123"),
+			planned: value("packages_hash", "abc123"),
+			want:    false,
+		},
+		"rebuild_triggers changed": {
+			attr:    "rebuild_triggers",
+			current: value("packages_hash", "abc123"),
+			planned: value("packages_hash", "def456"),
+			want:    true,
+		},
+		"rebuild_triggers added": {
+			attr:    "rebuild_triggers",
+			current: types.MapNull(types.StringType),
+			planned: value("date", "2025-03-01"),
+			want:    true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mapAttr := sresp.Schema.Attributes[test.attr].(schema.MapAttribute)
+			req := planmodifier.MapRequest{
+				Path:        path.Root(test.attr),
+				StateValue:  test.current,
+				PlanValue:   test.planned,
+				ConfigValue: test.planned,
+				State:       testResourceState(t, r, map[string]any{"config": config, test.attr: test.current}),
+				Plan:        testResourcePlan(t, r, map[string]any{"config": config, test.attr: test.planned}),
+			}
+			resp := &planmodifier.MapResponse{PlanValue: test.planned}
+			for _, m := range mapAttr.PlanModifiers {
+				m.PlanModifyMap(ctx, req, resp)
+			}
+			if resp.RequiresReplace != test.want {
+				t.Errorf("RequiresReplace = %t, wanted %t", resp.RequiresReplace, test.want)
+			}
+		})
+	}
+}
+
+func TestBuildMediaType(t *testing.T) {
+	tests := map[string]bool{
+		"application/vnd.oci.image.layer.v1.tar":      true,
+		"application/vnd.oci.image.layer.v1.tar+gzip": true,
+		"application/vnd.oci.image.layer.v1.tar+zstd": true,
+		"":     false,
+		"gzip": false,
+		"application/vnd.oci.image.layer.v1.tar+bzip2":            false,
+		"application/vnd.docker.image.rootfs.diff.tar.gzip":       false,
+		"application/vnd.oci.image.layer.nondistributable.v1.tar": false,
+	}
+	for _, r := range []tfresource.Resource{&BuildResource{}, &buildsResource{}} {
+		ctx := context.Background()
+		var sresp tfresource.SchemaResponse
+		r.Schema(ctx, tfresource.SchemaRequest{}, &sresp)
+		strAttr := sresp.Schema.Attributes["media_type"].(schema.StringAttribute)
+
+		for mediaType, valid := range tests {
+			t.Run(fmt.Sprintf("%T/%s", r, mediaType), func(t *testing.T) {
+				req := validator.StringRequest{
+					Path:        path.Root("media_type"),
+					ConfigValue: types.StringValue(mediaType),
+				}
+				resp := &validator.StringResponse{}
+				for _, v := range strAttr.Validators {
+					v.ValidateString(ctx, req, resp)
+				}
+				if got := !resp.Diagnostics.HasError(); got != valid {
+					t.Errorf("valid = %t, wanted %t: %v", got, valid, resp.Diagnostics)
+				}
+			})
+		}
+	}
+}
+
+func TestAccResourceApkoBuildRebuildTriggers(t *testing.T) {
+	group := os.Getenv("TF_ACC_GROUP_ID")
+	name := acctest.RandString(10)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceApkoBuildTriggers(group, name, "abc123"),
+				Check:  resource.TestCheckResourceAttr(`chainguard_apko_build.build`, `rebuild_triggers.packages_hash`, "abc123"),
+			},
+			// Changing a trigger rebuilds, although config is unchanged.
+			{
+				Config: testAccResourceApkoBuildTriggers(group, name, "def456"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(`chainguard_apko_build.build`, plancheck.ResourceActionReplace),
+					},
+				},
+				Check: resource.TestCheckResourceAttr(`chainguard_apko_build.build`, `rebuild_triggers.packages_hash`, "def456"),
+			},
+		},
+	})
+}
+
+func testAccResourceApkoBuildTriggers(group, name, hash string) string {
+	tmpl := `
+resource "chainguard_image_repo" "repo" {
+  parent_id = %q
+  name      = %q
+}
+
+resource "chainguard_apko_build" "build" {
+  repo   = chainguard_image_repo.repo.id
+  config = <<EOF
+contents:
+  packages:
+    - wolfi-base
+EOF
+
+  rebuild_triggers = {
+    packages_hash = %q
+  }
+}
+`
+	return fmt.Sprintf(tmpl, group, name, hash)
+}
+
+func TestParseApkoConfig_Error(t *testing.T) {
+	tests := map[string]struct {
+		config     string
+		wantDetail string
+	}{
+		"syntax error": {
+			config:     "contents:\n  packages:\n    - wolfi-base\nentrypoint:\n  command: /bin/sh: -l\n",
+			wantDetail: "yaml: line 5: mapping values are not allowed in this context\n\nAt line 5 of config:\n  5 |   command: /bin/sh: -l",
+		},
+		"wrong type": {
+			config:     "contents:\n  packages:\n    wolfi-base: 1\n",
+			wantDetail: "yaml: unmarshal errors:\n  line 3: cannot unmarshal !!map into []string\n\nAt line 3 of config:\n  3 |     wolfi-base: 1",
+		},
+		"tab indentation": {
+			config:     "contents:\n  packages:\n  - wolfi-base\n\tbad: tab\n",
+			wantDetail: "yaml: line 4: found a tab character that violates indentation\n\nAt line 4 of config:\n  4 | \tbad: tab",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := path.Root("builds").AtMapKey("example").AtName("config")
+			_, diags := parseApkoConfig(p, test.config)
+			if len(diags) != 1 {
+				t.Fatalf("parseApkoConfig() = %v, wanted one diagnostic", diags)
+			}
+			d := diags[0]
+			if d.Severity() != diag.SeverityError {
+				t.Errorf("Severity() = %v, wanted error", d.Severity())
+			}
+			if got := d.Detail(); got != test.wantDetail {
+				t.Errorf("Detail() = %q, wanted %q", got, test.wantDetail)
+			}
+			withPath, ok := d.(diag.DiagnosticWithPath)
+			if !ok {
+				t.Fatalf("diagnostic has no attribute path")
+			}
+			if got := withPath.Path(); !got.Equal(p) {
+				t.Errorf("Path() = %s, wanted %s", got, p)
+			}
+		})
+	}
+}
+
+func TestUserErrorDiagnostic(t *testing.T) {
+	const config = `contents:
+  repositories:
+    - https://packages.wolfi.dev/os
+  packages:
+    - wolfi-base
+    - curlx
+entrypoint:
+  command: /bin/sh -l
+`
+	tests := map[string]struct {
+		userError  string
+		wantDetail string
+	}{
+		"unresolvable package": {
+			userError:  `solving "curlx" constraint: could not find package "curlx" in indexes`,
+			wantDetail: "solving \"curlx\" constraint: could not find package \"curlx\" in indexes\n\nAt line 6 of config:\n  6 |     - curlx",
+		},
+		"single quoted repository": {
+			userError:  "fetching index from 'https://packages.wolfi.dev/os': 404 Not Found",
+			wantDetail: "fetching index from 'https://packages.wolfi.dev/os': 404 Not Found\n\nAt line 3 of config:\n  3 |     - https://packages.wolfi.dev/os",
+		},
+		"line numbers are not config lines": {
+			userError:  "step failed at line 2: exit status 1",
+			wantDetail: "step failed at line 2: exit status 1",
+		},
+		"no context": {
+			userError:  "build timed out",
+			wantDetail: "build timed out",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := userErrorDiagnostic(path.Root("config"), config, test.userError)
+
+			if d.Severity() != diag.SeverityError {
+				t.Errorf("Severity() = %v, wanted error", d.Severity())
+			}
+			if got := d.Detail(); got != test.wantDetail {
+				t.Errorf("Detail() = %q, wanted %q", got, test.wantDetail)
+			}
+			withPath, ok := d.(diag.DiagnosticWithPath)
+			if !ok {
+				t.Fatalf("diagnostic has no attribute path")
+			}
+			if got := withPath.Path(); !got.Equal(path.Root("config")) {
+				t.Errorf("Path() = %s, wanted config", got)
+			}
+		})
+	}
+}
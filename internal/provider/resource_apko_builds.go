@@ -0,0 +1,287 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	registry "chainguard.dev/sdk/proto/platform/registry/v1"
+	"github.com/chainguard-dev/terraform-provider-chainguard/internal/validators"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource               = &buildsResource{}
+	_ resource.ResourceWithConfigure  = &buildsResource{}
+	_ resource.ResourceWithModifyPlan = &buildsResource{}
+)
+
+// NewBuildsResource is a helper function to simplify the provider implementation.
+func NewBuildsResource() resource.Resource {
+	return &buildsResource{}
+}
+
+// buildsResource is the resource implementation.
+type buildsResource struct {
+	managedResource
+}
+
+type buildsResourceModel struct {
+	Repo      types.String                  `tfsdk:"repo"`
+	MediaType types.String                  `tfsdk:"media_type"`
+	Builds    map[string]buildsElementModel `tfsdk:"builds"`
+}
+
+type buildsElementModel struct {
+	ID       types.String `tfsdk:"id"`
+	Config   types.String `tfsdk:"config"`
+	Digest   types.String `tfsdk:"digest"`
+	ImageRef types.String `tfsdk:"image_ref"`
+}
+
+func (r *buildsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.configure(ctx, req, resp)
+}
+
+// Metadata returns the resource type name.
+func (r *buildsResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_apko_builds"
+}
+
+// Schema defines the schema for the resource.
+func (r *buildsResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Performs apko builds of several named configs into the same repository, rebuilding each one independently.",
+		Attributes: map[string]schema.Attribute{
+			"repo": schema.StringAttribute{
+				Description: "The UIDP of the repository in which to build the images.",
+				Required:    true,
+				Validators:  []validator.String{validators.UIDP(false /* allowRootSentinel */)},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"media_type": schema.StringAttribute{
+				Description: fmt.Sprintf("The layer media type to build, one of: %v.", layerMediaTypes),
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("application/vnd.oci.image.layer.v1.tar+gzip"),
+				Validators:  []validator.String{stringvalidator.OneOf(layerMediaTypes...)},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"builds": schema.MapNestedAttribute{
+				Description: "The builds to perform, keyed by a name of your choosing. Builds which fail when the resource is created are reported as warnings and retried on the next apply.",
+				Required:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Description: "The build report UIDP for the most recent build of this config.",
+							Computed:    true,
+						},
+						"config": schema.StringAttribute{
+							Description: "The apko configuration to build.",
+							Required:    true,
+						},
+						"digest": schema.StringAttribute{
+							Description: "The digest of the resulting image (e.g. sha256:deadbeef).",
+							Computed:    true,
+						},
+						"image_ref": schema.StringAttribute{
+							Description: "The resulting fully-qualified digest (e.g. {repo}@sha256:deadbeef).",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// ModifyPlan carries over the results of builds whose config is unchanged, so
+// that only new or changed configs are (re)built.
+func (r *buildsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to carry over on create or destroy.
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan, state buildsResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for name, want := range plan.Builds {
+		current, ok := state.Builds[name]
+		if !ok || current.ID.IsNull() || !want.Config.Equal(current.Config) {
+			continue
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("builds").AtMapKey(name), current)...)
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *buildsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Read the plan data into the resource model.
+	var plan buildsResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Info(ctx, fmt.Sprintf("create apko builds request: repo=%s, count=%d", plan.Repo, len(plan.Builds)))
+
+	// Record every build that succeeded, even if others fail, so that only
+	// the failures are retried.
+	state := buildsResourceModel{
+		Repo:      plan.Repo,
+		MediaType: plan.MediaType,
+		Builds:    make(map[string]buildsElementModel, len(plan.Builds)),
+	}
+	var diags diag.Diagnostics
+	for _, name := range sortedKeys(plan.Builds) {
+		elem, ok := r.build(ctx, plan, name, &diags)
+		if !ok {
+			// Keep the planned build, which must match the config, with no
+			// results. Read drops it so the next apply retries it.
+			elem.ID = types.StringNull()
+			elem.Digest = types.StringNull()
+			elem.ImageRef = types.StringNull()
+		}
+		state.Builds[name] = elem
+	}
+
+	// Failed builds are warnings, so the resource isn't tainted and replaced,
+	// which would rebuild every config rather than only the failures.
+	resp.Diagnostics.Append(asWarnings(diags)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *buildsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Read the current state into the resource model.
+	var state buildsResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Info(ctx, fmt.Sprintf("read apko builds request: repo=%s", state.Repo))
+
+	for _, name := range sortedKeys(state.Builds) {
+		elem := state.Builds[name]
+		if elem.ID.IsNull() {
+			// It failed to build, so drop it from state to retry it.
+			delete(state.Builds, name)
+			continue
+		}
+		cfg, diags := parseApkoConfig(path.Root("builds").AtMapKey(name).AtName("config"), elem.Config.ValueString())
+		if resp.Diagnostics.Append(diags...); diags.HasError() {
+			continue
+		}
+		report, diags := currentBuildReport(ctx, r.prov.clients(), elem.ID.ValueString(), state.Repo.ValueString(), elem.Config.ValueString(), cfg)
+		if resp.Diagnostics.Append(diags...); diags.HasError() {
+			continue
+		}
+		if report == nil {
+			// Drop the stale build from state so it is rebuilt.
+			delete(state.Builds, name)
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Set state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *buildsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state buildsResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Info(ctx, fmt.Sprintf("update apko builds request: repo=%s", plan.Repo))
+
+	// Reconcile one build at a time, starting from the current state so
+	// that it reflects exactly the builds that succeeded. Built images are
+	// left in the registry, so removed entries are simply dropped.
+	if state.Builds == nil {
+		state.Builds = make(map[string]buildsElementModel, len(plan.Builds))
+	}
+	for name := range state.Builds {
+		if _, ok := plan.Builds[name]; !ok {
+			delete(state.Builds, name)
+		}
+	}
+	for _, name := range sortedKeys(plan.Builds) {
+		if current, ok := state.Builds[name]; ok && !current.ID.IsNull() && current.Config.Equal(plan.Builds[name].Config) {
+			continue
+		}
+		if elem, ok := r.build(ctx, plan, name, &resp.Diagnostics); ok {
+			state.Builds[name] = elem
+		}
+	}
+
+	// Set state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Delete removes the resource from the Terraform state.
+func (r *buildsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// As with chainguard_apko_build, built images are left in the registry.
+}
+
+// build builds the config named name, reporting any failure to diags.
+func (r *buildsResource) build(ctx context.Context, plan buildsResourceModel, name string, diags *diag.Diagnostics) (buildsElementModel, bool) {
+	elem := plan.Builds[name]
+	cfg, ds := parseApkoConfig(path.Root("builds").AtMapKey(name).AtName("config"), elem.Config.ValueString())
+	if diags.Append(ds...); ds.HasError() {
+		return elem, false
+	}
+
+	build, err := r.prov.clients().Registry().Apko().BuildImage(ctx, &registry.BuildImageRequest{
+		Config:    cfg,
+		RepoUidp:  plan.Repo.ValueString(),
+		MediaType: plan.MediaType.ValueString(),
+	})
+	if err != nil {
+		diags.Append(errorToDiagnostic(err, fmt.Sprintf("failed to build image %q", name)))
+		return elem, false
+	}
+	if build.UserError != "" {
+		diags.Append(userErrorDiagnostic(path.Root("builds").AtMapKey(name).AtName("config"), elem.Config.ValueString(), build.UserError))
+		return elem, false
+	}
+
+	elem.ID = types.StringValue(build.BuildReportId)
+	elem.ImageRef = types.StringValue(build.Digest)
+	// The digest is returned fully-qualified, so strip the repository.
+	digest := build.Digest
+	if _, d, ok := strings.Cut(build.Digest, "@"); ok {
+		digest = d
+	}
+	elem.Digest = types.StringValue(digest)
+	return elem, true
+}
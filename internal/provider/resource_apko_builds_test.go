@@ -0,0 +1,313 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	tfresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	common "chainguard.dev/sdk/proto/platform/common/v1"
+	registry "chainguard.dev/sdk/proto/platform/registry/v1"
+	registrytest "chainguard.dev/sdk/proto/platform/registry/v1/test"
+	platformtest "chainguard.dev/sdk/proto/platform/test"
+)
+
+func TestAccResourceApkoBuilds(t *testing.T) {
+	group := os.Getenv("TF_ACC_GROUP_ID")
+	name := acctest.RandString(10)
+
+	digestPattern := regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+	// The id of a build left untouched by later steps, which must not change.
+	var baseID string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read
+			{
+				Config: testAccResourceApkoBuilds(group, name, map[string]string{
+					"base": "wolfi-base",
+					"curl": "curl",
+				}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(`chainguard_apko_builds.matrix`, `builds.%`, "2"),
+					resource.TestMatchResourceAttr(`chainguard_apko_builds.matrix`, `builds.base.digest`, digestPattern),
+					resource.TestCheckResourceAttrWith(`chainguard_apko_builds.matrix`, `builds.base.id`, func(v string) error {
+						baseID = v
+						return nil
+					}),
+					resource.TestMatchResourceAttr(`chainguard_apko_builds.matrix`, `builds.curl.digest`, digestPattern),
+				),
+			},
+			// Add one build and remove another. The untouched build is not
+			// rebuilt.
+			{
+				Config: testAccResourceApkoBuilds(group, name, map[string]string{
+					"base": "wolfi-base",
+					"bash": "bash",
+				}),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(`chainguard_apko_builds.matrix`, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(`chainguard_apko_builds.matrix`, `builds.%`, "2"),
+					resource.TestCheckResourceAttrWith(`chainguard_apko_builds.matrix`, `builds.base.id`, func(v string) error {
+						if v != baseID {
+							return fmt.Errorf("build base was rebuilt: id %q, wanted %q", v, baseID)
+						}
+						return nil
+					}),
+					resource.TestMatchResourceAttr(`chainguard_apko_builds.matrix`, `builds.bash.digest`, digestPattern),
+					resource.TestCheckNoResourceAttr(`chainguard_apko_builds.matrix`, `builds.curl.id`),
+				),
+			},
+		},
+	})
+}
+
+func testAccResourceApkoBuilds(group, name string, packages map[string]string) string {
+	keys := make([]string, 0, len(packages))
+	for k := range packages {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var builds strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&builds, `
+    %q = {
+      config = <<EOF
+contents:
+  packages:
+    - %s
+EOF
+    }`, k, packages[k])
+	}
+
+	tmpl := `
+resource "chainguard_image_repo" "repo" {
+  parent_id = %q
+  name      = %q
+}
+
+resource "chainguard_apko_builds" "matrix" {
+  repo   = chainguard_image_repo.repo.id
+  builds = {%s
+  }
+}
+`
+	return fmt.Sprintf(tmpl, group, name, builds.String())
+}
+
+func TestBuildsUpdate(t *testing.T) {
+	const (
+		repoID    = "0123456789abcdef0123456789abcdef01234567/0123456789abcdef"
+		mediaType = "application/vnd.oci.image.layer.v1.tar+gzip"
+		idA       = repoID + "/000000000000000a"
+		idB       = repoID + "/000000000000000b"
+		idC       = repoID + "/000000000000000c"
+	)
+	config := func(pkg string) string {
+		return fmt.Sprintf("contents:\n  packages:\n    - %s\n", pkg)
+	}
+	element := func(id, pkg string) buildsElementModel {
+		if id == "" {
+			return buildsElementModel{
+				ID:       types.StringUnknown(),
+				Config:   types.StringValue(config(pkg)),
+				Digest:   types.StringUnknown(),
+				ImageRef: types.StringUnknown(),
+			}
+		}
+		return buildsElementModel{
+			ID:       types.StringValue(id),
+			Config:   types.StringValue(config(pkg)),
+			Digest:   types.StringValue("sha256:" + pkg),
+			ImageRef: types.StringValue("cgr.dev/example/repo@sha256:" + pkg),
+		}
+	}
+
+	// Only build c is expected: a is unchanged and b is removed.
+	clients := &platformtest.MockPlatformClients{
+		RegistryClient: registrytest.MockRegistryClients{
+			ApkoClient: registrytest.MockApkoClient{
+				OnBuildImage: []registrytest.OnBuildImage{{
+					Given: &registry.BuildImageRequest{
+						Config: &registry.ApkoConfig{
+							Contents:   &registry.ApkoConfig_Contents{Packages: []string{"c"}},
+							Accounts:   &registry.ApkoConfig_Accounts{},
+							Entrypoint: &registry.ApkoConfig_Entrypoint{},
+						},
+						RepoUidp:  repoID,
+						MediaType: mediaType,
+					},
+					Result: &registry.BuildImageResponse{BuildReportId: idC, Digest: "cgr.dev/example/repo@sha256:c"},
+				}},
+			},
+		},
+	}
+
+	ctx := context.Background()
+	r := &buildsResource{managedResource{prov: &providerData{client: clients}}}
+	state := testResourceState(t, r, map[string]any{
+		"repo":       repoID,
+		"media_type": mediaType,
+		"builds": map[string]buildsElementModel{
+			"a": element(idA, "a"),
+			"b": element(idB, "b"),
+		},
+	})
+	plan := testResourcePlan(t, r, map[string]any{
+		"repo":       repoID,
+		"media_type": mediaType,
+		"builds": map[string]buildsElementModel{
+			"a": element(idA, "a"),
+			"c": element("", "c"),
+		},
+	})
+	resp := &tfresource.UpdateResponse{State: state}
+	r.Update(ctx, tfresource.UpdateRequest{Plan: plan, State: state}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Update() = %v", resp.Diagnostics)
+	}
+
+	var got buildsResourceModel
+	if diags := resp.State.Get(ctx, &got); diags.HasError() {
+		t.Fatalf("State.Get() = %v", diags)
+	}
+	want := map[string]buildsElementModel{
+		"a": element(idA, "a"),
+		"c": element(idC, "c"),
+	}
+	if len(got.Builds) != len(want) {
+		t.Fatalf("state has %d builds, wanted %d: %v", len(got.Builds), len(want), got.Builds)
+	}
+	for name, w := range want {
+		if g := got.Builds[name]; g != w {
+			t.Errorf("build %q = %v, wanted %v", name, g, w)
+		}
+	}
+}
+
+func TestBuildsCreate_PartialFailure(t *testing.T) {
+	const (
+		repoID    = "0123456789abcdef0123456789abcdef01234567/0123456789abcdef"
+		mediaType = "application/vnd.oci.image.layer.v1.tar+gzip"
+		idA       = repoID + "/000000000000000a"
+	)
+	config := func(pkg string) string {
+		return fmt.Sprintf("contents:\n  packages:\n    - %s\n", pkg)
+	}
+	apkoConfig := func(pkg string) *registry.ApkoConfig {
+		return &registry.ApkoConfig{
+			Contents:   &registry.ApkoConfig_Contents{Packages: []string{pkg}},
+			Accounts:   &registry.ApkoConfig_Accounts{},
+			Entrypoint: &registry.ApkoConfig_Entrypoint{},
+		}
+	}
+	planned := func(pkg string) buildsElementModel {
+		return buildsElementModel{
+			ID:       types.StringUnknown(),
+			Config:   types.StringValue(config(pkg)),
+			Digest:   types.StringUnknown(),
+			ImageRef: types.StringUnknown(),
+		}
+	}
+
+	clients := &platformtest.MockPlatformClients{
+		RegistryClient: registrytest.MockRegistryClients{
+			ApkoClient: registrytest.MockApkoClient{
+				OnBuildImage: []registrytest.OnBuildImage{{
+					Given:  &registry.BuildImageRequest{Config: apkoConfig("a"), RepoUidp: repoID, MediaType: mediaType},
+					Result: &registry.BuildImageResponse{BuildReportId: idA, Digest: "cgr.dev/example/repo@sha256:a"},
+				}, {
+					Given: &registry.BuildImageRequest{Config: apkoConfig("b"), RepoUidp: repoID, MediaType: mediaType},
+					Error: status.Error(codes.Internal, "boom"),
+				}},
+				OnResolveConfig: []registrytest.OnResolveConfig{{
+					Given:  &registry.ResolveConfigRequest{Config: apkoConfig("a"), RepoUidp: repoID},
+					Result: apkoConfig("a"),
+				}},
+			},
+			RegistryClient: registrytest.MockRegistryClient{
+				OnListBuildReports: []registrytest.BuildReportsOnList{{
+					Given: &registry.BuildReportFilter{Uidp: &common.UIDPFilter{DescendantsOf: idA}},
+					List: &registry.BuildReportList{Reports: []*registry.BuildReport{{
+						Id:           idA,
+						Config:       config("a"),
+						LockedConfig: config("a"),
+					}}},
+				}},
+			},
+		},
+	}
+
+	ctx := context.Background()
+	r := &buildsResource{managedResource{prov: &providerData{client: clients}}}
+	plan := testResourcePlan(t, r, map[string]any{
+		"repo":       repoID,
+		"media_type": mediaType,
+		"builds": map[string]buildsElementModel{
+			"a": planned("a"),
+			"b": planned("b"),
+		},
+	})
+	cresp := &tfresource.CreateResponse{State: tfsdk.State{Schema: plan.Schema, Raw: plan.Raw}}
+	r.Create(ctx, tfresource.CreateRequest{Plan: plan}, cresp)
+
+	// A failed build must not taint the resource, or every config would be
+	// rebuilt when it is replaced.
+	if cresp.Diagnostics.HasError() {
+		t.Fatalf("Create() = %v, wanted only warnings", cresp.Diagnostics)
+	}
+	if got := cresp.Diagnostics.WarningsCount(); got != 1 {
+		t.Errorf("Create() warnings = %d, wanted 1: %v", got, cresp.Diagnostics)
+	}
+
+	// Both builds are kept to match the plan, but only a has results.
+	var created buildsResourceModel
+	if diags := cresp.State.Get(ctx, &created); diags.HasError() {
+		t.Fatalf("State.Get() = %v", diags)
+	}
+	if got := created.Builds["a"].ID; got.ValueString() != idA {
+		t.Errorf("build a id = %s, wanted %q", got, idA)
+	}
+	if got := created.Builds["b"]; !got.ID.IsNull() || !got.Digest.IsNull() || !got.ImageRef.IsNull() {
+		t.Errorf("build b = %v, wanted null results", got)
+	}
+
+	// Read drops b, so the next plan retries only it.
+	rresp := &tfresource.ReadResponse{State: cresp.State}
+	r.Read(ctx, tfresource.ReadRequest{State: cresp.State}, rresp)
+	if rresp.Diagnostics.HasError() {
+		t.Fatalf("Read() = %v", rresp.Diagnostics)
+	}
+	var read buildsResourceModel
+	if diags := rresp.State.Get(ctx, &read); diags.HasError() {
+		t.Fatalf("State.Get() = %v", diags)
+	}
+	if _, ok := read.Builds["b"]; ok || len(read.Builds) != 1 {
+		t.Errorf("builds after Read = %v, wanted only a", read.Builds)
+	}
+}
@@ -7,9 +7,14 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
@@ -31,14 +36,243 @@ func (mr *managedResource) configure(ctx context.Context, req resource.Configure
 		return
 	}
 
-	// If the client hasn't been configured yet, configure it
-	if pd.client == nil {
-		tflog.Info(ctx, "initializing Chainguard API client (managed resource)")
-		if err := pd.setupClient(ctx); err != nil {
-			resp.Diagnostics.Append(errorToDiagnostic(err, "unable to setup client"))
-			return
+	mr.prov = pd
+}
+
+// ensureClient lazily sets up the shared API client. The wrappers applied when
+// registering with the provider call it before each operation that uses it.
+func (mr *managedResource) ensureClient(ctx context.Context) diag.Diagnostics {
+	return ensureClient(ctx, mr.prov)
+}
+
+// clientUser is implemented by managedResource and dataSource, whose API
+// client is set up lazily.
+type clientUser interface {
+	ensureClient(ctx context.Context) diag.Diagnostics
+}
+
+// withClient wraps each resource made by newResources so that its API client is
+// set up before every operation that uses it, rather than each resource having
+// to remember to do so.
+func withClient(newResources ...func() resource.Resource) []func() resource.Resource {
+	wrapped := make([]func() resource.Resource, 0, len(newResources))
+	for _, newResource := range newResources {
+		wrapped = append(wrapped, func() resource.Resource {
+			r := clientResource{newResource()}
+			// Only resources that support import may claim to.
+			if _, ok := r.Resource.(resource.ResourceWithImportState); ok {
+				return importableClientResource{r}
+			}
+			return r
+		})
+	}
+	return wrapped
+}
+
+// clientResource sets up the API client of the resource it wraps before each
+// CRUD operation.
+type clientResource struct {
+	resource.Resource
+}
+
+var (
+	_ resource.ResourceWithConfigure   = clientResource{}
+	_ resource.ResourceWithModifyPlan  = clientResource{}
+	_ resource.ResourceWithImportState = importableClientResource{}
+)
+
+// ensureClient sets up the wrapped resource's client, if it has one.
+func (r clientResource) ensureClient(ctx context.Context) diag.Diagnostics {
+	if cu, ok := r.Resource.(clientUser); ok {
+		return cu.ensureClient(ctx)
+	}
+	return nil
+}
+
+func (r clientResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if rc, ok := r.Resource.(resource.ResourceWithConfigure); ok {
+		rc.Configure(ctx, req, resp)
+	}
+}
+
+// ModifyPlan leaves setting up the client to the wrapped resource, since plans
+// often don't need it.
+func (r clientResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if rm, ok := r.Resource.(resource.ResourceWithModifyPlan); ok {
+		rm.ModifyPlan(ctx, req, resp)
+	}
+}
+
+func (r clientResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if resp.Diagnostics.Append(r.ensureClient(ctx)...); resp.Diagnostics.HasError() {
+		return
+	}
+	r.Resource.Create(ctx, req, resp)
+}
+
+func (r clientResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if resp.Diagnostics.Append(r.ensureClient(ctx)...); resp.Diagnostics.HasError() {
+		return
+	}
+	r.Resource.Read(ctx, req, resp)
+}
+
+func (r clientResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if resp.Diagnostics.Append(r.ensureClient(ctx)...); resp.Diagnostics.HasError() {
+		return
+	}
+	r.Resource.Update(ctx, req, resp)
+}
+
+func (r clientResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if resp.Diagnostics.Append(r.ensureClient(ctx)...); resp.Diagnostics.HasError() {
+		return
+	}
+	r.Resource.Delete(ctx, req, resp)
+}
+
+// importableClientResource is a clientResource for resources which support
+// import.
+type importableClientResource struct {
+	clientResource
+}
+
+func (r importableClientResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if resp.Diagnostics.Append(r.ensureClient(ctx)...); resp.Diagnostics.HasError() {
+		return
+	}
+	r.Resource.(resource.ResourceWithImportState).ImportState(ctx, req, resp)
+}
+
+// ensureClient sets up pd's API client if it hasn't been already, converting
+// any failure into a diagnostic.
+func ensureClient(ctx context.Context, pd *providerData) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if pd == nil {
+		diags.AddError("provider not configured", "The Chainguard provider was not configured. Please report this issue to the provider developers.")
+		return diags
+	}
+	if err := pd.setupClient(ctx); err != nil {
+		diags.Append(errorToDiagnostic(err, "unable to setup client"))
+	}
+	return diags
+}
+
+// timeoutsBlock returns the timeouts block for opts. Timeouts are opt-in, so
+// that long operations such as apko builds keep working as they always have.
+func timeoutsBlock(ctx context.Context, opts timeouts.Opts) schema.Block {
+	b := timeouts.Block(ctx, opts).(schema.SingleNestedBlock)
+	b.Description = "Timeouts for operations on this resource. Operations are not bounded unless a timeout is set here."
+	return b
+}
+
+// withTimeout bounds ctx by timeout, or leaves it unbounded when timeout is
+// zero, as it is when the timeouts block doesn't set one.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout == 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// destroyAllowed reports whether resources that are not deleted by default
+// (e.g. image repos and tags) should be deleted through Terraform.
+func (pd *providerData) destroyAllowed() bool {
+	return pd.testing || pd.allowDestroy
+}
+
+// createdAtKey is the private state key recording when a resource was created.
+const createdAtKey = "created_at"
+
+// readAfterCreateWindow, readAfterCreateAttempts and readAfterCreateBackoff
+// bound how long Read waits for a recently created resource to become visible
+// before concluding it was deleted.
+var (
+	readAfterCreateWindow   = 5 * time.Minute
+	readAfterCreateAttempts = 4
+	readAfterCreateBackoff  = 250 * time.Millisecond
+)
+
+// privateGetter and privateSetter are implemented by resource private state.
+type privateGetter interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+}
+
+type privateSetter interface {
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// markCreated records in private state that the resource was just created.
+// It only enables retries in listVisible, so failures are logged and ignored.
+func markCreated(ctx context.Context, private privateSetter) {
+	b, err := json.Marshal(time.Now().UTC())
+	if err == nil {
+		if diags := private.SetKey(ctx, createdAtKey, b); diags.HasError() {
+			err = fmt.Errorf("%v", diags)
 		}
 	}
+	if err != nil {
+		tflog.Debug(ctx, fmt.Sprintf("failed to record creation time: %v", err))
+	}
+}
 
-	mr.prov = pd
+// recentlyCreated reports whether private state records that the resource was
+// created within readAfterCreateWindow.
+func recentlyCreated(ctx context.Context, private privateGetter) bool {
+	b, diags := private.GetKey(ctx, createdAtKey)
+	if diags.HasError() || len(b) == 0 {
+		return false
+	}
+	var created time.Time
+	if err := json.Unmarshal(b, &created); err != nil {
+		return false
+	}
+	return time.Since(created) < readAfterCreateWindow
+}
+
+// listVisible calls list, retrying with exponential backoff while it returns
+// nothing, but only for resources created within readAfterCreateWindow since
+// replication lag may hide them briefly. Otherwise an empty result is returned
+// at once, so real deletions are not masked.
+func listVisible[T any](ctx context.Context, private privateGetter, list func() ([]T, error)) ([]T, error) {
+	attempts := 1
+	if recentlyCreated(ctx, private) {
+		attempts = readAfterCreateAttempts
+	}
+
+	backoff := readAfterCreateBackoff
+	for attempt := 1; ; attempt++ {
+		items, err := list()
+		if err != nil || len(items) != 0 || attempt >= attempts {
+			return items, err
+		}
+		tflog.Info(ctx, fmt.Sprintf("recently created resource not found (attempt %d), retrying", attempt))
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+			backoff *= 2
+		}
+	}
+}
+
+// asWarnings returns diags with errors downgraded to warnings, keeping their
+// attribute paths. Create uses it for failures of dependent objects which
+// state already leaves out, since a Create error taints the resource and the
+// next apply would replace it rather than retry only what failed.
+func asWarnings(diags diag.Diagnostics) diag.Diagnostics {
+	out := make(diag.Diagnostics, 0, len(diags))
+	for _, d := range diags {
+		if d.Severity() != diag.SeverityError {
+			out = append(out, d)
+			continue
+		}
+		if wp, ok := d.(diag.DiagnosticWithPath); ok {
+			out.AddAttributeWarning(wp.Path(), d.Summary(), d.Detail())
+		} else {
+			out.AddWarning(d.Summary(), d.Detail())
+		}
+	}
+	return out
 }
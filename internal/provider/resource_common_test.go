@@ -0,0 +1,334 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	registry "chainguard.dev/sdk/proto/platform/registry/v1"
+	registrytest "chainguard.dev/sdk/proto/platform/registry/v1/test"
+	platformtest "chainguard.dev/sdk/proto/platform/test"
+	"github.com/chainguard-dev/terraform-provider-chainguard/internal/token"
+)
+
+// testResourceState builds a tfsdk.State for r with the given top-level attributes set.
+func testResourceState(t *testing.T, r resource.Resource, attrs map[string]any) tfsdk.State {
+	t.Helper()
+	ctx := context.Background()
+
+	var sresp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &sresp)
+	if sresp.Diagnostics.HasError() {
+		t.Fatalf("Schema() = %v", sresp.Diagnostics)
+	}
+	state := tfsdk.State{
+		Schema: sresp.Schema,
+		Raw:    tftypes.NewValue(sresp.Schema.Type().TerraformType(ctx), nil),
+	}
+	for name, v := range attrs {
+		if diags := state.SetAttribute(ctx, path.Root(name), v); diags.HasError() {
+			t.Fatalf("State.SetAttribute(%s) = %v", name, diags)
+		}
+	}
+	return state
+}
+
+// testResourcePlan builds a tfsdk.Plan for r with the given top-level attributes set.
+func testResourcePlan(t *testing.T, r resource.Resource, attrs map[string]any) tfsdk.Plan {
+	t.Helper()
+	state := testResourceState(t, r, attrs)
+	return tfsdk.Plan{Schema: state.Schema, Raw: state.Raw}
+}
+
+func TestDestroyAllowed(t *testing.T) {
+	const (
+		repoID = "0123456789abcdef0123456789abcdef01234567/0123456789abcdef"
+		tagID  = repoID + "/0123456789abcdef"
+	)
+	clients := &platformtest.MockPlatformClients{
+		RegistryClient: registrytest.MockRegistryClients{
+			RegistryClient: registrytest.MockRegistryClient{
+				OnDeleteRepos: []registrytest.ReposOnDelete{{
+					Given: &registry.DeleteRepoRequest{Id: repoID},
+				}},
+				OnDeleteTags: []registrytest.TagsOnDelete{{
+					Given: &registry.DeleteTagRequest{Id: tagID},
+				}},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		newResource func(*providerData) resource.Resource
+		id          string
+	}{
+		"image repo": {
+			newResource: func(pd *providerData) resource.Resource {
+				return &imageRepoResource{managedResource{prov: pd}}
+			},
+			id: repoID,
+		},
+		"image tag": {
+			newResource: func(pd *providerData) resource.Resource {
+				return &imageTagResource{managedResource{prov: pd}}
+			},
+			id: tagID,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			for _, allow := range []bool{false, true} {
+				r := test.newResource(&providerData{client: clients, allowDestroy: allow})
+
+				req := resource.DeleteRequest{State: testResourceState(t, r, map[string]any{"id": test.id})}
+				var resp resource.DeleteResponse
+				r.Delete(context.Background(), req, &resp)
+				if got := !resp.Diagnostics.HasError(); got != allow {
+					t.Errorf("allow_destroy=%t: Delete() succeeded = %t, diags = %v", allow, got, resp.Diagnostics)
+				}
+			}
+		})
+	}
+}
+
+func TestEnsureClient(t *testing.T) {
+	var calls int
+	getToken = func(context.Context, token.LoginConfig, bool) ([]byte, error) {
+		calls++
+		return []byte("token"), nil
+	}
+	t.Cleanup(func() { getToken = token.Get })
+
+	pd := &providerData{consoleAPI: "https://console-api.example.com"}
+	tag := &imageTagResource{managedResource{prov: pd}}
+	group := &groupResource{managedResource{prov: pd}}
+	ds := &groupDataSource{dataSource{prov: pd}}
+
+	for _, diags := range []diag.Diagnostics{
+		tag.ensureClient(context.Background()),
+		group.ensureClient(context.Background()),
+		ds.ensureClient(context.Background()),
+		tag.ensureClient(context.Background()),
+	} {
+		if diags.HasError() {
+			t.Fatalf("ensureClient() = %v", diags)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("client set up %d times, wanted 1", calls)
+	}
+	if pd.clients() == nil {
+		t.Error("ensureClient() did not set up the client")
+	}
+}
+
+func TestEnsureClient_Error(t *testing.T) {
+	getToken = func(context.Context, token.LoginConfig, bool) ([]byte, error) {
+		return nil, errors.New("no token for you")
+	}
+	t.Cleanup(func() { getToken = token.Get })
+
+	r := withClient(NewImageTagResource)[0]()
+	var cresp resource.ConfigureResponse
+	r.(resource.ResourceWithConfigure).Configure(context.Background(), resource.ConfigureRequest{ProviderData: &providerData{}}, &cresp)
+	if cresp.Diagnostics.HasError() {
+		t.Fatalf("Configure() = %v", cresp.Diagnostics)
+	}
+	req := resource.ReadRequest{State: testResourceState(t, r, map[string]any{
+		"id": "0123456789abcdef0123456789abcdef01234567/0123456789abcdef/0123456789abcdef",
+	})}
+	var resp resource.ReadResponse
+	r.Read(context.Background(), req, &resp)
+
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("Read() succeeded without a client")
+	}
+	if got, want := resp.Diagnostics.Errors()[0].Summary(), "unable to setup client"; got != want {
+		t.Errorf("Read() error summary = %q, wanted %q", got, want)
+	}
+
+	var unconfigured imageTagResource
+	if diags := unconfigured.ensureClient(context.Background()); !diags.HasError() {
+		t.Error("ensureClient() succeeded without provider data")
+	}
+}
+
+func TestWithClient(t *testing.T) {
+	ctx := context.Background()
+	for _, newResource := range (&Provider{}).Resources(ctx) {
+		r := newResource()
+		var mresp resource.MetadataResponse
+		r.Metadata(ctx, resource.MetadataRequest{ProviderTypeName: "chainguard"}, &mresp)
+
+		// Every resource sets up its client before each operation.
+		var inner resource.Resource
+		switch cr := r.(type) {
+		case clientResource:
+			inner = cr.Resource
+		case importableClientResource:
+			inner = cr.Resource
+		default:
+			t.Errorf("%s: resource is %T, wanted it wrapped by withClient", mresp.TypeName, r)
+			continue
+		}
+		// It supports import exactly when the wrapped resource does.
+		_, want := inner.(resource.ResourceWithImportState)
+		if _, got := r.(resource.ResourceWithImportState); got != want {
+			t.Errorf("%s: supports import = %t, wanted %t", mresp.TypeName, got, want)
+		}
+	}
+
+	for _, newDataSource := range (&Provider{}).DataSources(ctx) {
+		d := newDataSource()
+		var mresp datasource.MetadataResponse
+		d.Metadata(ctx, datasource.MetadataRequest{ProviderTypeName: "chainguard"}, &mresp)
+		_, wrapped := d.(clientDataSource)
+		// Only the data sources which don't use the API client are exempt.
+		want := mresp.TypeName != "chainguard_token" && mresp.TypeName != "chainguard_identity_check"
+		if wrapped != want {
+			t.Errorf("%s: wrapped by withDataSourceClient = %t, wanted %t", mresp.TypeName, wrapped, want)
+		}
+	}
+}
+
+// fakePrivate is an in-memory stand-in for resource private state.
+type fakePrivate map[string][]byte
+
+func (p fakePrivate) GetKey(_ context.Context, key string) ([]byte, diag.Diagnostics) {
+	return p[key], nil
+}
+
+func (p fakePrivate) SetKey(_ context.Context, key string, value []byte) diag.Diagnostics {
+	p[key] = value
+	return nil
+}
+
+func TestRecentlyCreated(t *testing.T) {
+	ctx := context.Background()
+
+	marked := fakePrivate{}
+	markCreated(ctx, marked)
+
+	tests := map[string]struct {
+		private fakePrivate
+		want    bool
+	}{
+		"never marked": {private: fakePrivate{}},
+		"just created": {private: marked, want: true},
+		"created long ago": {
+			private: fakePrivate{createdAtKey: []byte(`"2020-01-02T03:04:05Z"`)},
+		},
+		"garbage": {
+			private: fakePrivate{createdAtKey: []byte(`"yesterday"`)},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := recentlyCreated(ctx, test.private); got != test.want {
+				t.Errorf("recentlyCreated() = %t, wanted %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestListVisible(t *testing.T) {
+	readAfterCreateBackoff = time.Millisecond
+	t.Cleanup(func() { readAfterCreateBackoff = 250 * time.Millisecond })
+
+	ctx := context.Background()
+	recent := fakePrivate{}
+	markCreated(ctx, recent)
+
+	tests := map[string]struct {
+		private fakePrivate
+		// results are returned by successive calls to list, and the last
+		// is repeated.
+		results   [][]string
+		err       error
+		want      []string
+		wantCalls int
+	}{
+		"found": {
+			private:   recent,
+			results:   [][]string{{"a"}},
+			want:      []string{"a"},
+			wantCalls: 1,
+		},
+		"recently created, empty then populated": {
+			private:   recent,
+			results:   [][]string{nil, nil, {"a"}},
+			want:      []string{"a"},
+			wantCalls: 3,
+		},
+		"recently created, deleted": {
+			private:   recent,
+			results:   [][]string{nil},
+			wantCalls: readAfterCreateAttempts,
+		},
+		"not recently created, empty then populated": {
+			private:   fakePrivate{},
+			results:   [][]string{nil, {"a"}},
+			wantCalls: 1,
+		},
+		"error": {
+			private:   recent,
+			results:   [][]string{nil},
+			err:       errors.New("boom"),
+			wantCalls: 1,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			got, err := listVisible(ctx, test.private, func() ([]string, error) {
+				res := test.results[min(calls, len(test.results)-1)]
+				calls++
+				return res, test.err
+			})
+			if (err != nil) != (test.err != nil) {
+				t.Fatalf("listVisible() error = %v, wanted %v", err, test.err)
+			}
+			if calls != test.wantCalls {
+				t.Errorf("list called %d times, wanted %d", calls, test.wantCalls)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("listVisible() (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAsWarnings(t *testing.T) {
+	var diags diag.Diagnostics
+	diags.AddError("plain error", "detail")
+	diags.AddAttributeError(path.Root("name"), "attribute error", "detail")
+	diags.AddWarning("warning", "detail")
+
+	got := asWarnings(diags)
+	if got.HasError() {
+		t.Errorf("asWarnings() = %v, wanted no errors", got)
+	}
+	want := diag.Diagnostics{
+		diag.NewWarningDiagnostic("plain error", "detail"),
+		diag.NewAttributeWarningDiagnostic(path.Root("name"), "attribute error", "detail"),
+		diag.NewWarningDiagnostic("warning", "detail"),
+	}
+	if !got.Equal(want) {
+		t.Errorf("asWarnings() = %v, wanted %v", got, want)
+	}
+}
@@ -8,7 +8,9 @@ package provider
 import (
 	"context"
 	"fmt"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -17,11 +19,12 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	common "chainguard.dev/sdk/proto/platform/common/v1"
 	iam "chainguard.dev/sdk/proto/platform/iam/v1"
 	"chainguard.dev/sdk/uidp"
-	"github.com/chainguard-dev/terraform-provider-chainguard/internal/token"
 	"github.com/chainguard-dev/terraform-provider-chainguard/internal/validators"
 )
 
@@ -48,6 +51,20 @@ type groupResourceModel struct {
 	Description types.String `tfsdk:"description"`
 	ParentID    types.String `tfsdk:"parent_id"`
 	Verified    types.Bool   `tfsdk:"verified"`
+
+	DefaultRolebindings []groupRolebindingModel `tfsdk:"default_rolebindings"`
+}
+
+type groupRolebindingModel struct {
+	ID       types.String `tfsdk:"id"`
+	Identity types.String `tfsdk:"identity"`
+	Role     types.String `tfsdk:"role"`
+}
+
+// key identifies a binding by what it grants, since ids are only known once
+// the binding is created.
+func (b groupRolebindingModel) key() string {
+	return b.Identity.ValueString() + "|" + b.Role.ValueString()
 }
 
 func (r *groupResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
@@ -88,6 +105,28 @@ func (r *groupResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 				Description: "Whether the organization has been verified by a Chainguardian. Only applicable to root groups.",
 				Optional:    true,
 			},
+			"default_rolebindings": schema.ListNestedAttribute{
+				Description: "Rolebindings to create in this group once it is created, e.g. to bind an owner. They are managed with the group and deleted before it. Bindings which fail to be created are reported as warnings and retried on the next apply, rather than replacing the group. For bindings managed on their own, use chainguard_rolebinding.",
+				Optional:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Description: "The UIDP of this rolebinding.",
+							Computed:    true,
+						},
+						"identity": schema.StringAttribute{
+							Description: "The id of an identity to grant the role's capabilities to at the scope of this group.",
+							Required:    true,
+							Validators:  []validator.String{validators.UIDP(false /* allowRootSentinel */)},
+						},
+						"role": schema.StringAttribute{
+							Description: "The role to grant the identity at the scope of this group.",
+							Required:    true,
+							Validators:  []validator.String{validators.UIDP(false /* allowRootSentinel */)},
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -121,31 +160,48 @@ func (r *groupResource) Create(ctx context.Context, req resource.CreateRequest,
 		cr.Parent = plan.ParentID.ValueString()
 	}
 
-	g, err := r.prov.client.IAM().Groups().Create(ctx, cr)
+	g, err := r.prov.clients().IAM().Groups().Create(ctx, cr)
 	if err != nil {
 		resp.Diagnostics.Append(errorToDiagnostic(err, fmt.Sprintf("failed to create group %q", cr.Group.Name)))
 		return
 	}
 
-	// Save group details in the state.
+	// Save group details in the state. Default rolebindings are saved once
+	// they are created.
 	plan.ID = types.StringValue(g.Id)
+	bindings := plan.DefaultRolebindings
+	plan.DefaultRolebindings = nil
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 
 	// Attempt to reauthenticate if root group was created so token
 	// has new root group in scope.
 	if uidp.InRoot(g.Id) {
-		cfg := r.prov.loginConfig
-		cgToken, err := token.Get(ctx, cfg, true /* forceRefresh */)
-		if err != nil {
-			resp.Diagnostics.Append(errorToDiagnostic(err, "failed to refresh Chainguard token"))
+		if err := r.prov.refreshClient(ctx); err != nil {
+			resp.Diagnostics.Append(errorToDiagnostic(err, "failed to reauthenticate after creating root group"))
 			return
 		}
-		clients, err := newPlatformClients(ctx, string(cgToken), r.prov.consoleAPI)
-		if err != nil {
-			resp.Diagnostics.Append(errorToDiagnostic(err, "failed to create new platform clients"))
-			return
+	}
+
+	// Bind the default roles, now that the group is in scope of the token.
+	// Failing Create would taint the group, and the next apply would replace
+	// it and everything in it, so failures are warnings. Bindings which
+	// weren't created are kept in state without an id, as state must match
+	// the plan, and Read drops them so the next plan retries only those.
+	if bindings != nil {
+		created, diags := r.reconcileRolebindings(ctx, g.Id, nil /* have */, bindings)
+		resp.Diagnostics.Append(asWarnings(diags)...)
+		ids := make(map[string]types.String, len(created))
+		for _, b := range created {
+			ids[b.key()] = b.ID
+		}
+		for i, b := range bindings {
+			bindings[i].ID = types.StringNull()
+			if id, ok := ids[b.key()]; ok {
+				bindings[i].ID = id
+			}
 		}
-		r.prov.client = clients
+		plan.DefaultRolebindings = bindings
+		resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 	}
 }
 
@@ -169,7 +225,7 @@ func (r *groupResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		Name: state.Name.ValueString(),
 		Uidp: uf,
 	}
-	groupList, err := r.prov.client.IAM().Groups().List(ctx, f)
+	groupList, err := r.prov.clients().IAM().Groups().List(ctx, f)
 	if err != nil {
 		resp.Diagnostics.Append(errorToDiagnostic(err, "failed to list groups"))
 		return
@@ -177,7 +233,16 @@ func (r *groupResource) Read(ctx context.Context, req resource.ReadRequest, resp
 
 	switch c := len(groupList.GetItems()); {
 	case c == 0:
-		// Group was already deleted outside TF, remove from state
+		// Group was already deleted outside TF, remove from state. A group's
+		// UIDP encodes its parent, so a group moved outside TF also ends up
+		// here, as its old id no longer exists. Warn in that case, since it
+		// would otherwise be silently re-created under its old parent.
+		if moved := r.movedTo(ctx, state.ID.ValueString(), state.Name.ValueString()); len(moved) > 0 {
+			resp.Diagnostics.AddWarning("group appears to have moved",
+				fmt.Sprintf("Group %q (%s) no longer exists, but a group with the same name was found at %s. "+
+					"If it was moved outside Terraform, it will be re-created under %s unless its parent_id is updated and it is imported again.",
+					state.Name.ValueString(), state.ID.ValueString(), strings.Join(moved, ", "), uidp.Parent(state.ID.ValueString())))
+		}
 		resp.State.RemoveResource(ctx)
 
 	case c == 1:
@@ -199,6 +264,16 @@ func (r *groupResource) Read(ctx context.Context, req resource.ReadRequest, resp
 			state.Verified = types.BoolValue(g.Verified)
 		}
 
+		// Drop default rolebindings deleted outside TF, so they are recreated.
+		if len(state.DefaultRolebindings) > 0 {
+			bindings, diags := r.currentRolebindings(ctx, g.Id, state.DefaultRolebindings)
+			resp.Diagnostics.Append(diags...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			state.DefaultRolebindings = bindings
+		}
+
 		// Set state
 		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 
@@ -208,17 +283,44 @@ func (r *groupResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	}
 }
 
+// movedTo returns the ids of groups in id's organization named name, other than
+// those in id's former parent, which are candidates for where it was moved.
+func (r *groupResource) movedTo(ctx context.Context, id, name string) []string {
+	// An organization cannot be moved.
+	if !uidp.Valid(id) || uidp.InRoot(id) {
+		return nil
+	}
+	ancestry := uidp.Ancestry(id)
+	groupList, err := r.prov.clients().IAM().Groups().List(ctx, &iam.GroupFilter{
+		Name: name,
+		Uidp: &common.UIDPFilter{DescendantsOf: ancestry[len(ancestry)-1]},
+	})
+	if err != nil {
+		// This is only to explain the group's disappearance, so don't fail.
+		tflog.Warn(ctx, fmt.Sprintf("failed to look for group %q elsewhere in its organization: %v", name, err))
+		return nil
+	}
+	var moved []string
+	for _, g := range groupList.GetItems() {
+		if g.GetName() == name && uidp.Parent(g.GetId()) != uidp.Parent(id) {
+			moved = append(moved, g.GetId())
+		}
+	}
+	return moved
+}
+
 // Update updates the resource and sets the updated Terraform state on success.
 func (r *groupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	// Read the plan into the resource model.
-	var data groupResourceModel
+	// Read the plan and state into the resource model.
+	var data, state groupResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 	tflog.Info(ctx, fmt.Sprintf("update group request: %s", data.ID))
 
-	g, err := r.prov.client.IAM().Groups().Update(ctx, &iam.Group{
+	g, err := r.prov.clients().IAM().Groups().Update(ctx, &iam.Group{
 		Id:          data.ID.ValueString(),
 		Name:        data.Name.ValueString(),
 		Description: data.Description.ValueString(),
@@ -238,6 +340,9 @@ func (r *groupResource) Update(ctx context.Context, req resource.UpdateRequest,
 	if !data.Verified.IsNull() || g.Verified {
 		data.Verified = types.BoolValue(g.Verified)
 	}
+	var diags diag.Diagnostics
+	data.DefaultRolebindings, diags = r.reconcileRolebindings(ctx, g.Id, state.DefaultRolebindings, data.DefaultRolebindings)
+	resp.Diagnostics.Append(diags...)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
@@ -252,7 +357,16 @@ func (r *groupResource) Delete(ctx context.Context, req resource.DeleteRequest,
 	tflog.Info(ctx, fmt.Sprintf("delete group request: %s", state.ID))
 
 	id := state.ID.ValueString()
-	_, err := r.prov.client.IAM().Groups().Delete(ctx, &iam.DeleteGroupRequest{
+	if len(state.DefaultRolebindings) > 0 {
+		bindings, diags := r.reconcileRolebindings(ctx, id, state.DefaultRolebindings, nil /* want */)
+		if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+			// Keep the bindings which could not be deleted in state.
+			state.DefaultRolebindings = bindings
+			resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+			return
+		}
+	}
+	_, err := r.prov.clients().IAM().Groups().Delete(ctx, &iam.DeleteGroupRequest{
 		Id: id,
 	})
 	if err != nil {
@@ -260,3 +374,95 @@ func (r *groupResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 }
+
+// reconcileRolebindings creates the bindings in want which are not in have, and
+// deletes those in have which are not in want. It returns the bindings that
+// exist afterwards in the order of want, or nil if want is nil. On error, the
+// bindings of have which were left untouched are returned too, so they are
+// not lost from state. Bindings of have without an id were never created.
+func (r *groupResource) reconcileRolebindings(ctx context.Context, group string, have, want []groupRolebindingModel) ([]groupRolebindingModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	untouched := make(map[string]groupRolebindingModel, len(have))
+	for _, b := range have {
+		if !b.ID.IsNull() {
+			untouched[b.key()] = b
+		}
+	}
+	remaining := func(got []groupRolebindingModel) []groupRolebindingModel {
+		for _, b := range have {
+			if _, ok := untouched[b.key()]; ok {
+				got = append(got, b)
+			}
+		}
+		return got
+	}
+
+	var got []groupRolebindingModel
+	if want != nil {
+		got = make([]groupRolebindingModel, 0, len(want))
+	}
+	for _, b := range want {
+		if existing, ok := untouched[b.key()]; ok {
+			delete(untouched, b.key())
+			b.ID = existing.ID
+			got = append(got, b)
+			continue
+		}
+		tflog.Info(ctx, fmt.Sprintf("create default rolebinding: group=%s, role=%s, identity=%s", group, b.Role, b.Identity))
+		binding, err := r.prov.clients().IAM().RoleBindings().Create(ctx, &iam.CreateRoleBindingRequest{
+			Parent: group,
+			RoleBinding: &iam.RoleBinding{
+				Identity: b.Identity.ValueString(),
+				Role:     b.Role.ValueString(),
+			},
+		})
+		if err != nil {
+			diags.Append(errorToDiagnostic(err, fmt.Sprintf("failed to create default rolebinding of role %s to identity %s", b.Role.ValueString(), b.Identity.ValueString())))
+			return remaining(got), diags
+		}
+		b.ID = types.StringValue(binding.Id)
+		got = append(got, b)
+	}
+
+	for _, b := range have {
+		if _, ok := untouched[b.key()]; !ok {
+			continue
+		}
+		tflog.Info(ctx, fmt.Sprintf("delete default rolebinding: id=%s", b.ID))
+		_, err := r.prov.clients().IAM().RoleBindings().Delete(ctx, &iam.DeleteRoleBindingRequest{Id: b.ID.ValueString()})
+		if err != nil && status.Code(err) != codes.NotFound {
+			diags.Append(errorToDiagnostic(err, fmt.Sprintf("failed to delete default rolebinding %q", b.ID.ValueString())))
+			continue
+		}
+		delete(untouched, b.key())
+	}
+	return remaining(got), diags
+}
+
+// currentRolebindings returns the bindings which still exist in the group.
+func (r *groupResource) currentRolebindings(ctx context.Context, group string, bindings []groupRolebindingModel) ([]groupRolebindingModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	list, err := r.prov.clients().IAM().RoleBindings().List(ctx, &iam.RoleBindingFilter{
+		Uidp: &common.UIDPFilter{ChildrenOf: group},
+	})
+	if err != nil {
+		diags.Append(errorToDiagnostic(err, "failed to list rolebindings"))
+		return bindings, diags
+	}
+	exists := make(map[string]bool, len(list.GetItems()))
+	for _, b := range list.GetItems() {
+		exists[b.GetId()] = true
+	}
+
+	current := make([]groupRolebindingModel, 0, len(bindings))
+	for _, b := range bindings {
+		if exists[b.ID.ValueString()] {
+			current = append(current, b)
+		} else if b.ID.IsNull() {
+			tflog.Info(ctx, fmt.Sprintf("default rolebinding of role %s to identity %s was not created", b.Role, b.Identity))
+		} else {
+			tflog.Info(ctx, fmt.Sprintf("default rolebinding %s was deleted outside terraform", b.ID))
+		}
+	}
+	return current, diags
+}
@@ -146,7 +146,7 @@ func (r *groupInviteResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
-	invite, err := r.prov.client.IAM().GroupInvites().Create(ctx, &iam.GroupInviteRequest{
+	invite, err := r.prov.clients().IAM().GroupInvites().Create(ctx, &iam.GroupInviteRequest{
 		Group: plan.Group.ValueString(),
 		Ttl:   durationpb.New(time.Until(ts)),
 		Role:  plan.Role.ValueString(),
@@ -171,10 +171,11 @@ func (r *groupInviteResource) Read(ctx context.Context, req resource.ReadRequest
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx = maskSensitive(ctx, req.State.Schema.AttributeAtTerraformPath, req.State.Raw)
 	tflog.Info(ctx, fmt.Sprintf("read group invite request: %s", state.ID))
 
 	// Query for the group to update state
-	inviteList, err := r.prov.client.IAM().GroupInvites().List(ctx, &iam.GroupInviteFilter{
+	inviteList, err := r.prov.clients().IAM().GroupInvites().List(ctx, &iam.GroupInviteFilter{
 		Id: state.ID.ValueString(),
 	})
 	if err != nil {
@@ -212,10 +213,11 @@ func (r *groupInviteResource) Delete(ctx context.Context, req resource.DeleteReq
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx = maskSensitive(ctx, req.State.Schema.AttributeAtTerraformPath, req.State.Raw)
 	tflog.Info(ctx, fmt.Sprintf("delete group invite request: %s", state.ID))
 
 	id := state.ID.ValueString()
-	_, err := r.prov.client.IAM().GroupInvites().Delete(ctx, &iam.DeleteGroupInviteRequest{
+	_, err := r.prov.clients().IAM().GroupInvites().Delete(ctx, &iam.DeleteGroupInviteRequest{
 		Id: state.ID.ValueString(),
 	})
 	if err != nil {
@@ -6,13 +6,27 @@ SPDX-License-Identifier: Apache-2.0
 package provider
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"regexp"
+	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	tfresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	common "chainguard.dev/sdk/proto/platform/common/v1"
+	iam "chainguard.dev/sdk/proto/platform/iam/v1"
+	iamtest "chainguard.dev/sdk/proto/platform/iam/v1/test"
+	platformtest "chainguard.dev/sdk/proto/platform/test"
 )
 
 func testAccResourceGroup(parent, name, description string) string {
@@ -133,3 +147,329 @@ func TestAccRootGroupResource(t *testing.T) {
 		},
 	})
 }
+
+func TestAccGroupResource_DefaultRolebindings(t *testing.T) {
+	parent := os.Getenv(EnvAccGroupID)
+	name := acctest.RandString(10)
+
+	grandchildpattern := regexp.MustCompile(fmt.Sprintf(`%s\/[a-z0-9]{16}\/[a-z0-9]{16}`, parent))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create with a binding.
+			{
+				Config: testAccResourceGroupDefaultRolebindings(parent, name, true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("chainguard_group.test", "default_rolebindings.#", "1"),
+					resource.TestCheckResourceAttrPair("chainguard_group.test", "default_rolebindings.0.identity", "chainguard_identity.user", "id"),
+					resource.TestCheckResourceAttrPair("chainguard_group.test", "default_rolebindings.0.role", "data.chainguard_role.viewer_test", "items.0.id"),
+					resource.TestMatchResourceAttr("chainguard_group.test", "default_rolebindings.0.id", grandchildpattern),
+				),
+			},
+
+			// Remove the binding.
+			{
+				Config: testAccResourceGroupDefaultRolebindings(parent, name, false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("chainguard_group.test", "default_rolebindings.#", "0"),
+				),
+			},
+
+			// Delete testing automatically occurs in TestCase.
+		},
+	})
+}
+
+func testAccResourceGroupDefaultRolebindings(parent, name string, bind bool) string {
+	bindings := "[]"
+	if bind {
+		bindings = `[{
+    identity = chainguard_identity.user.id
+    role     = data.chainguard_role.viewer_test.items.0.id
+  }]`
+	}
+	const tmpl = `
+resource "chainguard_identity" "user" {
+  parent_id = %q
+  name      = "something"
+  claim_match {
+    issuer  = "https://issuer.example.com"
+    subject = "something:something:subject"
+  }
+}
+
+resource "chainguard_group" "test" {
+  parent_id            = %q
+  name                 = %q
+  default_rolebindings = %s
+}
+`
+	return accDataRoleViewer + fmt.Sprintf(tmpl, parent, parent, name, bindings)
+}
+
+func TestGroupReconcileRolebindings(t *testing.T) {
+	const (
+		group    = "0123456789abcdef0123456789abcdef01234567/0123456789abcdef"
+		identity = "0123456789abcdef0123456789abcdef01234567/fedcba9876543210"
+		viewer   = "0123456789abcdef0123456789abcdef01234567/000000000000000a"
+		editor   = "0123456789abcdef0123456789abcdef01234567/000000000000000b"
+		owner    = "0123456789abcdef0123456789abcdef01234567/000000000000000c"
+	)
+	binding := func(id, role string) groupRolebindingModel {
+		b := groupRolebindingModel{ID: types.StringUnknown(), Identity: types.StringValue(identity), Role: types.StringValue(role)}
+		if id != "" {
+			b.ID = types.StringValue(id)
+		}
+		return b
+	}
+	create := iamtest.RoleBindingOnCreate{
+		Given:   &iam.CreateRoleBindingRequest{Parent: group, RoleBinding: &iam.RoleBinding{Identity: identity, Role: owner}},
+		Created: &iam.RoleBinding{Id: group + "/owner"},
+	}
+
+	tests := map[string]struct {
+		have, want []groupRolebindingModel
+		mock       iamtest.MockRoleBindingsClient
+		wantResult []groupRolebindingModel
+		wantErr    bool
+	}{
+		"create": {
+			want:       []groupRolebindingModel{binding("", owner)},
+			mock:       iamtest.MockRoleBindingsClient{OnCreate: []iamtest.RoleBindingOnCreate{create}},
+			wantResult: []groupRolebindingModel{binding(group+"/owner", owner)},
+		},
+		"keep, create and delete": {
+			have: []groupRolebindingModel{binding(group+"/viewer", viewer), binding(group+"/editor", editor)},
+			want: []groupRolebindingModel{binding("", viewer), binding("", owner)},
+			mock: iamtest.MockRoleBindingsClient{
+				OnCreate: []iamtest.RoleBindingOnCreate{create},
+				OnDelete: []iamtest.RoleBindingOnDelete{{Given: &iam.DeleteRoleBindingRequest{Id: group + "/editor"}}},
+			},
+			wantResult: []groupRolebindingModel{binding(group+"/viewer", viewer), binding(group+"/owner", owner)},
+		},
+		"delete all": {
+			have: []groupRolebindingModel{binding(group+"/viewer", viewer)},
+			mock: iamtest.MockRoleBindingsClient{
+				OnDelete: []iamtest.RoleBindingOnDelete{{Given: &iam.DeleteRoleBindingRequest{Id: group + "/viewer"}}},
+			},
+		},
+		"failed delete is kept": {
+			have: []groupRolebindingModel{binding(group+"/viewer", viewer)},
+			want: []groupRolebindingModel{},
+			mock: iamtest.MockRoleBindingsClient{
+				OnDelete: []iamtest.RoleBindingOnDelete{{
+					Given: &iam.DeleteRoleBindingRequest{Id: group + "/viewer"},
+					Error: errors.New("boom"),
+				}},
+			},
+			wantResult: []groupRolebindingModel{binding(group+"/viewer", viewer)},
+			wantErr:    true,
+		},
+		"failed create keeps existing": {
+			have: []groupRolebindingModel{binding(group+"/editor", editor)},
+			want: []groupRolebindingModel{binding("", owner)},
+			mock: iamtest.MockRoleBindingsClient{OnCreate: []iamtest.RoleBindingOnCreate{{
+				Given: create.Given,
+				Error: errors.New("boom"),
+			}}},
+			wantResult: []groupRolebindingModel{binding(group+"/editor", editor)},
+			wantErr:    true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			clients := &platformtest.MockPlatformClients{
+				IAMClient: iamtest.MockIAMClient{RoleBindingsClient: test.mock},
+			}
+			r := &groupResource{managedResource{prov: &providerData{client: clients}}}
+
+			got, diags := r.reconcileRolebindings(context.Background(), group, test.have, test.want)
+			if diags.HasError() != test.wantErr {
+				t.Fatalf("reconcileRolebindings() = %v, wanted error: %t", diags, test.wantErr)
+			}
+			if (got == nil) != (test.wantResult == nil) {
+				t.Errorf("reconcileRolebindings() = %#v, wanted %#v", got, test.wantResult)
+			}
+			if len(got) != len(test.wantResult) {
+				t.Fatalf("reconcileRolebindings() = %v, wanted %v", got, test.wantResult)
+			}
+			for i := range got {
+				if got[i] != test.wantResult[i] {
+					t.Errorf("binding %d = %v, wanted %v", i, got[i], test.wantResult[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGroupRead_Moved(t *testing.T) {
+	const (
+		org       = "0123456789abcdef0123456789abcdef01234567"
+		oldParent = org + "/000000000000000a"
+		newParent = org + "/000000000000000b"
+		oldID     = oldParent + "/0123456789abcdef"
+		newID     = newParent + "/0123456789abcdef"
+	)
+
+	tests := map[string]struct {
+		found []*iam.Group
+		want  string
+	}{
+		"moved": {
+			found: []*iam.Group{{Id: newID, Name: "example"}},
+			want:  newID,
+		},
+		"deleted": {},
+		"same name in the same parent": {
+			// Another group of the same name under the old parent was not
+			// moved there.
+			found: []*iam.Group{{Id: oldParent + "/fedcba9876543210", Name: "example"}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			clients := &platformtest.MockPlatformClients{
+				IAMClient: iamtest.MockIAMClient{
+					GroupsClient: iamtest.MockGroupsClient{
+						OnList: []iamtest.GroupOnList{{
+							// The old id no longer exists under the old parent...
+							Given: &iam.GroupFilter{Id: oldID, Name: "example", Uidp: &common.UIDPFilter{ChildrenOf: oldParent}},
+							List:  &iam.GroupList{},
+						}, {
+							// ...but the organization may have a group of that name elsewhere.
+							Given: &iam.GroupFilter{Name: "example", Uidp: &common.UIDPFilter{DescendantsOf: org}},
+							List:  &iam.GroupList{Items: test.found},
+						}},
+					},
+				},
+			}
+			ctx := context.Background()
+			r := &groupResource{managedResource{prov: &providerData{client: clients}}}
+
+			state := testResourceState(t, r, map[string]any{
+				"id":        oldID,
+				"parent_id": oldParent,
+				"name":      "example",
+			})
+			resp := &tfresource.ReadResponse{State: state}
+			r.Read(ctx, tfresource.ReadRequest{State: state}, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Read() = %v", resp.Diagnostics)
+			}
+			if !resp.State.Raw.IsNull() {
+				t.Error("Read() kept the group in state, wanted it removed")
+			}
+
+			warnings := resp.Diagnostics.Warnings()
+			if test.want == "" {
+				if len(warnings) != 0 {
+					t.Errorf("Read() = %v, wanted no warnings", warnings)
+				}
+				return
+			}
+			if len(warnings) != 1 || !strings.Contains(warnings[0].Detail(), test.want) {
+				t.Errorf("Read() = %v, wanted a warning that the group moved to %s", warnings, test.want)
+			}
+		})
+	}
+}
+
+func TestGroupCreate_RolebindingFailure(t *testing.T) {
+	const (
+		parent   = "0123456789abcdef0123456789abcdef01234567"
+		group    = parent + "/0123456789abcdef"
+		identity = parent + "/fedcba9876543210"
+		viewer   = parent + "/000000000000000a"
+		typo     = parent + "/00000000000000ff"
+	)
+	binding := func(role string) groupRolebindingModel {
+		return groupRolebindingModel{ID: types.StringUnknown(), Identity: types.StringValue(identity), Role: types.StringValue(role)}
+	}
+	clients := &platformtest.MockPlatformClients{
+		IAMClient: iamtest.MockIAMClient{
+			GroupsClient: iamtest.MockGroupsClient{
+				OnCreate: []iamtest.GroupOnCreate{{
+					Given:   &iam.CreateGroupRequest{Parent: parent, Group: &iam.Group{Name: "example"}},
+					Created: &iam.Group{Id: group, Name: "example"},
+				}},
+				OnList: []iamtest.GroupOnList{{
+					Given: &iam.GroupFilter{Id: group, Name: "example", Uidp: &common.UIDPFilter{ChildrenOf: parent}},
+					List:  &iam.GroupList{Items: []*iam.Group{{Id: group, Name: "example"}}},
+				}},
+			},
+			RoleBindingsClient: iamtest.MockRoleBindingsClient{
+				OnCreate: []iamtest.RoleBindingOnCreate{{
+					Given:   &iam.CreateRoleBindingRequest{Parent: group, RoleBinding: &iam.RoleBinding{Identity: identity, Role: viewer}},
+					Created: &iam.RoleBinding{Id: group + "/viewer"},
+				}, {
+					Given: &iam.CreateRoleBindingRequest{Parent: group, RoleBinding: &iam.RoleBinding{Identity: identity, Role: typo}},
+					Error: status.Error(codes.NotFound, "no such role"),
+				}},
+				OnList: []iamtest.RoleBindingOnList{{
+					Given: &iam.RoleBindingFilter{Uidp: &common.UIDPFilter{ChildrenOf: group}},
+					List:  &iam.RoleBindingList{Items: []*iam.RoleBindingList_Binding{{Id: group + "/viewer"}}},
+				}},
+			},
+		},
+	}
+	ctx := context.Background()
+	r := &groupResource{managedResource{prov: &providerData{client: clients}}}
+
+	plan := testResourcePlan(t, r, map[string]any{
+		"id":                   types.StringUnknown(),
+		"parent_id":            parent,
+		"name":                 "example",
+		"default_rolebindings": []groupRolebindingModel{binding(viewer), binding(typo)},
+	})
+	cresp := &tfresource.CreateResponse{State: tfsdk.State{Schema: plan.Schema, Raw: plan.Raw}}
+	r.Create(ctx, tfresource.CreateRequest{Plan: plan}, cresp)
+
+	// The group was created, so a failed binding must not taint it.
+	if cresp.Diagnostics.HasError() {
+		t.Fatalf("Create() = %v, wanted only warnings", cresp.Diagnostics)
+	}
+	if cresp.Diagnostics.WarningsCount() != 1 {
+		t.Errorf("Create() = %v, wanted a warning for the failed binding", cresp.Diagnostics)
+	}
+	var created groupResourceModel
+	if diags := cresp.State.Get(ctx, &created); diags.HasError() {
+		t.Fatalf("State.Get() = %v", diags)
+	}
+	if created.ID.ValueString() != group {
+		t.Errorf("Create() id = %v, wanted %s", created.ID, group)
+	}
+	want := []groupRolebindingModel{binding(viewer), binding(typo)}
+	want[0].ID = types.StringValue(group + "/viewer")
+	want[1].ID = types.StringNull()
+	if diff := cmp.Diff(want, created.DefaultRolebindings); diff != "" {
+		t.Errorf("Create() default_rolebindings (-want +got):\n%s", diff)
+	}
+
+	// Read drops the binding which wasn't created, so the next plan adds it.
+	rresp := &tfresource.ReadResponse{State: cresp.State}
+	r.Read(ctx, tfresource.ReadRequest{State: cresp.State}, rresp)
+	if rresp.Diagnostics.HasError() {
+		t.Fatalf("Read() = %v", rresp.Diagnostics)
+	}
+	var read groupResourceModel
+	if diags := rresp.State.Get(ctx, &read); diags.HasError() {
+		t.Fatalf("State.Get() = %v", diags)
+	}
+	if diff := cmp.Diff(want[:1], read.DefaultRolebindings); diff != "" {
+		t.Errorf("Read() default_rolebindings (-want +got):\n%s", diff)
+	}
+
+	// Retrying only creates the missing binding.
+	have := read.DefaultRolebindings
+	got, diags := r.reconcileRolebindings(ctx, group, have, []groupRolebindingModel{binding(viewer), binding(typo)})
+	if !diags.HasError() {
+		t.Errorf("reconcileRolebindings() = %v, wanted the typo to fail again", got)
+	}
+	if diff := cmp.Diff(want[:1], got); diff != "" {
+		t.Errorf("reconcileRolebindings() (-want +got):\n%s", diff)
+	}
+}
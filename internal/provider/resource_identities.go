@@ -0,0 +1,318 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/maps"
+
+	common "chainguard.dev/sdk/proto/platform/common/v1"
+	iam "chainguard.dev/sdk/proto/platform/iam/v1"
+	"github.com/chainguard-dev/terraform-provider-chainguard/internal/validators"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource              = &identitiesResource{}
+	_ resource.ResourceWithConfigure = &identitiesResource{}
+)
+
+// NewIdentitiesResource is a helper function to simplify the provider implementation.
+func NewIdentitiesResource() resource.Resource {
+	return &identitiesResource{}
+}
+
+// identitiesResource is the resource implementation.
+type identitiesResource struct {
+	managedResource
+}
+
+type identitiesResourceModel struct {
+	ParentID   types.String                      `tfsdk:"parent_id"`
+	Identities map[string]identitiesElementModel `tfsdk:"identities"`
+}
+
+type identitiesElementModel struct {
+	ID          types.String `tfsdk:"id"`
+	Description types.String `tfsdk:"description"`
+	ClaimMatch  types.Object `tfsdk:"claim_match"`
+}
+
+func (r *identitiesResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.configure(ctx, req, resp)
+}
+
+// Metadata returns the resource type name.
+func (r *identitiesResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_identities"
+}
+
+// Schema defines the schema for the resource.
+func (r *identitiesResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "A set of claim_match IAM identities sharing a parent group in the Chainguard platform.",
+		Attributes: map[string]schema.Attribute{
+			"parent_id": schema.StringAttribute{
+				Description:   "The id of the group containing these identities.",
+				Required:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+				Validators:    []validator.String{validators.UIDP(false /* allowRootSentinel */)},
+			},
+			"identities": schema.MapNestedAttribute{
+				Description: "The identities to manage, keyed by identity name. Identities are added, updated and removed individually as this map changes. Identities which fail to be created with the resource are reported as warnings and retried on the next apply.",
+				Required:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Description:   "The id of this identity.",
+							Computed:      true,
+							PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+						},
+						"description": schema.StringAttribute{
+							Description: "A longer description of the purpose of this identity.",
+							Optional:    true,
+						},
+						"claim_match": schema.SingleNestedAttribute{
+							Description: "An identity that may be assumed when its claims satisfy these constraints.",
+							Required:    true,
+							Attributes:  claimMatchAttributes(false /* forceNewOnIssuer */),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// identity converts the element named name into an identity for the API.
+func (e identitiesElementModel) identity(ctx context.Context, name string) (*iam.Identity, error) {
+	return populateIdentity(ctx, identityResourceModel{
+		ID:          e.ID,
+		Name:        types.StringValue(name),
+		Description: e.Description,
+		ClaimMatch:  e.ClaimMatch,
+	})
+}
+
+// populate updates the element from the identity returned by the API.
+func (e *identitiesElementModel) populate(ctx context.Context, id *iam.Identity) diag.Diagnostics {
+	m := identityResourceModel{
+		Description: e.Description,
+		ClaimMatch:  e.ClaimMatch,
+	}
+	diags := populateModel(ctx, &m, id)
+	e.ID = m.ID
+	e.Description = m.Description
+	e.ClaimMatch = m.ClaimMatch
+	return diags
+}
+
+// changed reports whether the planned element differs from its current state.
+func (e identitiesElementModel) changed(state identitiesElementModel) bool {
+	return !e.Description.Equal(state.Description) || !e.ClaimMatch.Equal(state.ClaimMatch)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *identitiesResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Read the plan data into the resource model.
+	var plan identitiesResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Info(ctx, fmt.Sprintf("create identities request: parent_id=%s, count=%d", plan.ParentID, len(plan.Identities)))
+
+	// Record every identity that was created, even if others fail, so
+	// nothing is orphaned by a partial failure.
+	state := identitiesResourceModel{
+		ParentID:   plan.ParentID,
+		Identities: make(map[string]identitiesElementModel, len(plan.Identities)),
+	}
+	var diags diag.Diagnostics
+	for _, name := range sortedKeys(plan.Identities) {
+		elem, ok := r.create(ctx, plan.ParentID.ValueString(), name, plan.Identities[name], &diags)
+		if !ok {
+			// Keep the planned identity, which must match the config, with no
+			// id. Read drops it so the next apply retries it.
+			elem.ID = types.StringNull()
+		}
+		state.Identities[name] = elem
+	}
+
+	// Failed identities are warnings, so the resource isn't tainted and
+	// replaced along with the identities that succeeded.
+	resp.Diagnostics.Append(asWarnings(diags)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *identitiesResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Read the current state into the resource model.
+	var state identitiesResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Info(ctx, fmt.Sprintf("read identities request: parent_id=%s", state.ParentID))
+
+	identityList, err := r.prov.clients().IAM().Identities().List(ctx, &iam.IdentityFilter{
+		Uidp: &common.UIDPFilter{
+			ChildrenOf: state.ParentID.ValueString(),
+		},
+	})
+	if err != nil {
+		resp.Diagnostics.Append(errorToDiagnostic(err, "failed to list identities"))
+		return
+	}
+	byID := make(map[string]*iam.Identity, len(identityList.GetItems()))
+	for _, id := range identityList.GetItems() {
+		byID[id.GetId()] = id
+	}
+
+	for name, elem := range state.Identities {
+		id, ok := byID[elem.ID.ValueString()]
+		if !ok {
+			// Identity was deleted outside TF, remove it from the set.
+			delete(state.Identities, name)
+			continue
+		}
+		resp.Diagnostics.Append(elem.populate(ctx, id)...)
+		state.Identities[name] = elem
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Set state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *identitiesResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state identitiesResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Info(ctx, fmt.Sprintf("update identities request: parent_id=%s", plan.ParentID))
+
+	// Reconcile one identity at a time, starting from the current state so
+	// that it reflects exactly the operations that succeeded.
+	if state.Identities == nil {
+		state.Identities = make(map[string]identitiesElementModel, len(plan.Identities))
+	}
+	for _, name := range sortedKeys(state.Identities) {
+		if _, ok := plan.Identities[name]; ok {
+			continue
+		}
+		if state.Identities[name].ID.IsNull() {
+			// It was never created.
+			delete(state.Identities, name)
+			continue
+		}
+		id := state.Identities[name].ID.ValueString()
+		if _, err := r.prov.clients().IAM().Identities().Delete(ctx, &iam.DeleteIdentityRequest{Id: id}); err != nil {
+			resp.Diagnostics.Append(errorToDiagnostic(err, fmt.Sprintf("failed to delete identity %q (%s)", name, id)))
+			continue
+		}
+		delete(state.Identities, name)
+	}
+	for _, name := range sortedKeys(plan.Identities) {
+		want := plan.Identities[name]
+		current, ok := state.Identities[name]
+		switch {
+		case !ok || current.ID.IsNull():
+			if elem, ok := r.create(ctx, plan.ParentID.ValueString(), name, want, &resp.Diagnostics); ok {
+				state.Identities[name] = elem
+			}
+		case want.changed(current):
+			want.ID = current.ID
+			ident, err := want.identity(ctx, name)
+			if err != nil {
+				resp.Diagnostics.Append(errorToDiagnostic(err, fmt.Sprintf("failed to populate identity %q from plan", name)))
+				continue
+			}
+			if _, err := r.prov.clients().IAM().Identities().Update(ctx, ident); err != nil {
+				resp.Diagnostics.Append(errorToDiagnostic(err, fmt.Sprintf("failed to update identity %q (%s)", name, current.ID.ValueString())))
+				continue
+			}
+			resp.Diagnostics.Append(want.populate(ctx, ident)...)
+			state.Identities[name] = want
+		}
+	}
+
+	// Set state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *identitiesResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Read the current state into the resource model.
+	var state identitiesResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Info(ctx, fmt.Sprintf("delete identities request: parent_id=%s", state.ParentID))
+
+	for _, name := range sortedKeys(state.Identities) {
+		if state.Identities[name].ID.IsNull() {
+			continue
+		}
+		id := state.Identities[name].ID.ValueString()
+		if _, err := r.prov.clients().IAM().Identities().Delete(ctx, &iam.DeleteIdentityRequest{Id: id}); err != nil {
+			resp.Diagnostics.Append(errorToDiagnostic(err, fmt.Sprintf("failed to delete identity %q (%s)", name, id)))
+			continue
+		}
+		delete(state.Identities, name)
+	}
+
+	// Keep any identities that failed to delete in state so they are retried.
+	if resp.Diagnostics.HasError() {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	}
+}
+
+// create creates the identity named name, reporting any failure to diags.
+func (r *identitiesResource) create(ctx context.Context, parentID, name string, elem identitiesElementModel, diags *diag.Diagnostics) (identitiesElementModel, bool) {
+	identity, err := elem.identity(ctx, name)
+	if err != nil {
+		diags.Append(errorToDiagnostic(err, fmt.Sprintf("failed to populate identity %q from plan", name)))
+		return elem, false
+	}
+
+	ident, err := r.prov.clients().IAM().Identities().Create(ctx, &iam.CreateIdentityRequest{
+		ParentId: parentID,
+		Identity: identity,
+	})
+	if err != nil {
+		diags.Append(errorToDiagnostic(err, fmt.Sprintf("failed to create identity %q", name)))
+		return elem, false
+	}
+
+	diags.Append(elem.populate(ctx, ident)...)
+	return elem, true
+}
+
+// sortedKeys returns the keys of m in order, so operations on a set of
+// identities happen in a stable order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := maps.Keys(m)
+	slices.Sort(keys)
+	return keys
+}
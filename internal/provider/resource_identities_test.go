@@ -0,0 +1,325 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	tfresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+
+	common "chainguard.dev/sdk/proto/platform/common/v1"
+	iam "chainguard.dev/sdk/proto/platform/iam/v1"
+	iamtest "chainguard.dev/sdk/proto/platform/iam/v1/test"
+	platformtest "chainguard.dev/sdk/proto/platform/test"
+)
+
+func TestAccResourceIdentities(t *testing.T) {
+	group := os.Getenv("TF_ACC_GROUP_ID")
+
+	childpattern := regexp.MustCompile(fmt.Sprintf(`%s\/[a-z0-9]{16}`, group))
+	issuer := "https://token.actions.githubusercontent.com"
+
+	// The id of an identity left untouched by later steps, which must not change.
+	var apiID string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read
+			{
+				Config: testAccResourceIdentities(group, issuer, map[string]string{
+					"ci-api": "repo:chainguard-dev/api:ref:refs/heads/main",
+					"ci-web": "repo:chainguard-dev/web:ref:refs/heads/main",
+				}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(`chainguard_identities.ci`, `identities.%`, "2"),
+					resource.TestMatchResourceAttr(`chainguard_identities.ci`, `identities.ci-api.id`, childpattern),
+					resource.TestCheckResourceAttrWith(`chainguard_identities.ci`, `identities.ci-api.id`, func(v string) error {
+						apiID = v
+						return nil
+					}),
+					resource.TestMatchResourceAttr(`chainguard_identities.ci`, `identities.ci-web.id`, childpattern),
+					resource.TestCheckResourceAttr(`chainguard_identities.ci`, `identities.ci-api.claim_match.subject`, "repo:chainguard-dev/api:ref:refs/heads/main"),
+				),
+			},
+			// Add one identity and remove another. The untouched identity
+			// keeps its id.
+			{
+				Config: testAccResourceIdentities(group, issuer, map[string]string{
+					"ci-api":  "repo:chainguard-dev/api:ref:refs/heads/main",
+					"ci-docs": "repo:chainguard-dev/docs:ref:refs/heads/main",
+				}),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(`chainguard_identities.ci`, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(`chainguard_identities.ci`, `identities.%`, "2"),
+					resource.TestCheckResourceAttrWith(`chainguard_identities.ci`, `identities.ci-api.id`, func(v string) error {
+						if v != apiID {
+							return fmt.Errorf("identity ci-api was replaced: id %q, wanted %q", v, apiID)
+						}
+						return nil
+					}),
+					resource.TestMatchResourceAttr(`chainguard_identities.ci`, `identities.ci-docs.id`, childpattern),
+					resource.TestCheckNoResourceAttr(`chainguard_identities.ci`, `identities.ci-web.id`),
+				),
+			},
+			// Update an identity in place.
+			{
+				Config: testAccResourceIdentities(group, issuer, map[string]string{
+					"ci-api":  "repo:chainguard-dev/api:ref:refs/heads/release",
+					"ci-docs": "repo:chainguard-dev/docs:ref:refs/heads/main",
+				}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(`chainguard_identities.ci`, `identities.%`, "2"),
+					resource.TestCheckResourceAttr(`chainguard_identities.ci`, `identities.ci-api.claim_match.subject`, "repo:chainguard-dev/api:ref:refs/heads/release"),
+				),
+			},
+		},
+	})
+}
+
+func testAccResourceIdentities(group, issuer string, subjects map[string]string) string {
+	names := make([]string, 0, len(subjects))
+	for name := range subjects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var identities strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&identities, `
+    %q = {
+      claim_match = {
+        issuer  = %q
+        subject = %q
+      }
+    }`, name, issuer, subjects[name])
+	}
+
+	tmpl := `
+resource "chainguard_identities" "ci" {
+  parent_id  = %q
+  identities = {%s
+  }
+}
+`
+	return fmt.Sprintf(tmpl, group, identities.String())
+}
+
+// testIdentitiesElement builds an identities element matching issuer and
+// subject. An empty id is left unknown, as it is when planned for creation.
+func testIdentitiesElement(t *testing.T, r *identitiesResource, issuer, id, description, subject string) identitiesElementModel {
+	t.Helper()
+	ctx := context.Background()
+
+	var sresp tfresource.SchemaResponse
+	r.Schema(ctx, tfresource.SchemaRequest{}, &sresp)
+	cmTypes := sresp.Schema.Attributes["identities"].(schema.MapNestedAttribute).
+		NestedObject.Attributes["claim_match"].GetType().(types.ObjectType).AttrTypes
+
+	cm, diags := types.ObjectValueFrom(ctx, cmTypes, claimMatchModel{
+		Issuer:        types.StringValue(issuer),
+		Subject:       types.StringValue(subject),
+		Claims:        types.MapNull(types.StringType),
+		ClaimPatterns: types.MapNull(types.StringType),
+	})
+	if diags.HasError() {
+		t.Fatalf("ObjectValueFrom() = %v", diags)
+	}
+	e := identitiesElementModel{ID: types.StringUnknown(), Description: types.StringNull(), ClaimMatch: cm}
+	if id != "" {
+		e.ID = types.StringValue(id)
+	}
+	if description != "" {
+		e.Description = types.StringValue(description)
+	}
+	return e
+}
+
+// testIdentitiesRelationship is the API form of testIdentitiesElement's claim_match.
+func testIdentitiesRelationship(issuer, subject string) *iam.Identity_ClaimMatch_ {
+	return &iam.Identity_ClaimMatch_{ClaimMatch: &iam.Identity_ClaimMatch{
+		Iss: &iam.Identity_ClaimMatch_Issuer{Issuer: issuer},
+		Sub: &iam.Identity_ClaimMatch_Subject{Subject: subject},
+	}}
+}
+
+func TestIdentitiesCreate_PartialFailure(t *testing.T) {
+	const (
+		parentID = "0123456789abcdef0123456789abcdef01234567"
+		issuer   = "https://issuer.example.com"
+		idA      = parentID + "/000000000000000a"
+	)
+	ctx := context.Background()
+	r := &identitiesResource{}
+	create := func(name string) *iam.CreateIdentityRequest {
+		return &iam.CreateIdentityRequest{
+			ParentId: parentID,
+			Identity: &iam.Identity{Name: name, Relationship: testIdentitiesRelationship(issuer, name)},
+		}
+	}
+
+	clients := &platformtest.MockPlatformClients{
+		IAMClient: iamtest.MockIAMClient{
+			IdentitiesClient: iamtest.MockIdentitiesClient{
+				OnCreate: []iamtest.IdentityOnCreate{{
+					Given:   create("a"),
+					Created: &iam.Identity{Id: idA, Name: "a", Relationship: testIdentitiesRelationship(issuer, "a")},
+				}, {
+					Given: create("b"),
+					Error: errors.New("boom"),
+				}},
+				OnList: []iamtest.IdentityOnList{{
+					Given: &iam.IdentityFilter{Uidp: &common.UIDPFilter{ChildrenOf: parentID}},
+					List: &iam.IdentityList{Items: []*iam.Identity{
+						{Id: idA, Name: "a", Relationship: testIdentitiesRelationship(issuer, "a")},
+					}},
+				}},
+			},
+		},
+	}
+	r.prov = &providerData{client: clients}
+
+	plan := testResourcePlan(t, r, map[string]any{
+		"parent_id": parentID,
+		"identities": map[string]identitiesElementModel{
+			"a": testIdentitiesElement(t, r, issuer, "", "", "a"),
+			"b": testIdentitiesElement(t, r, issuer, "", "", "b"),
+		},
+	})
+	cresp := &tfresource.CreateResponse{State: tfsdk.State{Schema: plan.Schema, Raw: plan.Raw}}
+	r.Create(ctx, tfresource.CreateRequest{Plan: plan}, cresp)
+
+	// An identity failing to be created must not taint the others.
+	if cresp.Diagnostics.HasError() {
+		t.Fatalf("Create() = %v, wanted only warnings", cresp.Diagnostics)
+	}
+	if got := cresp.Diagnostics.WarningsCount(); got != 1 {
+		t.Errorf("Create() warnings = %d, wanted 1: %v", got, cresp.Diagnostics)
+	}
+
+	// Both identities are kept to match the plan, but only a has an id.
+	var created identitiesResourceModel
+	if diags := cresp.State.Get(ctx, &created); diags.HasError() {
+		t.Fatalf("State.Get() = %v", diags)
+	}
+	if got := created.Identities["a"].ID; got.ValueString() != idA {
+		t.Errorf("identity a id = %s, wanted %q", got, idA)
+	}
+	if got := created.Identities["b"].ID; !got.IsNull() {
+		t.Errorf("identity b id = %s, wanted null", got)
+	}
+
+	// Read drops b, so the next plan retries it.
+	rresp := &tfresource.ReadResponse{State: cresp.State}
+	r.Read(ctx, tfresource.ReadRequest{State: cresp.State}, rresp)
+	if rresp.Diagnostics.HasError() {
+		t.Fatalf("Read() = %v", rresp.Diagnostics)
+	}
+	var read identitiesResourceModel
+	if diags := rresp.State.Get(ctx, &read); diags.HasError() {
+		t.Fatalf("State.Get() = %v", diags)
+	}
+	if _, ok := read.Identities["b"]; ok || len(read.Identities) != 1 {
+		t.Errorf("identities after Read = %v, wanted only a", read.Identities)
+	}
+}
+
+func TestIdentitiesUpdate_PartialFailure(t *testing.T) {
+	const (
+		parentID = "0123456789abcdef0123456789abcdef01234567"
+		issuer   = "https://issuer.example.com"
+		idA      = parentID + "/000000000000000a"
+		idB      = parentID + "/000000000000000b"
+		idC      = parentID + "/000000000000000c"
+	)
+	ctx := context.Background()
+	r := &identitiesResource{}
+	element := func(id, description, subject string) identitiesElementModel {
+		t.Helper()
+		return testIdentitiesElement(t, r, issuer, id, description, subject)
+	}
+	relationship := func(subject string) *iam.Identity_ClaimMatch_ {
+		return testIdentitiesRelationship(issuer, subject)
+	}
+
+	clients := &platformtest.MockPlatformClients{
+		IAMClient: iamtest.MockIAMClient{
+			IdentitiesClient: iamtest.MockIdentitiesClient{
+				OnDelete: []iamtest.IdentityOnDelete{{
+					Given: &iam.DeleteIdentityRequest{Id: idA},
+					Error: errors.New("boom"),
+				}},
+				OnUpdate: []iamtest.IdentityOnUpdate{{
+					Given:   &iam.Identity{Id: idB, Name: "b", Description: "updated", Relationship: relationship("b")},
+					Updated: &iam.Identity{Id: idB, Name: "b", Description: "updated", Relationship: relationship("b")},
+				}},
+				OnCreate: []iamtest.IdentityOnCreate{{
+					Given: &iam.CreateIdentityRequest{
+						ParentId: parentID,
+						Identity: &iam.Identity{Name: "c", Relationship: relationship("c")},
+					},
+					Created: &iam.Identity{Id: idC, Name: "c", Relationship: relationship("c")},
+				}},
+			},
+		},
+	}
+	r.prov = &providerData{client: clients}
+
+	state := testResourceState(t, r, map[string]any{
+		"parent_id": parentID,
+		"identities": map[string]identitiesElementModel{
+			"a": element(idA, "", "a"),
+			"b": element(idB, "", "b"),
+		},
+	})
+	plan := testResourcePlan(t, r, map[string]any{
+		"parent_id": parentID,
+		"identities": map[string]identitiesElementModel{
+			"b": element(idB, "updated", "b"),
+			"c": element("", "", "c"),
+		},
+	})
+	resp := &tfresource.UpdateResponse{State: state}
+	r.Update(ctx, tfresource.UpdateRequest{Plan: plan, State: state}, resp)
+
+	if got := resp.Diagnostics.ErrorsCount(); got != 1 {
+		t.Fatalf("Update() errors = %d, wanted 1: %v", got, resp.Diagnostics)
+	}
+
+	var got identitiesResourceModel
+	if diags := resp.State.Get(ctx, &got); diags.HasError() {
+		t.Fatalf("State.Get() = %v", diags)
+	}
+	want := map[string]string{"a": idA, "b": idB, "c": idC}
+	if len(got.Identities) != len(want) {
+		t.Fatalf("state has %d identities, wanted %d: %v", len(got.Identities), len(want), got.Identities)
+	}
+	for name, id := range want {
+		if e := got.Identities[name]; e.ID.ValueString() != id {
+			t.Errorf("identity %q id = %s, wanted %q", name, e.ID, id)
+		}
+	}
+	if d := got.Identities["b"].Description; d.ValueString() != "updated" {
+		t.Errorf("identity b description = %s, wanted %q", d, "updated")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
@@ -25,12 +26,13 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
-	"golang.org/x/exp/maps"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	common "chainguard.dev/sdk/proto/platform/common/v1"
 	iam "chainguard.dev/sdk/proto/platform/iam/v1"
 	"chainguard.dev/sdk/uidp"
 	"chainguard.dev/sdk/validation"
+	"github.com/chainguard-dev/terraform-provider-chainguard/internal/protoutil"
 	"github.com/chainguard-dev/terraform-provider-chainguard/internal/validators"
 )
 
@@ -39,6 +41,7 @@ var (
 	_ resource.Resource                = &identityResource{}
 	_ resource.ResourceWithConfigure   = &identityResource{}
 	_ resource.ResourceWithImportState = &identityResource{}
+	_ resource.ResourceWithModifyPlan  = &identityResource{}
 )
 
 // NewIdentityResource is a helper function to simplify the provider implementation.
@@ -60,6 +63,9 @@ type identityResourceModel struct {
 	ClaimMatch       types.Object `tfsdk:"claim_match"`
 	Static           types.Object `tfsdk:"static"`
 	ServicePrincipal types.String `tfsdk:"service_principal"`
+	Summary          types.String `tfsdk:"summary"`
+
+	ForceNewOnIssuerChange types.Bool `tfsdk:"force_new_on_issuer_change"`
 }
 
 type awsIdentityModel struct {
@@ -99,8 +105,6 @@ func (r *identityResource) Metadata(_ context.Context, req resource.MetadataRequ
 
 // Schema defines the schema for the resource.
 func (r *identityResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
-	servicePrincipals := maps.Keys(iam.ServicePrincipal_value)
-
 	resp.Schema = schema.Schema{
 		Description: "IAM Identity in the Chainguard platform.",
 		Attributes: map[string]schema.Attribute{
@@ -110,10 +114,10 @@ func (r *identityResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
 			"parent_id": schema.StringAttribute{
-				Description:   "The id of the group containing this identity.",
+				Description:   "The id of the group containing this identity, or \"/\" for an identity at the root, where the API permits it.",
 				Required:      true,
 				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
-				Validators:    []validator.String{validators.UIDP(false /* allowRootSentinel */)},
+				Validators:    []validator.String{validators.UIDP(true /* allowRootSentinel */)},
 			},
 			"name": schema.StringAttribute{
 				Description: "The name of this identity.",
@@ -125,11 +129,11 @@ func (r *identityResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 				Optional:    true,
 			},
 			"service_principal": schema.StringAttribute{
-				Description:   "An identity that may be assumed by a particular Chainguard service.",
+				Description:   "An identity that may be assumed by a particular Chainguard service. Must be one of: " + protoutil.EnumToQuotedString(iam.ServicePrincipal_value) + ".",
 				Optional:      true,
 				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
 				Validators: []validator.String{
-					stringvalidator.OneOf(servicePrincipals...),
+					protoutil.EnumOneOf(iam.ServicePrincipal_value),
 					// Only one relationship type may be defined.
 					// This mutex relationship need only be configured once, even if this attribute is not
 					// defined by the user.
@@ -141,6 +145,14 @@ func (r *identityResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 					),
 				},
 			},
+			"summary": schema.StringAttribute{
+				Description: "A human-readable description of who may assume this identity, derived from its relationship.",
+				Computed:    true,
+			},
+			"force_new_on_issuer_change": schema.BoolAttribute{
+				Description: "Replace this identity, rather than updating it in place, when claim_match.issuer or claim_match.issuer_pattern changes. Defaults to false.",
+				Optional:    true,
+			},
 		},
 		Blocks: map[string]schema.Block{
 			"aws_identity": schema.SingleNestedBlock{
@@ -203,76 +215,7 @@ func (r *identityResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 			},
 			"claim_match": schema.SingleNestedBlock{
 				Description: "An identity that may be assumed when its claims satisfy these constraints.",
-				Attributes: map[string]schema.Attribute{
-					"issuer": schema.StringAttribute{
-						Description: "The exact issuer that must appear in tokens to assume this identity.",
-						Optional:    true,
-						Validators: []validator.String{
-							validators.IsURL(true /* requireHTTPS */),
-							validators.IfParentDefined(
-								stringvalidator.ExactlyOneOf(
-									path.Root("claim_match").AtName("issuer").Expression(),
-									path.Root("claim_match").AtName("issuer_pattern").Expression(),
-								),
-							),
-						},
-					},
-					"issuer_pattern": schema.StringAttribute{
-						Description: "A pattern for matching acceptable issuers that appear in tokens to assume this identity.",
-						Optional:    true,
-						Validators: []validator.String{
-							validators.ValidRegExp(),
-						},
-					},
-					"subject": schema.StringAttribute{
-						Description: "The exact subject that must appear in tokens to assume this identity.",
-						Optional:    true,
-						Validators: []validator.String{
-							validators.IfParentDefined(
-								stringvalidator.ExactlyOneOf(
-									path.Root("claim_match").AtName("subject").Expression(),
-									path.Root("claim_match").AtName("subject_pattern").Expression(),
-								),
-							),
-						},
-					},
-					"subject_pattern": schema.StringAttribute{
-						Description: "A pattern for matching acceptable subjects that appear in tokens to assume this identity.",
-						Optional:    true,
-						Validators: []validator.String{
-							validators.ValidRegExp(),
-						},
-					},
-					// NB: claims and claim_patterns are neither required, nor mutually-exclusive.
-					"claims": schema.MapAttribute{
-						Description: "The exact custom claims that appear in tokens to assume this identity.",
-						Optional:    true,
-						ElementType: types.StringType,
-					},
-					"claim_patterns": schema.MapAttribute{
-						Description: "The custom claim patterns for matching acceptable custom claims that appear in tokens to assume this identity.",
-						Optional:    true,
-						ElementType: types.StringType,
-						Validators: []validator.Map{
-							mapvalidator.ValueStringsAre(validators.ValidRegExp()),
-						},
-					},
-					"audience": schema.StringAttribute{
-						Description: "The exact audience that must appear in tokens to assume this identity.",
-						Optional:    true,
-						Validators: []validator.String{
-							stringvalidator.ConflictsWith(path.Root("claim_match").AtName("audience_pattern").Expression()),
-						},
-					},
-					"audience_pattern": schema.StringAttribute{
-						Description: "A pattern for matching acceptable audiences that appear in tokens to assume this identity.",
-						Optional:    true,
-						Validators: []validator.String{
-							validators.ValidRegExp(),
-							stringvalidator.ConflictsWith(path.Root("claim_match").AtName("audience").Expression()),
-						},
-					},
-				},
+				Attributes:  claimMatchAttributes(true /* forceNewOnIssuer */),
 			},
 			"static": schema.SingleNestedBlock{
 				Description: "An identity that is verified by OIDC, with pre-registered verification keys.",
@@ -304,12 +247,15 @@ func (r *identityResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 					"issuer_keys": schema.StringAttribute{
 						Description: "The JSON web key set (JWKS) of the OIDC issuer that should be used to verify tokens.",
 						Optional:    true, // This attribute is required, but only if the block is defined. See Validators.
+						Validators: []validator.String{
+							validators.JWKS(),
+						},
 					},
 					"expiration": schema.StringAttribute{
-						Description: "The RFC3339 encoded date and time at which this identity will no longer be valid.",
+						Description: "The RFC3339 encoded date and time (in UTC) at which this identity will no longer be valid. Must be at least 5 minutes in the future.",
 						Optional:    true, // This attribute is required, but only if the block is defined. See Validators.
 						Validators: []validator.String{
-							validators.ValidateStringFuncs(checkRFC3339),
+							validators.FutureRFC3339(staticMinLead, true /* requireUTC */),
 						},
 					},
 				},
@@ -321,6 +267,10 @@ func (r *identityResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 // For testing.
 var timeNow = time.Now
 
+// staticMinLead is how far in the future static identity expirations must be,
+// to avoid creating credentials that are expired as soon as they're usable.
+const staticMinLead = 5 * time.Minute
+
 // checkRFC3339 implements validators.ValidateStringFunc.
 func checkRFC3339(raw string) error {
 	t, err := time.Parse(time.RFC3339, raw)
@@ -333,6 +283,114 @@ func checkRFC3339(raw string) error {
 	return nil
 }
 
+// claimMatchAttributes returns the attributes of a claim_match block. They are
+// shared with chainguard_identities, so validators use relative paths. When
+// forceNewOnIssuer is set, issuer changes honor force_new_on_issuer_change.
+func claimMatchAttributes(forceNewOnIssuer bool) map[string]schema.Attribute {
+	attrs := map[string]schema.Attribute{
+		"issuer": schema.StringAttribute{
+			Description: "The exact issuer that must appear in tokens to assume this identity.",
+			Optional:    true,
+			Validators: []validator.String{
+				validators.IsURL(true /* requireHTTPS */),
+				validators.IfParentDefined(
+					stringvalidator.ExactlyOneOf(
+						path.MatchRelative().AtParent().AtName("issuer"),
+						path.MatchRelative().AtParent().AtName("issuer_pattern"),
+					),
+				),
+			},
+		},
+		"issuer_pattern": schema.StringAttribute{
+			Description: "A pattern for matching acceptable issuers that appear in tokens to assume this identity.",
+			Optional:    true,
+			Validators: []validator.String{
+				validators.ValidRegExp(),
+			},
+		},
+		"subject": schema.StringAttribute{
+			Description: "The exact subject that must appear in tokens to assume this identity.",
+			Optional:    true,
+			Validators: []validator.String{
+				validators.IfParentDefined(
+					stringvalidator.ExactlyOneOf(
+						path.MatchRelative().AtParent().AtName("subject"),
+						path.MatchRelative().AtParent().AtName("subject_pattern"),
+					),
+				),
+			},
+		},
+		"subject_pattern": schema.StringAttribute{
+			Description: "A pattern for matching acceptable subjects that appear in tokens to assume this identity.",
+			Optional:    true,
+			Validators: []validator.String{
+				validators.ValidRegExp(),
+			},
+		},
+		// NB: claims and claim_patterns are neither required, nor mutually-exclusive.
+		"claims": schema.MapAttribute{
+			Description: "The exact custom claims that appear in tokens to assume this identity.",
+			Optional:    true,
+			ElementType: types.StringType,
+			Validators: []validator.Map{
+				validators.ExactClaims(),
+			},
+		},
+		"claim_patterns": schema.MapAttribute{
+			Description: "The custom claim patterns for matching acceptable custom claims that appear in tokens to assume this identity.",
+			Optional:    true,
+			ElementType: types.StringType,
+			Validators: []validator.Map{
+				mapvalidator.ValueStringsAre(validators.ValidRegExp()),
+				validators.ClaimPatterns(),
+			},
+		},
+		"audience": schema.StringAttribute{
+			Description: "The exact audience that must appear in tokens to assume this identity.",
+			Optional:    true,
+			Validators: []validator.String{
+				stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("audience_pattern")),
+			},
+		},
+		"audience_pattern": schema.StringAttribute{
+			Description: "A pattern for matching acceptable audiences that appear in tokens to assume this identity.",
+			Optional:    true,
+			Validators: []validator.String{
+				validators.ValidRegExp(),
+				stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("audience")),
+			},
+		},
+	}
+
+	if forceNewOnIssuer {
+		issuer := attrs["issuer"].(schema.StringAttribute)
+		issuer.PlanModifiers = []planmodifier.String{
+			stringplanmodifier.RequiresReplaceIf(replaceOnIssuerChange,
+				"Changing the issuer replaces the identity when force_new_on_issuer_change is set.",
+				"Changing the issuer replaces the identity when `force_new_on_issuer_change` is set."),
+		}
+		attrs["issuer"] = issuer
+
+		issuerPattern := attrs["issuer_pattern"].(schema.StringAttribute)
+		issuerPattern.PlanModifiers = []planmodifier.String{
+			stringplanmodifier.RequiresReplaceIf(replaceOnIssuerChange,
+				"Changing the issuer pattern replaces the identity when force_new_on_issuer_change is set.",
+				"Changing the issuer pattern replaces the identity when `force_new_on_issuer_change` is set."),
+		}
+		attrs["issuer_pattern"] = issuerPattern
+	}
+	return attrs
+}
+
+// replaceOnIssuerChange requires replacement of a changed claim_match issuer
+// or issuer_pattern when the identity opts in with force_new_on_issuer_change,
+// for backends that cannot update the issuer in place.
+func replaceOnIssuerChange(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+	var force types.Bool
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("force_new_on_issuer_change"), &force)...)
+	resp.RequiresReplace = force.ValueBool()
+}
+
 func populateModel(ctx context.Context, model *identityResourceModel, id *iam.Identity) diag.Diagnostics {
 	var allDiags diag.Diagnostics
 
@@ -347,7 +405,9 @@ func populateModel(ctx context.Context, model *identityResourceModel, id *iam.Id
 	model.ID = types.StringValue(id.Id)
 	model.ParentID = types.StringValue(uidp.Parent(id.Id))
 	model.Name = types.StringValue(id.Name)
-	if model.Description.IsNull() && id.Description != "" {
+	// Only leave the description null when it was never set and the server
+	// has none, so out-of-band changes (including clearing it) are detected.
+	if !(model.Description.IsNull() && id.Description == "") {
 		model.Description = types.StringValue(id.Description)
 	}
 
@@ -477,6 +537,8 @@ func populateModel(ctx context.Context, model *identityResourceModel, id *iam.Id
 		model.ServicePrincipal = types.StringNull()
 	}
 
+	model.Summary = types.StringValue(identitySummary(id))
+
 	return allDiags
 }
 
@@ -618,9 +680,129 @@ func populateIdentity(ctx context.Context, m identityResourceModel) (*iam.Identi
 	return id, nil
 }
 
+// ModifyPlan warns, on a best-effort basis, when another identity in the same
+// parent already uses the planned exact issuer and subject, since the server
+// only rejects the conflict at apply time. It is a warning to allow for false
+// positives, and is skipped when no client is set up yet rather than logging
+// in just to check.
+func (r *identityResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.prov == nil {
+		return
+	}
+	clients := r.prov.clients()
+	if clients == nil {
+		return
+	}
+
+	var plan identityResourceModel
+	// Identities at the root aren't checked, since there is no group to list.
+	if diags := req.Plan.Get(ctx, &plan); diags.HasError() || !uidp.Valid(plan.ParentID.ValueString()) {
+		return
+	}
+	attr, iss, sub := plan.exactIssuerSubject(ctx)
+	if iss == "" || sub == "" {
+		return
+	}
+	// Only check new issuer and subject pairs.
+	if !req.State.Raw.IsNull() {
+		var state identityResourceModel
+		if diags := req.State.Get(ctx, &state); !diags.HasError() {
+			if _, stateIss, stateSub := state.exactIssuerSubject(ctx); stateIss == iss && stateSub == sub {
+				return
+			}
+		}
+	}
+
+	identityList, err := clients.IAM().Identities().List(ctx, &iam.IdentityFilter{
+		Uidp: &common.UIDPFilter{
+			ChildrenOf: plan.ParentID.ValueString(),
+		},
+	})
+	if err != nil {
+		tflog.Warn(ctx, fmt.Sprintf("skipping identity conflict check, failed to list identities: %v", err))
+		return
+	}
+	for _, other := range identityList.GetItems() {
+		if other.GetId() == plan.ID.ValueString() {
+			continue
+		}
+		otherIss, otherSub := other.GetClaimMatch().GetIssuer(), other.GetClaimMatch().GetSubject()
+		if other.GetStatic() != nil {
+			otherIss, otherSub = other.GetStatic().GetIssuer(), other.GetStatic().GetSubject()
+		}
+		if otherIss == iss && otherSub == sub {
+			resp.Diagnostics.AddAttributeWarning(path.Root(attr), "identity may conflict with an existing identity",
+				fmt.Sprintf("Identity %q (%s) in %s already matches issuer %q and subject %q. "+
+					"The server requires (issuer, subject) pairs to be unique, so applying this plan may fail.",
+					other.GetName(), other.GetId(), plan.ParentID.ValueString(), iss, sub))
+		}
+	}
+}
+
+// exactIssuerSubject returns the block and the exact issuer and subject the
+// identity matches, if any. Patterns are not exact, so are ignored.
+func (m identityResourceModel) exactIssuerSubject(ctx context.Context) (attr, iss, sub string) {
+	switch {
+	case !m.ClaimMatch.IsNull() && !m.ClaimMatch.IsUnknown():
+		var cm claimMatchModel
+		if diags := m.ClaimMatch.As(ctx, &cm, basetypes.ObjectAsOptions{}); diags.HasError() {
+			return "", "", ""
+		}
+		return "claim_match", cm.Issuer.ValueString(), cm.Subject.ValueString()
+	case !m.Static.IsNull() && !m.Static.IsUnknown():
+		var st staticModel
+		if diags := m.Static.As(ctx, &st, basetypes.ObjectAsOptions{}); diags.HasError() {
+			return "", "", ""
+		}
+		return "static", st.Issuer.ValueString(), st.Subject.ValueString()
+	default:
+		return "", "", ""
+	}
+}
+
 // ImportState imports resources by ID into the current Terraform state.
+// Identities may also be imported by name, as <parent_id>/<name>.
 func (r *identityResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	if uidp.Valid(req.ID) {
+		resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+		return
+	}
+
+	i := strings.LastIndex(req.ID, "/")
+	if i < 0 || !uidp.Valid(req.ID[:i]) || req.ID[i+1:] == "" {
+		resp.Diagnostics.AddError("invalid import id",
+			fmt.Sprintf("expected an identity id or <parent_id>/<name>, got %q", req.ID))
+		return
+	}
+	parentID, name := req.ID[:i], req.ID[i+1:]
+
+	tflog.Info(ctx, fmt.Sprintf("import identity request: parent_id=%s, name=%s", parentID, name))
+
+	identityList, err := r.prov.clients().IAM().Identities().List(ctx, &iam.IdentityFilter{
+		Uidp: &common.UIDPFilter{
+			ChildrenOf: parentID,
+		},
+	})
+	if err != nil {
+		resp.Diagnostics.Append(errorToDiagnostic(err, "failed to list identities"))
+		return
+	}
+
+	var ids []string
+	for _, id := range identityList.GetItems() {
+		if id.GetName() == name {
+			ids = append(ids, id.GetId())
+		}
+	}
+	switch len(ids) {
+	case 0:
+		resp.Diagnostics.AddError("identity not found", fmt.Sprintf("no identity named %q in group %s", name, parentID))
+	case 1:
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), ids[0])...)
+	default:
+		resp.Diagnostics.AddError("ambiguous identity name",
+			fmt.Sprintf("%d identities named %q in group %s, import one by id instead: %s", len(ids), name, parentID, strings.Join(ids, ", ")))
+	}
 }
 
 // Create creates the resource and sets the initial Terraform state.
@@ -640,10 +822,14 @@ func (r *identityResource) Create(ctx context.Context, req resource.CreateReques
 	}
 
 	// Create the identity.
-	ident, err := r.prov.client.IAM().Identities().Create(ctx, &iam.CreateIdentityRequest{
-		ParentId: plan.ParentID.ValueString(),
+	cr := &iam.CreateIdentityRequest{
 		Identity: identity,
-	})
+	}
+	// Due to validation, we are guaranteed ParentID is either a valid UIDP or "/".
+	if uidp.Valid(plan.ParentID.ValueString()) {
+		cr.ParentId = plan.ParentID.ValueString()
+	}
+	ident, err := r.prov.clients().IAM().Identities().Create(ctx, cr)
 	if err != nil {
 		resp.Diagnostics.Append(errorToDiagnostic(err, "failed to create identity"))
 		return
@@ -655,6 +841,115 @@ func (r *identityResource) Create(ctx context.Context, req resource.CreateReques
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	markCreated(ctx, resp.Private)
+
+	// The identity exists at this point, so state is saved before verifying
+	// it. A failed check taints the resource rather than orphaning it.
+	if r.prov.strict && !resp.Diagnostics.HasError() {
+		resp.Diagnostics.Append(r.verifyRelationship(ctx, identity, ident.GetId())...)
+	}
+}
+
+// verifyRelationship lists the identity by id and checks the server persisted
+// the relationship type that was requested, catching silent coercions.
+func (r *identityResource) verifyRelationship(ctx context.Context, want *iam.Identity, id string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	identityList, err := r.prov.clients().IAM().Identities().List(ctx, &iam.IdentityFilter{
+		Id: id,
+	})
+	if err != nil {
+		diags.Append(errorToDiagnostic(err, "failed to verify identity"))
+		return diags
+	}
+	if c := len(identityList.GetItems()); c != 1 {
+		diags.AddError("failed to verify identity", fmt.Sprintf("expected one identity with id %s, found %d", id, c))
+		return diags
+	}
+
+	if got, wanted := relationshipType(identityList.Items[0]), relationshipType(want); got != wanted {
+		tflog.Error(ctx, fmt.Sprintf("identity %s persisted as %s, wanted %s", id, got, wanted))
+		diags.AddError("failed to verify identity",
+			fmt.Sprintf("identity %s was created with a %s relationship, but the server persisted a %s relationship", id, wanted, got))
+	}
+	return diags
+}
+
+// relationshipType returns the name of the schema block or attribute that
+// configures the identity's relationship.
+func relationshipType(id *iam.Identity) string {
+	switch id.GetRelationship().(type) {
+	case *iam.Identity_AwsIdentity:
+		return "aws_identity"
+	case *iam.Identity_ClaimMatch_:
+		return "claim_match"
+	case *iam.Identity_Static:
+		return "static"
+	case *iam.Identity_ServicePrincipal:
+		return "service_principal"
+	default:
+		return "unknown"
+	}
+}
+
+// githubActionsIssuer is the issuer of GitHub Actions OIDC tokens.
+const githubActionsIssuer = "https://token.actions.githubusercontent.com"
+
+// identitySummary describes who may assume the identity, for display only.
+func identitySummary(id *iam.Identity) string {
+	switch rel := id.GetRelationship().(type) {
+	case *iam.Identity_ClaimMatch_:
+		cm := rel.ClaimMatch
+		if cm.GetIssuer() == githubActionsIssuer {
+			if s := githubActionsSummary(cm.GetSubject()); s != "" {
+				return s
+			}
+		}
+		return fmt.Sprintf("Tokens from %s with %s",
+			matchSummary("issuer", cm.GetIssuer(), cm.GetIssuerPattern()),
+			matchSummary("subject", cm.GetSubject(), cm.GetSubjectPattern()))
+	case *iam.Identity_Static:
+		st := rel.Static
+		return fmt.Sprintf("Tokens from issuer %s with subject %s, verified with static keys until %s",
+			st.GetIssuer(), st.GetSubject(), st.GetExpiration().AsTime().Format(time.RFC3339))
+	case *iam.Identity_AwsIdentity:
+		aws := rel.AwsIdentity
+		return fmt.Sprintf("AWS identities in account %s with %s and %s", aws.GetAwsAccount(),
+			matchSummary("ARN", aws.GetArn(), aws.GetArnPattern()),
+			matchSummary("user ID", aws.GetUserId(), aws.GetUserIdPattern()))
+	case *iam.Identity_ServicePrincipal:
+		return fmt.Sprintf("The Chainguard %s service", rel.ServicePrincipal)
+	default:
+		return "Unknown relationship"
+	}
+}
+
+// matchSummary describes matching name against an exact value or a pattern.
+func matchSummary(name, exact, pattern string) string {
+	if pattern != "" {
+		return fmt.Sprintf("%s matching %q", name, pattern)
+	}
+	return name + " " + exact
+}
+
+// githubActionsSummary describes a GitHub Actions subject such as
+// "repo:org/x:ref:refs/heads/main", or returns "" if it isn't recognized.
+func githubActionsSummary(subject string) string {
+	parts := strings.SplitN(subject, ":", 4)
+	if len(parts) < 3 || parts[0] != "repo" {
+		return ""
+	}
+	repo := parts[1]
+	switch {
+	case len(parts) == 4 && parts[2] == "ref":
+		return fmt.Sprintf("GitHub Actions for repo %s on ref %s", repo, strings.TrimPrefix(parts[3], "refs/heads/"))
+	case len(parts) == 4 && parts[2] == "environment":
+		return fmt.Sprintf("GitHub Actions for repo %s in environment %s", repo, parts[3])
+	case len(parts) == 3 && parts[2] == "pull_request":
+		return fmt.Sprintf("GitHub Actions for pull requests to repo %s", repo)
+	default:
+		return ""
+	}
 }
 
 // Read refreshes the Terraform state with the latest data.
@@ -669,15 +964,18 @@ func (r *identityResource) Read(ctx context.Context, req resource.ReadRequest, r
 
 	// Query for the identity to update state
 	identID := state.ID.ValueString()
-	identityList, err := r.prov.client.IAM().Identities().List(ctx, &iam.IdentityFilter{
-		Id: identID,
+	items, err := listVisible(ctx, req.Private, func() ([]*iam.Identity, error) {
+		identityList, err := r.prov.clients().IAM().Identities().List(ctx, &iam.IdentityFilter{
+			Id: identID,
+		})
+		return identityList.GetItems(), err
 	})
 	if err != nil {
 		resp.Diagnostics.Append(errorToDiagnostic(err, "failed to list identities"))
 		return
 	}
 
-	switch c := len(identityList.GetItems()); {
+	switch c := len(items); {
 	case c == 0:
 		// Identity doesn't exist or was deleted outside TF
 		resp.State.RemoveResource(ctx)
@@ -688,7 +986,7 @@ func (r *identityResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
-	ident := identityList.Items[0]
+	ident := items[0]
 
 	// If any errors were encountered, exit before updating the state.
 	if resp.Diagnostics.Append(populateModel(ctx, &state, ident)...); resp.Diagnostics.HasError() {
@@ -715,7 +1013,7 @@ func (r *identityResource) Update(ctx context.Context, req resource.UpdateReques
 		return
 	}
 
-	if _, err = r.prov.client.IAM().Identities().Update(ctx, ident); err != nil {
+	if _, err = r.prov.clients().IAM().Identities().Update(ctx, ident); err != nil {
 		resp.Diagnostics.Append(errorToDiagnostic(err, fmt.Sprintf("failed to update identity %q", plan.ID.ValueString())))
 		return
 	}
@@ -740,7 +1038,7 @@ func (r *identityResource) Delete(ctx context.Context, req resource.DeleteReques
 	tflog.Info(ctx, fmt.Sprintf("delete identity request: %s", state.ID))
 
 	id := state.ID.ValueString()
-	_, err := r.prov.client.IAM().Identities().Delete(ctx, &iam.DeleteIdentityRequest{
+	_, err := r.prov.clients().IAM().Identities().Delete(ctx, &iam.DeleteIdentityRequest{
 		Id: id,
 	})
 	if err != nil {
@@ -194,6 +194,7 @@ func (r *identityProviderResource) Create(ctx context.Context, req resource.Crea
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx = maskSensitive(ctx, req.Plan.Schema.AttributeAtTerraformPath, req.Plan.Raw)
 	tflog.Info(ctx, fmt.Sprintf("create identity provider: parent_id=%s, name=%s", plan.ParentID, plan.Name))
 
 	idp, err := populateIDP(ctx, &plan)
@@ -202,7 +203,7 @@ func (r *identityProviderResource) Create(ctx context.Context, req resource.Crea
 		return
 	}
 
-	idp, err = r.prov.client.IAM().IdentityProviders().Create(ctx, &iam.CreateIdentityProviderRequest{
+	idp, err = r.prov.clients().IAM().IdentityProviders().Create(ctx, &iam.CreateIdentityProviderRequest{
 		ParentId:         plan.ParentID.ValueString(),
 		IdentityProvider: idp,
 	})
@@ -224,10 +225,11 @@ func (r *identityProviderResource) Read(ctx context.Context, req resource.ReadRe
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx = maskSensitive(ctx, req.State.Schema.AttributeAtTerraformPath, req.State.Raw)
 	tflog.Info(ctx, fmt.Sprintf("read identity provider request: %s", state.ID))
 
 	id := state.ID.ValueString()
-	idpList, err := r.prov.client.IAM().IdentityProviders().List(ctx, &iam.IdentityProviderFilter{
+	idpList, err := r.prov.clients().IAM().IdentityProviders().List(ctx, &iam.IdentityProviderFilter{
 		Id: id,
 	})
 	if err != nil {
@@ -305,6 +307,7 @@ func (r *identityProviderResource) Update(ctx context.Context, req resource.Upda
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx = maskSensitive(ctx, req.Plan.Schema.AttributeAtTerraformPath, req.Plan.Raw)
 	tflog.Info(ctx, fmt.Sprintf("update identity provider request: %s", data.ID))
 
 	idp, err := populateIDP(ctx, &data)
@@ -313,7 +316,7 @@ func (r *identityProviderResource) Update(ctx context.Context, req resource.Upda
 		return
 	}
 
-	if _, err := r.prov.client.IAM().IdentityProviders().Update(ctx, idp); err != nil {
+	if _, err := r.prov.clients().IAM().IdentityProviders().Update(ctx, idp); err != nil {
 		resp.Diagnostics.Append(errorToDiagnostic(err, "failed to update identity provider"))
 		return
 	}
@@ -329,10 +332,11 @@ func (r *identityProviderResource) Delete(ctx context.Context, req resource.Dele
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx = maskSensitive(ctx, req.State.Schema.AttributeAtTerraformPath, req.State.Raw)
 	tflog.Info(ctx, fmt.Sprintf("delete identity provider request: %s", state.ID))
 
 	id := state.ID.ValueString()
-	_, err := r.prov.client.IAM().IdentityProviders().Delete(ctx, &iam.DeleteIdentityProviderRequest{
+	_, err := r.prov.clients().IAM().IdentityProviders().Delete(ctx, &iam.DeleteIdentityProviderRequest{
 		Id: id,
 	})
 	if err != nil {
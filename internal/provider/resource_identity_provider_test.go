@@ -6,13 +6,21 @@ SPDX-License-Identifier: Apache-2.0
 package provider
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"regexp"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	tfresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	iam "chainguard.dev/sdk/proto/platform/iam/v1"
+	iamtest "chainguard.dev/sdk/proto/platform/iam/v1/test"
+	platformtest "chainguard.dev/sdk/proto/platform/test"
 )
 
 type oidc struct {
@@ -115,3 +123,86 @@ resource "chainguard_identity_provider" "example" {
 `
 	return fmt.Sprintf(tmpl, idp.parentID, idp.name, idp.description, idp.defaultRole, idp.oidc.issuer, idp.oidc.clientID, idp.oidc.clientSecret, idp.oidc.additionalScopes)
 }
+
+func TestIdentityProviderRead_Description(t *testing.T) {
+	const (
+		parent = "0123456789abcdef0123456789abcdef01234567"
+		id     = parent + "/0123456789abcdef"
+	)
+	tests := map[string]struct {
+		state  types.String
+		remote string
+		want   types.String
+	}{
+		"unset stays null": {
+			state: types.StringNull(),
+			want:  types.StringNull(),
+		},
+		"set remotely": {
+			state:  types.StringNull(),
+			remote: "added elsewhere",
+			want:   types.StringValue("added elsewhere"),
+		},
+		"cleared remotely": {
+			state: types.StringValue("was set"),
+			want:  types.StringValue(""),
+		},
+		"unchanged": {
+			state:  types.StringValue("same"),
+			remote: "same",
+			want:   types.StringValue("same"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			clients := &platformtest.MockPlatformClients{
+				IAMClient: iamtest.MockIAMClient{
+					IdentityProvidersClient: iamtest.MockIdentityProvidersClient{
+						OnList: []iamtest.IdentityProvidersOnList{{
+							Given: &iam.IdentityProviderFilter{Id: id},
+							List: &iam.IdentityProviderList{Items: []*iam.IdentityProvider{{
+								Id:          id,
+								Name:        "example",
+								Description: test.remote,
+								DefaultRole: parent,
+								Configuration: &iam.IdentityProvider_Oidc{Oidc: &iam.IdentityProvider_OIDC{
+									Issuer:   "https://issuer.example.com",
+									ClientId: "client",
+								}},
+							}}},
+						}},
+					},
+				},
+			}
+			ctx := context.Background()
+			r := &identityProviderResource{managedResource{prov: &providerData{client: clients}}}
+
+			state := testResourceState(t, r, map[string]any{
+				"id":           id,
+				"parent_id":    parent,
+				"name":         "example",
+				"description":  test.state,
+				"default_role": parent,
+				"oidc": oidcResourceModel{
+					Issuer:           types.StringValue("https://issuer.example.com"),
+					ClientID:         types.StringValue("client"),
+					ClientSecret:     types.StringValue("secret"),
+					AdditionalScopes: types.ListNull(types.StringType),
+				},
+			})
+			resp := &tfresource.ReadResponse{State: state}
+			r.Read(ctx, tfresource.ReadRequest{State: state}, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Read() = %v", resp.Diagnostics)
+			}
+			var got types.String
+			if diags := resp.State.GetAttribute(ctx, path.Root("description"), &got); diags.HasError() {
+				t.Fatalf("GetAttribute(description) = %v", diags)
+			}
+			if !got.Equal(test.want) {
+				t.Errorf("description = %v, wanted %v", got, test.want)
+			}
+		})
+	}
+}
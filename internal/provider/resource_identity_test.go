@@ -7,6 +7,9 @@ package provider
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	crand "crypto/rand"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -20,14 +23,25 @@ import (
 	"time"
 
 	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	tfresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	sdkauth "chainguard.dev/sdk/auth"
 	"chainguard.dev/sdk/proto/platform"
+	common "chainguard.dev/sdk/proto/platform/common/v1"
 	iam "chainguard.dev/sdk/proto/platform/iam/v1"
+	iamtest "chainguard.dev/sdk/proto/platform/iam/v1/test"
+	platformtest "chainguard.dev/sdk/proto/platform/test"
 	"chainguard.dev/sdk/sts"
 	"chainguard.dev/sdk/uidp"
 )
@@ -40,6 +54,25 @@ func literal(s string) *regexp.Regexp {
 	return regexp.MustCompile(s)
 }
 
+// testJWKS returns a JSON web key set containing a freshly generated public key.
+func testJWKS(t *testing.T) string {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), crand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() = %v", err)
+	}
+	b, err := json.Marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{
+		Key:       priv.Public(),
+		KeyID:     "test",
+		Algorithm: string(jose.ES256),
+		Use:       "sig",
+	}}})
+	if err != nil {
+		t.Fatalf("json.Marshal() = %v", err)
+	}
+	return string(b)
+}
+
 func checkRegexp(r string) error {
 	_, err := regexp.Compile(r)
 	return err
@@ -245,8 +278,8 @@ func TestAccResourceStaticIdentity(t *testing.T) {
 	newIssuer := "https://token.githubusercontent.com"
 	subject := "robot@my-project.iam.gserviceaccount.com"
 	newSubject := "android@my-project.iam.gserviceaccount.com"
-	issuerKeys := "keys"
-	newIssuerKeys := "keyz"
+	issuerKeys := testJWKS(t)
+	newIssuerKeys := testJWKS(t)
 	expiration := time.Now().Add(3 * time.Hour).UTC().Format(time.RFC3339)
 	newExpiration := time.Now().Add(4 * time.Hour).UTC().Format(time.RFC3339)
 
@@ -261,7 +294,7 @@ func TestAccResourceStaticIdentity(t *testing.T) {
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `id`, childpattern),
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `name`, literal("bill")),
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer`, literal(issuer)),
-					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer_keys`, literal(issuerKeys)),
+					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer_keys`, literal(regexp.QuoteMeta(issuerKeys))),
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.subject`, literal(subject)),
 				),
 			},
@@ -271,7 +304,7 @@ func TestAccResourceStaticIdentity(t *testing.T) {
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `id`, childpattern),
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `name`, literal("ted")),
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer`, literal(issuer)),
-					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer_keys`, literal(issuerKeys)),
+					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer_keys`, literal(regexp.QuoteMeta(issuerKeys))),
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.subject`, literal(subject)),
 				),
 			},
@@ -288,7 +321,7 @@ func TestAccResourceStaticIdentity(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `id`, childpattern),
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer`, literal(issuer)),
-					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer_keys`, literal(issuerKeys)),
+					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer_keys`, literal(regexp.QuoteMeta(issuerKeys))),
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.subject`, literal(subject)),
 				),
 			},
@@ -297,7 +330,7 @@ func TestAccResourceStaticIdentity(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `id`, childpattern),
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer`, literal(newIssuer)),
-					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer_keys`, literal(issuerKeys)),
+					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer_keys`, literal(regexp.QuoteMeta(issuerKeys))),
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.subject`, literal(subject)),
 				),
 			},
@@ -314,7 +347,7 @@ func TestAccResourceStaticIdentity(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `id`, childpattern),
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer`, literal(issuer)),
-					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer_keys`, literal(issuerKeys)),
+					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer_keys`, literal(regexp.QuoteMeta(issuerKeys))),
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.subject`, literal(subject)),
 				),
 			},
@@ -323,7 +356,7 @@ func TestAccResourceStaticIdentity(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `id`, childpattern),
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer`, literal(issuer)),
-					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer_keys`, literal(issuerKeys)),
+					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer_keys`, literal(regexp.QuoteMeta(issuerKeys))),
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.subject`, literal(newSubject)),
 				),
 			},
@@ -340,7 +373,7 @@ func TestAccResourceStaticIdentity(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `id`, childpattern),
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer`, literal(issuer)),
-					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer_keys`, literal(issuerKeys)),
+					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer_keys`, literal(regexp.QuoteMeta(issuerKeys))),
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.subject`, literal(subject)),
 				),
 			},
@@ -349,7 +382,7 @@ func TestAccResourceStaticIdentity(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `id`, childpattern),
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer`, literal(issuer)),
-					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer_keys`, literal(newIssuerKeys)),
+					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer_keys`, literal(regexp.QuoteMeta(newIssuerKeys))),
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.subject`, literal(subject)),
 				),
 			},
@@ -366,7 +399,7 @@ func TestAccResourceStaticIdentity(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `id`, childpattern),
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer`, literal(issuer)),
-					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer_keys`, literal(issuerKeys)),
+					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer_keys`, literal(regexp.QuoteMeta(issuerKeys))),
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.subject`, literal(subject)),
 				),
 			},
@@ -375,7 +408,7 @@ func TestAccResourceStaticIdentity(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `id`, childpattern),
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer`, literal(issuer)),
-					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer_keys`, literal(issuerKeys)),
+					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer_keys`, literal(regexp.QuoteMeta(issuerKeys))),
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.subject`, literal(subject)),
 				),
 			},
@@ -505,7 +538,7 @@ func TestAccResourceIdentityTypeChange(t *testing.T) {
 	service := "INGESTER"
 	issuer := "https://accounts.google.com"
 	subject := "robot@my-project.iam.gserviceaccount.com"
-	issuerKeys := "keys"
+	issuerKeys := testJWKS(t)
 	expiration := time.Now().UTC().Add(3 * time.Hour).Format(time.RFC3339)
 
 	// Check changing claim_match to service_principal.
@@ -553,7 +586,7 @@ func TestAccResourceIdentityTypeChange(t *testing.T) {
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `id`, childpattern),
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `name`, literal("bill")),
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer`, literal(issuer)),
-					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer_keys`, literal(issuerKeys)),
+					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer_keys`, literal(regexp.QuoteMeta(issuerKeys))),
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.subject`, literal(subject)),
 					resource.TestCheckNoResourceAttr(`chainguard_identity.user`, `service_principal`),
 				),
@@ -572,7 +605,7 @@ func TestAccResourceIdentityTypeChange(t *testing.T) {
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `id`, childpattern),
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `name`, literal("bill")),
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer`, literal(issuer)),
-					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer_keys`, literal(issuerKeys)),
+					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.issuer_keys`, literal(regexp.QuoteMeta(issuerKeys))),
 					resource.TestMatchResourceAttr(`chainguard_identity.user`, `static.subject`, literal(subject)),
 				),
 			},
@@ -599,6 +632,153 @@ func randString(n int) string {
 	return string(b)
 }
 
+func TestAccResourceIdentityForceNewOnIssuerChange(t *testing.T) {
+	group := os.Getenv("TF_ACC_GROUP_ID")
+
+	issuer := "https://accounts.google.com"
+	newIssuer := "https://token.actions.githubusercontent.com"
+	subject := "robot@my-project.iam.gserviceaccount.com"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read
+			{
+				Config: testAccResourceIdentityForceNew(group, "forced", true, `issuer = "`+issuer+`"`, subject),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(`chainguard_identity.user`, `force_new_on_issuer_change`, "true"),
+					resource.TestCheckResourceAttr(`chainguard_identity.user`, `claim_match.issuer`, issuer),
+				),
+			},
+			// Changing the issuer replaces the identity.
+			{
+				Config: testAccResourceIdentityForceNew(group, "forced", true, `issuer = "`+newIssuer+`"`, subject),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(`chainguard_identity.user`, plancheck.ResourceActionReplace),
+					},
+				},
+				Check: resource.TestCheckResourceAttr(`chainguard_identity.user`, `claim_match.issuer`, newIssuer),
+			},
+			// Switching to an issuer pattern also replaces the identity.
+			{
+				Config: testAccResourceIdentityForceNew(group, "forced", true, `issuer_pattern = "`+pattern(newIssuer)+`"`, subject),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(`chainguard_identity.user`, plancheck.ResourceActionReplace),
+					},
+				},
+				Check: resource.TestCheckResourceAttr(`chainguard_identity.user`, `claim_match.issuer_pattern`, pattern(newIssuer)),
+			},
+			// Other changes are still made in place.
+			{
+				Config: testAccResourceIdentityForceNew(group, "renamed", true, `issuer_pattern = "`+pattern(newIssuer)+`"`, subject),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(`chainguard_identity.user`, plancheck.ResourceActionUpdate),
+					},
+				},
+			},
+			// Without the flag, issuer changes are updated in place.
+			{
+				Config: testAccResourceIdentityForceNew(group, "renamed", false, `issuer = "`+issuer+`"`, subject),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(`chainguard_identity.user`, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.TestCheckResourceAttr(`chainguard_identity.user`, `claim_match.issuer`, issuer),
+			},
+		},
+	})
+}
+
+func TestAccResourceIdentityImportByName(t *testing.T) {
+	group := os.Getenv("TF_ACC_GROUP_ID")
+	name := "import-" + acctest.RandString(8)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceIdentityServicePrincipal(group, name, "COSIGNED"),
+			},
+			// Import by <parent_id>/<name>.
+			{
+				ResourceName:      "chainguard_identity.user",
+				ImportState:       true,
+				ImportStateId:     group + "/" + name,
+				ImportStateVerify: true,
+			},
+		},
+	})
+
+	// The literal config creates two identities with the same name, so the
+	// name cannot be resolved to a single identity.
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceIdentityLiteral(group, name, "https://accounts.google.com", "robot@my-project.iam.gserviceaccount.com"),
+			},
+			{
+				ResourceName:  "chainguard_identity.user",
+				ImportState:   true,
+				ImportStateId: group + "/" + name,
+				ExpectError:   regexp.MustCompile(`ambiguous identity name`),
+			},
+		},
+	})
+}
+
+func TestAccResourceRootIdentity(t *testing.T) {
+	if os.Getenv(EnvAccAmbient) == "" && os.Getenv("TF_CHAINGUARD_IDENTITY_TOKEN") == "" {
+		t.Skip("TF_CHAINGUARD_IDENTITY_TOKEN or TF_ACC_AMBIENT required for root identity acceptance test")
+	}
+	name := acctest.RandString(10)
+	subject := acctest.RandString(10)
+
+	rootPattern := regexp.MustCompile(`^[a-z0-9]{40}$`)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing.
+			{
+				Config: fmt.Sprintf(`
+resource "chainguard_identity" "test" {
+  parent_id = "/"
+  name      = %q
+  claim_match {
+    issuer  = "https://accounts.google.com"
+    subject = %q
+  }
+}
+`, name, subject),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("chainguard_identity.test", "parent_id", "/"),
+					resource.TestCheckResourceAttr("chainguard_identity.test", "name", name),
+					resource.TestMatchResourceAttr("chainguard_identity.test", "id", rootPattern),
+				),
+			},
+
+			// ImportState testing.
+			{
+				ResourceName:            "chainguard_identity.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"claim_match.claims", "claim_match.claim_patterns"},
+			},
+
+			// Delete testing automatically occurs in TestCase.
+		},
+	})
+}
+
 func TestAccResourceIdentityUsage(t *testing.T) {
 	group := os.Getenv("TF_ACC_GROUP_ID")
 
@@ -1088,6 +1268,21 @@ claim_match {
 	)
 }
 
+func testAccResourceIdentityForceNew(group, name string, force bool, issuer, subject string) string {
+	tmpl := `
+resource "chainguard_identity" "user" {
+parent_id                  = %q
+name                       = %q
+force_new_on_issuer_change = %t
+claim_match {
+  %s
+  subject = %q
+}
+}
+`
+	return fmt.Sprintf(tmpl, group, name, force, issuer, subject)
+}
+
 func testAccResourceIdentityClaimMatch(group, name, issuerPattern, subjectPattern, audience string, claims, claimPatterns map[string]string) string {
 	tmpl := `
 resource "chainguard_identity" "user" {
@@ -1188,3 +1383,453 @@ service_principal = %q
 		service,
 	)
 }
+
+func TestIdentityRead_Description(t *testing.T) {
+	const identityID = "0123456789abcdef0123456789abcdef01234567/0123456789abcdef"
+
+	tests := map[string]struct {
+		state    any // nil leaves description null in state
+		server   string
+		want     string
+		wantNull bool
+	}{
+		"unset and empty": {
+			server:   "",
+			wantNull: true,
+		},
+		"unset and set out of band": {
+			server: "added",
+			want:   "added",
+		},
+		"changed out of band": {
+			state:  "original",
+			server: "changed",
+			want:   "changed",
+		},
+		"cleared out of band": {
+			state:  "original",
+			server: "",
+			want:   "",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			clients := &platformtest.MockPlatformClients{
+				IAMClient: iamtest.MockIAMClient{
+					IdentitiesClient: iamtest.MockIdentitiesClient{
+						OnList: []iamtest.IdentityOnList{{
+							Given: &iam.IdentityFilter{Id: identityID},
+							List: &iam.IdentityList{Items: []*iam.Identity{{
+								Id:          identityID,
+								Name:        "identity",
+								Description: test.server,
+								Relationship: &iam.Identity_ServicePrincipal{
+									ServicePrincipal: iam.ServicePrincipal_COSIGNED,
+								},
+							}}},
+						}},
+					},
+				},
+			}
+
+			r := &identityResource{managedResource{prov: &providerData{client: clients}}}
+			attrs := map[string]any{
+				"id":                identityID,
+				"parent_id":         uidp.Parent(identityID),
+				"name":              "identity",
+				"service_principal": "COSIGNED",
+			}
+			if test.state != nil {
+				attrs["description"] = test.state
+			}
+			state := testResourceState(t, r, attrs)
+			resp := &tfresource.ReadResponse{State: state}
+			r.Read(context.Background(), tfresource.ReadRequest{State: state}, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Read() = %v", resp.Diagnostics)
+			}
+
+			var got identityResourceModel
+			if diags := resp.State.Get(context.Background(), &got); diags.HasError() {
+				t.Fatalf("State.Get() = %v", diags)
+			}
+			if got.Description.IsNull() != test.wantNull || got.Description.ValueString() != test.want {
+				t.Errorf("description = %s, wanted %q (null: %t)", got.Description, test.want, test.wantNull)
+			}
+		})
+	}
+}
+
+func TestIdentityCreate_Strict(t *testing.T) {
+	const (
+		parentID   = "0123456789abcdef0123456789abcdef01234567"
+		identityID = parentID + "/0123456789abcdef"
+	)
+	servicePrincipal := &iam.Identity_ServicePrincipal{ServicePrincipal: iam.ServicePrincipal_COSIGNED}
+
+	tests := map[string]struct {
+		strict    bool
+		persisted *iam.Identity
+		wantErr   bool
+	}{
+		"strict and persisted as configured": {
+			strict:    true,
+			persisted: &iam.Identity{Id: identityID, Name: "identity", Relationship: servicePrincipal},
+		},
+		"strict and coerced": {
+			strict: true,
+			persisted: &iam.Identity{Id: identityID, Name: "identity", Relationship: &iam.Identity_ClaimMatch_{
+				ClaimMatch: &iam.Identity_ClaimMatch{Iss: &iam.Identity_ClaimMatch_Issuer{Issuer: "https://issuer.example.com"}},
+			}},
+			wantErr: true,
+		},
+		"not strict and coerced": {
+			persisted: &iam.Identity{Id: identityID, Name: "identity", Relationship: &iam.Identity_ClaimMatch_{
+				ClaimMatch: &iam.Identity_ClaimMatch{Iss: &iam.Identity_ClaimMatch_Issuer{Issuer: "https://issuer.example.com"}},
+			}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			clients := &platformtest.MockPlatformClients{
+				IAMClient: iamtest.MockIAMClient{
+					IdentitiesClient: iamtest.MockIdentitiesClient{
+						OnCreate: []iamtest.IdentityOnCreate{{
+							Given: &iam.CreateIdentityRequest{
+								ParentId: parentID,
+								Identity: &iam.Identity{Name: "identity", Relationship: servicePrincipal},
+							},
+							Created: &iam.Identity{Id: identityID, Name: "identity", Relationship: servicePrincipal},
+						}},
+						OnList: []iamtest.IdentityOnList{{
+							Given: &iam.IdentityFilter{Id: identityID},
+							List:  &iam.IdentityList{Items: []*iam.Identity{test.persisted}},
+						}},
+					},
+				},
+			}
+
+			r := &identityResource{managedResource{prov: &providerData{client: clients, strict: test.strict}}}
+			plan := testResourcePlan(t, r, map[string]any{
+				"parent_id":         parentID,
+				"name":              "identity",
+				"service_principal": "COSIGNED",
+			})
+			resp := &tfresource.CreateResponse{State: testResourceState(t, r, nil)}
+			r.Create(context.Background(), tfresource.CreateRequest{Plan: plan}, resp)
+
+			if got := resp.Diagnostics.HasError(); got != test.wantErr {
+				t.Fatalf("Create() error = %t, wanted %t: %v", got, test.wantErr, resp.Diagnostics)
+			}
+
+			// State is saved even when verification fails, so the identity
+			// is tainted rather than orphaned.
+			var got identityResourceModel
+			if diags := resp.State.Get(context.Background(), &got); diags.HasError() {
+				t.Fatalf("State.Get() = %v", diags)
+			}
+			if got.ID.ValueString() != identityID {
+				t.Errorf("Create() id = %q, wanted %q", got.ID.ValueString(), identityID)
+			}
+		})
+	}
+}
+
+func TestIdentityCreate_Root(t *testing.T) {
+	const identityID = "0123456789abcdef0123456789abcdef01234567"
+	servicePrincipal := &iam.Identity_ServicePrincipal{ServicePrincipal: iam.ServicePrincipal_COSIGNED}
+
+	// The root sentinel is sent as an empty parent, as for root groups.
+	clients := &platformtest.MockPlatformClients{
+		IAMClient: iamtest.MockIAMClient{
+			IdentitiesClient: iamtest.MockIdentitiesClient{
+				OnCreate: []iamtest.IdentityOnCreate{{
+					Given: &iam.CreateIdentityRequest{
+						Identity: &iam.Identity{Name: "identity", Relationship: servicePrincipal},
+					},
+					Created: &iam.Identity{Id: identityID, Name: "identity", Relationship: servicePrincipal},
+				}},
+			},
+		},
+	}
+
+	r := &identityResource{managedResource{prov: &providerData{client: clients}}}
+	plan := testResourcePlan(t, r, map[string]any{
+		"parent_id":         "/",
+		"name":              "identity",
+		"service_principal": "COSIGNED",
+	})
+	resp := &tfresource.CreateResponse{State: testResourceState(t, r, nil)}
+	r.Create(context.Background(), tfresource.CreateRequest{Plan: plan}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Create() = %v", resp.Diagnostics)
+	}
+
+	var got identityResourceModel
+	if diags := resp.State.Get(context.Background(), &got); diags.HasError() {
+		t.Fatalf("State.Get() = %v", diags)
+	}
+	if got.ID.ValueString() != identityID || got.ParentID.ValueString() != "/" {
+		t.Errorf("Create() id, parent_id = %q, %q, wanted %q, %q", got.ID.ValueString(), got.ParentID.ValueString(), identityID, "/")
+	}
+}
+
+func TestIdentityImportState(t *testing.T) {
+	const (
+		parentID = "0123456789abcdef0123456789abcdef01234567"
+		idA      = parentID + "/000000000000000a"
+		idB      = parentID + "/000000000000000b"
+		idC      = parentID + "/000000000000000c"
+	)
+	clients := &platformtest.MockPlatformClients{
+		IAMClient: iamtest.MockIAMClient{
+			IdentitiesClient: iamtest.MockIdentitiesClient{
+				OnList: []iamtest.IdentityOnList{{
+					Given: &iam.IdentityFilter{Uidp: &common.UIDPFilter{ChildrenOf: parentID}},
+					List: &iam.IdentityList{Items: []*iam.Identity{
+						{Id: idA, Name: "unique"},
+						{Id: idB, Name: "twin"},
+						{Id: idC, Name: "twin"},
+					}},
+				}},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		id      string
+		wantID  string
+		wantErr string
+	}{
+		"uidp": {
+			id:     idB,
+			wantID: idB,
+		},
+		"name path": {
+			id:     parentID + "/unique",
+			wantID: idA,
+		},
+		"ambiguous name": {
+			id:      parentID + "/twin",
+			wantErr: "ambiguous identity name",
+		},
+		"unknown name": {
+			id:      parentID + "/missing",
+			wantErr: "identity not found",
+		},
+		"invalid parent": {
+			id:      "not-a-group/unique",
+			wantErr: "invalid import id",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := &identityResource{managedResource{prov: &providerData{client: clients}}}
+			resp := &tfresource.ImportStateResponse{State: testResourceState(t, r, nil)}
+			r.ImportState(context.Background(), tfresource.ImportStateRequest{ID: test.id}, resp)
+
+			if test.wantErr != "" {
+				if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != test.wantErr {
+					t.Fatalf("ImportState() = %v, wanted error %q", resp.Diagnostics, test.wantErr)
+				}
+				return
+			}
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("ImportState() = %v", resp.Diagnostics)
+			}
+			var got types.String
+			if diags := resp.State.GetAttribute(context.Background(), path.Root("id"), &got); diags.HasError() {
+				t.Fatalf("State.GetAttribute() = %v", diags)
+			}
+			if got.ValueString() != test.wantID {
+				t.Errorf("ImportState() id = %s, wanted %q", got, test.wantID)
+			}
+		})
+	}
+}
+
+func TestIdentityModifyPlan_Conflict(t *testing.T) {
+	const (
+		parentID = "0123456789abcdef0123456789abcdef01234567"
+		idA      = parentID + "/000000000000000a"
+		idB      = parentID + "/000000000000000b"
+		issuer   = "https://token.actions.githubusercontent.com"
+		subject  = "repo:example/repo:ref:refs/heads/main"
+	)
+	clients := &platformtest.MockPlatformClients{
+		IAMClient: iamtest.MockIAMClient{
+			IdentitiesClient: iamtest.MockIdentitiesClient{
+				OnList: []iamtest.IdentityOnList{{
+					Given: &iam.IdentityFilter{Uidp: &common.UIDPFilter{ChildrenOf: parentID}},
+					List: &iam.IdentityList{Items: []*iam.Identity{{
+						Id:   idA,
+						Name: "existing",
+						Relationship: &iam.Identity_ClaimMatch_{ClaimMatch: &iam.Identity_ClaimMatch{
+							Iss: &iam.Identity_ClaimMatch_Issuer{Issuer: issuer},
+							Sub: &iam.Identity_ClaimMatch_Subject{Subject: subject},
+						}},
+					}}},
+				}},
+			},
+		},
+	}
+	claimMatch := func(iss, sub string) map[string]any {
+		return map[string]any{"claim_match": claimMatchModel{
+			Issuer:          types.StringValue(iss),
+			IssuerPattern:   types.StringNull(),
+			Subject:         types.StringValue(sub),
+			SubjectPattern:  types.StringNull(),
+			Claims:          types.MapNull(types.StringType),
+			ClaimPatterns:   types.MapNull(types.StringType),
+			Audience:        types.StringNull(),
+			AudiencePattern: types.StringNull(),
+		}}
+	}
+	attrs := func(id string, extra map[string]any) map[string]any {
+		m := map[string]any{"parent_id": parentID, "name": "new"}
+		if id != "" {
+			m["id"] = id
+		}
+		for k, v := range extra {
+			m[k] = v
+		}
+		return m
+	}
+
+	tests := map[string]struct {
+		clients     platform.Clients
+		state       map[string]any
+		plan        map[string]any
+		wantWarning bool
+	}{
+		"conflict": {
+			clients:     clients,
+			plan:        attrs("", claimMatch(issuer, subject)),
+			wantWarning: true,
+		},
+		"different subject": {
+			clients: clients,
+			plan:    attrs("", claimMatch(issuer, "repo:example/repo:ref:refs/heads/dev")),
+		},
+		"no client": {
+			plan: attrs("", claimMatch(issuer, subject)),
+		},
+		"itself": {
+			clients: clients,
+			state:   attrs(idA, claimMatch(issuer, "old")),
+			plan:    attrs(idA, claimMatch(issuer, subject)),
+		},
+		"changed to conflict": {
+			clients:     clients,
+			state:       attrs(idB, claimMatch(issuer, "old")),
+			plan:        attrs(idB, claimMatch(issuer, subject)),
+			wantWarning: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := &identityResource{managedResource{prov: &providerData{client: test.clients}}}
+			req := tfresource.ModifyPlanRequest{
+				State: testResourceState(t, r, test.state),
+				Plan:  testResourcePlan(t, r, test.plan),
+			}
+			if test.state == nil {
+				req.State.Raw = tftypes.NewValue(req.State.Schema.Type().TerraformType(context.Background()), nil)
+			}
+			resp := &tfresource.ModifyPlanResponse{Plan: req.Plan}
+			r.ModifyPlan(context.Background(), req, resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("ModifyPlan() = %v", resp.Diagnostics)
+			}
+			if got := resp.Diagnostics.WarningsCount() > 0; got != test.wantWarning {
+				t.Errorf("ModifyPlan() warned = %t, wanted %t: %v", got, test.wantWarning, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestIdentitySummary(t *testing.T) {
+	claimMatch := func(cm *iam.Identity_ClaimMatch) *iam.Identity {
+		return &iam.Identity{Relationship: &iam.Identity_ClaimMatch_{ClaimMatch: cm}}
+	}
+	tests := map[string]struct {
+		id   *iam.Identity
+		want string
+	}{
+		"github actions ref": {
+			id: claimMatch(&iam.Identity_ClaimMatch{
+				Iss: &iam.Identity_ClaimMatch_Issuer{Issuer: "https://token.actions.githubusercontent.com"},
+				Sub: &iam.Identity_ClaimMatch_Subject{Subject: "repo:org/x:ref:refs/heads/main"},
+			}),
+			want: "GitHub Actions for repo org/x on ref main",
+		},
+		"github actions environment": {
+			id: claimMatch(&iam.Identity_ClaimMatch{
+				Iss: &iam.Identity_ClaimMatch_Issuer{Issuer: "https://token.actions.githubusercontent.com"},
+				Sub: &iam.Identity_ClaimMatch_Subject{Subject: "repo:org/x:environment:prod"},
+			}),
+			want: "GitHub Actions for repo org/x in environment prod",
+		},
+		"github actions pull request": {
+			id: claimMatch(&iam.Identity_ClaimMatch{
+				Iss: &iam.Identity_ClaimMatch_Issuer{Issuer: "https://token.actions.githubusercontent.com"},
+				Sub: &iam.Identity_ClaimMatch_Subject{Subject: "repo:org/x:pull_request"},
+			}),
+			want: "GitHub Actions for pull requests to repo org/x",
+		},
+		"github actions pattern": {
+			id: claimMatch(&iam.Identity_ClaimMatch{
+				Iss: &iam.Identity_ClaimMatch_Issuer{Issuer: "https://token.actions.githubusercontent.com"},
+				Sub: &iam.Identity_ClaimMatch_SubjectPattern{SubjectPattern: "repo:org/x:.*"},
+			}),
+			want: `Tokens from issuer https://token.actions.githubusercontent.com with subject matching "repo:org/x:.*"`,
+		},
+		"claim match": {
+			id: claimMatch(&iam.Identity_ClaimMatch{
+				Iss: &iam.Identity_ClaimMatch_Issuer{Issuer: "https://accounts.google.com"},
+				Sub: &iam.Identity_ClaimMatch_Subject{Subject: "1234"},
+			}),
+			want: "Tokens from issuer https://accounts.google.com with subject 1234",
+		},
+		"claim match patterns": {
+			id: claimMatch(&iam.Identity_ClaimMatch{
+				Iss: &iam.Identity_ClaimMatch_IssuerPattern{IssuerPattern: "https://.*"},
+				Sub: &iam.Identity_ClaimMatch_SubjectPattern{SubjectPattern: ".*"},
+			}),
+			want: `Tokens from issuer matching "https://.*" with subject matching ".*"`,
+		},
+		"static": {
+			id: &iam.Identity{Relationship: &iam.Identity_Static{Static: &iam.Identity_StaticKeys{
+				Issuer:     "https://example.com",
+				Subject:    "alice",
+				Expiration: timestamppb.New(time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)),
+			}}},
+			want: "Tokens from issuer https://example.com with subject alice, verified with static keys until 2030-01-02T03:04:05Z",
+		},
+		"aws": {
+			id: &iam.Identity{Relationship: &iam.Identity_AwsIdentity{AwsIdentity: &iam.Identity_AWSIdentity{
+				AwsAccount: "123456789012",
+				AwsArn:     &iam.Identity_AWSIdentity_Arn{Arn: "arn:aws:iam::123456789012:role/ci"},
+				AwsUserId:  &iam.Identity_AWSIdentity_UserIdPattern{UserIdPattern: "AROA.*"},
+			}}},
+			want: `AWS identities in account 123456789012 with ARN arn:aws:iam::123456789012:role/ci and user ID matching "AROA.*"`,
+		},
+		"service principal": {
+			id:   &iam.Identity{Relationship: &iam.Identity_ServicePrincipal{ServicePrincipal: iam.ServicePrincipal_COSIGNED}},
+			want: "The Chainguard COSIGNED service",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := identitySummary(test.id); got != test.want {
+				t.Errorf("identitySummary() = %q, wanted %q", got, test.want)
+			}
+		})
+	}
+}
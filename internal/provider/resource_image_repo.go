@@ -8,12 +8,11 @@ package provider
 import (
 	"context"
 	"fmt"
-	"sync"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/objectvalidator"
-	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -23,11 +22,15 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	common "chainguard.dev/sdk/proto/platform/common/v1"
 	registry "chainguard.dev/sdk/proto/platform/registry/v1"
 	"chainguard.dev/sdk/uidp"
 	"chainguard.dev/sdk/validation"
+	"github.com/chainguard-dev/terraform-provider-chainguard/internal/protoutil"
 	"github.com/chainguard-dev/terraform-provider-chainguard/internal/validators"
 )
 
@@ -56,8 +59,9 @@ type imageRepoResourceModel struct {
 	Readme     types.String `tfsdk:"readme"`
 	SyncConfig types.Object `tfsdk:"sync_config"`
 	// Image tier (e.g. APPLICATION, BASE, etc.)
-	Tier    types.String `tfsdk:"tier"`
-	Aliases types.List   `tfsdk:"aliases"`
+	Tier     types.String   `tfsdk:"tier"`
+	Aliases  types.List     `tfsdk:"aliases"`
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
 }
 
 type syncConfig struct {
@@ -81,9 +85,9 @@ func (r *imageRepoResource) Metadata(_ context.Context, req resource.MetadataReq
 }
 
 // Schema defines the schema for the resource.
-func (r *imageRepoResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *imageRepoResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Image repo (note: delete is purposefully a no-op).",
+		Description: "Image repo (note: delete is purposefully a no-op unless allow_destroy is set on the provider).",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Description:   "The UIDP of this repo.",
@@ -93,6 +97,9 @@ func (r *imageRepoResource) Schema(_ context.Context, _ resource.SchemaRequest,
 			"name": schema.StringAttribute{
 				Description: "The name of this repo.",
 				Required:    true,
+				Validators: []validator.String{
+					validators.OCIName(),
+				},
 			},
 			"parent_id": schema.StringAttribute{
 				Description:   "The group that owns the repo.",
@@ -119,10 +126,10 @@ func (r *imageRepoResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				},
 			},
 			"tier": schema.StringAttribute{
-				Description: "Image tier associated with this repo.",
+				Description: "Image tier associated with this repo. Must be one of: " + protoutil.EnumToQuotedString(registry.CatalogTier_value) + ".",
 				Optional:    true,
 				Validators: []validator.String{
-					validators.ValidateStringFuncs(validTierValue),
+					protoutil.EnumOneOf(registry.CatalogTier_value),
 				},
 			},
 			"aliases": schema.ListAttribute{
@@ -135,6 +142,12 @@ func (r *imageRepoResource) Schema(_ context.Context, _ resource.SchemaRequest,
 			},
 		},
 		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
 			"sync_config": schema.SingleNestedBlock{
 				Description: "Configuration for catalog syncing.",
 				Validators: []validator.Object{
@@ -205,14 +218,6 @@ func validAliasesValue(s string) error {
 	return nil
 }
 
-// validTierValue implements validators.ValidateStringFunc.
-func validTierValue(s string) error {
-	if _, ok := registry.CatalogTier_value[s]; !ok {
-		return fmt.Errorf("tier %q is invalid", s)
-	}
-	return nil
-}
-
 // validReadmeValue implements validators.ValidateStringFunc.
 func validReadmeValue(s string) error {
 	if diff, err := validation.ValidateReadme(s); err != nil {
@@ -226,7 +231,27 @@ func (r *imageRepoResource) ImportState(ctx context.Context, req resource.Import
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
-var mu sync.Mutex
+// repoLock serializes image repo operations. It is a channel rather than a
+// sync.Mutex so that waiting for it counts against the operation's timeout.
+var repoLock = make(chan struct{}, 1)
+
+// lockRepos acquires repoLock, or fails once ctx is done. The returned func
+// releases it.
+func lockRepos(ctx context.Context) (func(), error) {
+	select {
+	case repoLock <- struct{}{}:
+		return func() { <-repoLock }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// repoAdoptAttempts and repoAdoptBackoff bound how long Create waits for a repo
+// created concurrently by another process to become visible before adopting it.
+var (
+	repoAdoptAttempts = 5
+	repoAdoptBackoff  = 250 * time.Millisecond
+)
 
 // Create creates the resource and sets the initial Terraform state.
 func (r *imageRepoResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -238,9 +263,20 @@ func (r *imageRepoResource) Create(ctx context.Context, req resource.CreateReque
 	}
 	tflog.Info(ctx, fmt.Sprintf("create image repo request: name=%s, parent_id=%s", plan.Name, plan.ParentID))
 
+	createTimeout, diags := plan.Timeouts.Create(ctx, 0 /* none */)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withTimeout(ctx, createTimeout)
+	defer cancel()
+
 	// Lock to prevent concurrent creation of the same repo.
-	mu.Lock()
-	defer mu.Unlock()
+	unlock, err := lockRepos(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("timed out waiting for another image repo operation", err.Error())
+		return
+	}
+	defer unlock()
 
 	var sc *registry.SyncConfig
 	if !plan.SyncConfig.IsNull() {
@@ -280,18 +316,29 @@ func (r *imageRepoResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
-	repo, err := r.prov.client.Registry().Registry().CreateRepo(ctx, &registry.CreateRepoRequest{
+	want := &registry.Repo{
+		Name:        plan.Name.ValueString(),
+		Bundles:     bundles,
+		Readme:      plan.Readme.ValueString(),
+		SyncConfig:  sc,
+		CatalogTier: registry.CatalogTier(registry.CatalogTier_value[plan.Tier.ValueString()]),
+		Aliases:     aliases,
+	}
+	repo, err := r.prov.clients().Registry().Registry().CreateRepo(ctx, &registry.CreateRepoRequest{
 		ParentId: plan.ParentID.ValueString(),
-		Repo: &registry.Repo{
-			Name:        plan.Name.ValueString(),
-			Bundles:     bundles,
-			Readme:      plan.Readme.ValueString(),
-			SyncConfig:  sc,
-			CatalogTier: registry.CatalogTier(registry.CatalogTier_value[plan.Tier.ValueString()]),
-			Aliases:     aliases,
-		},
+		Repo:     want,
 	})
-	if err != nil {
+	switch {
+	case status.Code(err) == codes.AlreadyExists:
+		// The mutex only covers this process, so another one (e.g. a parallel CI job)
+		// may have created the repo first. Adopt it rather than failing.
+		tflog.Info(ctx, fmt.Sprintf("image repo %q already exists in %s, adopting it", want.Name, plan.ParentID))
+		repo, err = r.adoptRepo(ctx, plan.ParentID.ValueString(), want)
+		if err != nil {
+			resp.Diagnostics.Append(errorToDiagnostic(err, "failed to adopt existing image repo"))
+			return
+		}
+	case err != nil:
 		resp.Diagnostics.Append(errorToDiagnostic(err, "failed to create image repo"))
 		return
 	}
@@ -299,6 +346,36 @@ func (r *imageRepoResource) Create(ctx context.Context, req resource.CreateReque
 	// Save repo details in the state.
 	plan.ID = types.StringValue(repo.Id)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	markCreated(ctx, resp.Private)
+}
+
+// adoptRepo finds the existing repo named want.Name in parentID, polling with
+// exponential backoff until it's visible, and updates it to match want.
+func (r *imageRepoResource) adoptRepo(ctx context.Context, parentID string, want *registry.Repo) (*registry.Repo, error) {
+	backoff := repoAdoptBackoff
+	for attempt := 1; ; attempt++ {
+		repoList, err := r.prov.clients().Registry().Registry().ListRepos(ctx, &registry.RepoFilter{
+			Uidp: &common.UIDPFilter{ChildrenOf: parentID},
+			Name: want.Name,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if items := repoList.GetItems(); len(items) == 1 {
+			want.Id = items[0].Id
+			return r.prov.clients().Registry().Registry().UpdateRepo(ctx, want)
+		}
+		if attempt == repoAdoptAttempts {
+			return nil, fmt.Errorf("repo %q reported as existing in %s, but was not found after %d attempts", want.Name, parentID, attempt)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+			backoff *= 2
+		}
+	}
 }
 
 // Read refreshes the Terraform state with the latest data.
@@ -311,21 +388,35 @@ func (r *imageRepoResource) Read(ctx context.Context, req resource.ReadRequest,
 	}
 	tflog.Info(ctx, fmt.Sprintf("read image repo request: %s", state.ID))
 
+	readTimeout, diags := state.Timeouts.Read(ctx, 0 /* none */)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withTimeout(ctx, readTimeout)
+	defer cancel()
+
 	// Lock to prevent concurrent update of the same repo.
-	mu.Lock()
-	defer mu.Unlock()
+	unlock, err := lockRepos(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("timed out waiting for another image repo operation", err.Error())
+		return
+	}
+	defer unlock()
 
 	// Query for the repo to update state
 	id := state.ID.ValueString()
-	repoList, err := r.prov.client.Registry().Registry().ListRepos(ctx, &registry.RepoFilter{
-		Id: id,
+	items, err := listVisible(ctx, req.Private, func() ([]*registry.Repo, error) {
+		repoList, err := r.prov.clients().Registry().Registry().ListRepos(ctx, &registry.RepoFilter{
+			Id: id,
+		})
+		return repoList.GetItems(), err
 	})
 	if err != nil {
 		resp.Diagnostics.Append(errorToDiagnostic(err, "failed to list image repos"))
 		return
 	}
 
-	switch c := len(repoList.GetItems()); {
+	switch c := len(items); {
 	case c == 0:
 		// Repo doesn't exist or was deleted outside TF
 		resp.State.RemoveResource(ctx)
@@ -336,7 +427,7 @@ func (r *imageRepoResource) Read(ctx context.Context, req resource.ReadRequest,
 	}
 
 	// Update the state with values returned from the API.
-	repo := repoList.GetItems()[0]
+	repo := items[0]
 	state.ID = types.StringValue(repo.Id)
 	state.ParentID = types.StringValue(uidp.Parent(repo.Id))
 	state.Name = types.StringValue(repo.Name)
@@ -351,7 +442,6 @@ func (r *imageRepoResource) Read(ctx context.Context, req resource.ReadRequest,
 	}
 
 	var sc syncConfig
-	var diags diag.Diagnostics
 	if !state.SyncConfig.IsNull() {
 		if diags = state.SyncConfig.As(ctx, &sc, basetypes.ObjectAsOptions{}); diags.HasError() {
 			resp.Diagnostics.Append(diags...)
@@ -400,9 +490,20 @@ func (r *imageRepoResource) Update(ctx context.Context, req resource.UpdateReque
 	}
 	tflog.Info(ctx, fmt.Sprintf("update image repo request: %s", data.ID))
 
+	updateTimeout, diags := data.Timeouts.Update(ctx, 0 /* none */)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	// Lock to prevent concurrent update of the same repo.
-	mu.Lock()
-	defer mu.Unlock()
+	unlock, err := lockRepos(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("timed out waiting for another image repo operation", err.Error())
+		return
+	}
+	defer unlock()
 
 	var sc *registry.SyncConfig
 	if !data.SyncConfig.IsNull() {
@@ -442,7 +543,7 @@ func (r *imageRepoResource) Update(ctx context.Context, req resource.UpdateReque
 		return
 	}
 
-	repo, err := r.prov.client.Registry().Registry().UpdateRepo(ctx, &registry.Repo{
+	repo, err := r.prov.clients().Registry().Registry().UpdateRepo(ctx, &registry.Repo{
 		Id:          data.ID.ValueString(),
 		Name:        data.Name.ValueString(),
 		Bundles:     bundles,
@@ -471,7 +572,6 @@ func (r *imageRepoResource) Update(ctx context.Context, req resource.UpdateReque
 		data.Tier = types.StringNull()
 	}
 
-	var diags diag.Diagnostics
 	data.Bundles, diags = types.ListValueFrom(ctx, types.StringType, repo.Bundles)
 	if diags.HasError() {
 		resp.Diagnostics.Append(diags...)
@@ -488,12 +588,12 @@ func (r *imageRepoResource) Update(ctx context.Context, req resource.UpdateReque
 }
 
 // Delete is purposefully a no-op so we don't accidentally delete repos with terraform.
-// Instead, delete them with "chainctl img rm".
+// Instead, delete them with "chainctl img rm", or set allow_destroy in the provider config.
 func (r *imageRepoResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	// When not running acceptance tests, add an error to resp so Terraform does not automatically remove this resource from state.
+	// Unless deletes are explicitly allowed, add an error to resp so Terraform does not automatically remove this resource from state.
 	// See https://developer.hashicorp.com/terraform/plugin/framework/resources/delete#caveats for details.
-	if !r.prov.testing {
-		resp.Diagnostics.AddError("not implemented", "Image repos cannot be deleted through Terraform. Use `chainctl img repo rm` to manually delete.")
+	if !r.prov.destroyAllowed() {
+		resp.Diagnostics.AddError("not implemented", "Image repos cannot be deleted through Terraform. Use `chainctl img repo rm` to manually delete. Alternatively, set allow_destroy = true in the provider configuration.")
 		return
 	}
 
@@ -503,14 +603,25 @@ func (r *imageRepoResource) Delete(ctx context.Context, req resource.DeleteReque
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	tflog.Info(ctx, fmt.Sprintf("ACCEPTANCE TEST: delete image repo request: %s", state.ID))
+	tflog.Info(ctx, fmt.Sprintf("delete image repo request: %s", state.ID))
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, 0 /* none */)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withTimeout(ctx, deleteTimeout)
+	defer cancel()
 
 	// Lock to prevent concurrent creation of the same repo.
-	mu.Lock()
-	defer mu.Unlock()
+	unlock, err := lockRepos(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("timed out waiting for another image repo operation", err.Error())
+		return
+	}
+	defer unlock()
 
 	id := state.ID.ValueString()
-	_, err := r.prov.client.Registry().Registry().DeleteRepo(ctx, &registry.DeleteRepoRequest{
+	_, err = r.prov.clients().Registry().Registry().DeleteRepo(ctx, &registry.DeleteRepoRequest{
 		Id: id,
 	})
 	if err != nil {
@@ -6,14 +6,25 @@ SPDX-License-Identifier: Apache-2.0
 package provider
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"testing"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	tfresource "github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	"chainguard.dev/sdk/proto/platform"
+	common "chainguard.dev/sdk/proto/platform/common/v1"
+	registry "chainguard.dev/sdk/proto/platform/registry/v1"
+	registrytest "chainguard.dev/sdk/proto/platform/registry/v1/test"
+	platformtest "chainguard.dev/sdk/proto/platform/test"
 	"chainguard.dev/sdk/uidp"
 )
 
@@ -283,3 +294,218 @@ func TestImageRepo_ConcurrentUpdates(t *testing.T) {
 		})
 	}
 }
+
+func TestImageRepo_CreateAlreadyExists(t *testing.T) {
+	const (
+		parentID  = "0123456789abcdef0123456789abcdef01234567"
+		missingID = "fedcba9876543210fedcba9876543210fedcba98"
+		repoID    = parentID + "/0123456789abcdef"
+	)
+	repoAdoptBackoff = time.Millisecond
+	t.Cleanup(func() { repoAdoptBackoff = 250 * time.Millisecond })
+
+	alreadyExists := status.Error(codes.AlreadyExists, "repo already exists")
+	clients := &platformtest.MockPlatformClients{
+		RegistryClient: registrytest.MockRegistryClients{
+			RegistryClient: registrytest.MockRegistryClient{
+				OnCreateRepos: []registrytest.ReposOnCreate{{
+					Given: &registry.CreateRepoRequest{ParentId: parentID, Repo: &registry.Repo{Name: "repo"}},
+					Error: alreadyExists,
+				}, {
+					Given: &registry.CreateRepoRequest{ParentId: missingID, Repo: &registry.Repo{Name: "repo"}},
+					Error: alreadyExists,
+				}},
+				OnListRepos: []registrytest.ReposOnList{{
+					Given: &registry.RepoFilter{Uidp: &common.UIDPFilter{ChildrenOf: parentID}, Name: "repo"},
+					List:  &registry.RepoList{Items: []*registry.Repo{{Id: repoID, Name: "repo"}}},
+				}, {
+					Given: &registry.RepoFilter{Uidp: &common.UIDPFilter{ChildrenOf: missingID}, Name: "repo"},
+					List:  &registry.RepoList{},
+				}},
+				OnUpdateRepo: []registrytest.RepoOnUpdate{{
+					Given:   &registry.Repo{Id: repoID, Name: "repo"},
+					Updated: &registry.Repo{Id: repoID, Name: "repo"},
+				}},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		parentID string
+		wantID   string
+		wantErr  bool
+	}{
+		"adopts existing repo": {
+			parentID: parentID,
+			wantID:   repoID,
+		},
+		"existing repo never visible": {
+			parentID: missingID,
+			wantErr:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := &imageRepoResource{managedResource{prov: &providerData{client: clients}}}
+			plan := testResourcePlan(t, r, map[string]any{
+				"parent_id": test.parentID,
+				"name":      "repo",
+			})
+			resp := &tfresource.CreateResponse{State: testResourceState(t, r, nil)}
+			r.Create(context.Background(), tfresource.CreateRequest{Plan: plan}, resp)
+
+			if got := resp.Diagnostics.HasError(); got != test.wantErr {
+				t.Fatalf("Create() error = %t, wanted %t: %v", got, test.wantErr, resp.Diagnostics)
+			}
+			if test.wantErr {
+				return
+			}
+			var got imageRepoResourceModel
+			if diags := resp.State.Get(context.Background(), &got); diags.HasError() {
+				t.Fatalf("State.Get() = %v", diags)
+			}
+			if got.ID.ValueString() != test.wantID {
+				t.Errorf("Create() id = %q, wanted %q", got.ID.ValueString(), test.wantID)
+			}
+		})
+	}
+}
+
+// deadlineRegistryClient records the deadline of the context passed to CreateRepo.
+type deadlineRegistryClient struct {
+	registry.RegistryClient
+	deadline *time.Time
+}
+
+func (c deadlineRegistryClient) CreateRepo(ctx context.Context, in *registry.CreateRepoRequest, opts ...grpc.CallOption) (*registry.Repo, error) {
+	*c.deadline, _ = ctx.Deadline()
+	return c.RegistryClient.CreateRepo(ctx, in, opts...)
+}
+
+type deadlineRegistryClients struct {
+	registry.Clients
+	rc registry.RegistryClient
+}
+
+func (c deadlineRegistryClients) Registry() registry.RegistryClient {
+	return c.rc
+}
+
+type deadlineClients struct {
+	platform.Clients
+	reg registry.Clients
+}
+
+func (c deadlineClients) Registry() registry.Clients {
+	return c.reg
+}
+
+func TestImageRepo_CreateTimeout(t *testing.T) {
+	const (
+		parentID = "0123456789abcdef0123456789abcdef01234567"
+		repoID   = parentID + "/0123456789abcdef"
+	)
+	mock := &platformtest.MockPlatformClients{
+		RegistryClient: registrytest.MockRegistryClients{
+			RegistryClient: registrytest.MockRegistryClient{
+				OnCreateRepos: []registrytest.ReposOnCreate{{
+					Given:   &registry.CreateRepoRequest{ParentId: parentID, Repo: &registry.Repo{Name: "repo"}},
+					Created: &registry.Repo{Id: repoID, Name: "repo"},
+				}},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		create string
+		want   time.Duration
+	}{
+		"default": {
+			// No timeout is set, so the call has no deadline.
+		},
+		"configured": {
+			create: "10m",
+			want:   10 * time.Minute,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var deadline time.Time
+			clients := deadlineClients{
+				Clients: mock,
+				reg: deadlineRegistryClients{
+					Clients: mock.Registry(),
+					rc:      deadlineRegistryClient{RegistryClient: mock.Registry().Registry(), deadline: &deadline},
+				},
+			}
+
+			ctx := context.Background()
+			r := &imageRepoResource{managedResource{prov: &providerData{client: clients}}}
+			plan := testResourcePlan(t, r, map[string]any{
+				"parent_id": parentID,
+				"name":      "repo",
+			})
+			if test.create != "" {
+				if diags := plan.SetAttribute(ctx, path.Root("timeouts").AtName("create"), test.create); diags.HasError() {
+					t.Fatalf("Plan.SetAttribute() = %v", diags)
+				}
+			}
+			resp := &tfresource.CreateResponse{State: testResourceState(t, r, nil)}
+			start := time.Now()
+			r.Create(ctx, tfresource.CreateRequest{Plan: plan}, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Create() = %v", resp.Diagnostics)
+			}
+
+			if test.want == 0 {
+				if !deadline.IsZero() {
+					t.Errorf("CreateRepo() deadline in %v, wanted none", deadline.Sub(start))
+				}
+				return
+			}
+			if deadline.IsZero() {
+				t.Fatal("CreateRepo() called without a deadline")
+			}
+			if got := deadline.Sub(start); got < test.want-time.Minute || got > test.want+time.Minute {
+				t.Errorf("CreateRepo() deadline in %v, wanted %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestImageRepo_CreateTimeoutWaitingForLock(t *testing.T) {
+	// Another image repo operation holds the lock for the whole test.
+	unlock, err := lockRepos(context.Background())
+	if err != nil {
+		t.Fatalf("lockRepos() = %v", err)
+	}
+	defer unlock()
+
+	ctx := context.Background()
+	r := &imageRepoResource{managedResource{prov: &providerData{client: &platformtest.MockPlatformClients{}}}}
+	plan := testResourcePlan(t, r, map[string]any{
+		"parent_id": "0123456789abcdef0123456789abcdef01234567",
+		"name":      "repo",
+	})
+	if diags := plan.SetAttribute(ctx, path.Root("timeouts").AtName("create"), "100ms"); diags.HasError() {
+		t.Fatalf("Plan.SetAttribute() = %v", diags)
+	}
+	resp := &tfresource.CreateResponse{State: testResourceState(t, r, nil)}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.Create(ctx, tfresource.CreateRequest{Plan: plan}, resp)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Create() still waiting for the lock after its timeout")
+	}
+
+	if got := resp.Diagnostics.Errors(); len(got) != 1 || got[0].Summary() != "timed out waiting for another image repo operation" {
+		t.Errorf("Create() = %v, wanted a lock timeout", resp.Diagnostics)
+	}
+}
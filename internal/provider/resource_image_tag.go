@@ -22,6 +22,7 @@ import (
 
 	registry "chainguard.dev/sdk/proto/platform/registry/v1"
 	"chainguard.dev/sdk/uidp"
+	"chainguard.dev/sdk/validation"
 	"github.com/chainguard-dev/terraform-provider-chainguard/internal/validators"
 )
 
@@ -30,6 +31,7 @@ var (
 	_ resource.Resource                = &imageTagResource{}
 	_ resource.ResourceWithConfigure   = &imageTagResource{}
 	_ resource.ResourceWithImportState = &imageTagResource{}
+	_ resource.ResourceWithModifyPlan  = &imageTagResource{}
 )
 
 // NewImageTagResource is a helper function to simplify the provider implementation.
@@ -47,6 +49,8 @@ type imageTagResourceModel struct {
 	Name    types.String `tfsdk:"name"`
 	RepoID  types.String `tfsdk:"repo_id"`
 	Bundles types.List   `tfsdk:"bundles"`
+	AliasOf types.String `tfsdk:"alias_of"`
+	Digest  types.String `tfsdk:"digest"`
 }
 
 func (r *imageTagResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
@@ -61,7 +65,7 @@ func (r *imageTagResource) Metadata(_ context.Context, req resource.MetadataRequ
 // Schema defines the schema for the resource.
 func (r *imageTagResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Image tag (note: delete is purposefully a no-op).",
+		Description: "Image tag (note: delete is purposefully a no-op unless allow_destroy is set on the provider).",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Description:   "The UIDP of this tag.",
@@ -71,6 +75,10 @@ func (r *imageTagResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 			"name": schema.StringAttribute{
 				Description: "The name of this tag.",
 				Required:    true,
+				Validators: []validator.String{
+					// Unlike repo names, OCI tags may contain uppercase characters and lead with an underscore.
+					validators.ValidateStringFuncs(validTagName),
+				},
 			},
 			"repo_id": schema.StringAttribute{
 				Description:   "The repo that owns the repo.",
@@ -88,15 +96,109 @@ func (r *imageTagResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 					listvalidator.ValueStringsAre(validators.ValidateStringFuncs(validBundlesValue)),
 				},
 			},
+			"alias_of": schema.StringAttribute{
+				Description: "The UIDP of another tag in the same repo. When set, this tag floats to track the source tag's current digest.",
+				Optional:    true,
+				Validators: []validator.String{
+					validators.UIDP(false /* allowRootSentinel */),
+					validators.UIDPChildOf(path.MatchRoot("repo_id")),
+				},
+			},
+			"digest": schema.StringAttribute{
+				Description:   "The digest of the manifest this tag points to.",
+				Computed:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
 		},
 	}
 }
 
+// validTagName implements validators.ValidateStringFunc.
+func validTagName(s string) error {
+	if err := validation.ValidateTag(s); err != nil {
+		return fmt.Errorf("tag name %q is invalid: %w", s, err)
+	}
+	return nil
+}
+
 // ImportState imports resources by ID into the current Terraform state.
 func (r *imageTagResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
+// ModifyPlan resolves the digest of the alias_of source tag so that a floating
+// tag is updated whenever its source moves.
+func (r *imageTagResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to do on destroy, or before the provider is configured.
+	if req.Plan.Raw.IsNull() || r.prov == nil {
+		return
+	}
+
+	var plan imageTagResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if plan.AliasOf.IsNull() {
+		return
+	}
+	if plan.AliasOf.IsUnknown() {
+		// The source isn't known yet, so neither is the digest it resolves to.
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("digest"), types.StringUnknown())...)
+		return
+	}
+
+	resp.Diagnostics.Append(r.ensureClient(ctx)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	source := plan.AliasOf.ValueString()
+	if !plan.RepoID.IsUnknown() && uidp.Parent(source) != plan.RepoID.ValueString() {
+		resp.Diagnostics.AddAttributeError(path.Root("alias_of"), "invalid alias_of",
+			fmt.Sprintf("source tag %q is not in repo %q", source, plan.RepoID.ValueString()))
+		return
+	}
+	if !plan.ID.IsUnknown() && source == plan.ID.ValueString() {
+		resp.Diagnostics.AddAttributeError(path.Root("alias_of"), "invalid alias_of", "a tag cannot be an alias of itself")
+		return
+	}
+
+	digest, diags := r.sourceDigest(ctx, source)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("digest"), digest)...)
+}
+
+// aliasDigest returns the digest to send for the tag: the digest resolved from
+// alias_of, or empty for a plain tag so that the registry keeps whatever it
+// points to rather than a digest from a possibly stale state.
+func (m imageTagResourceModel) aliasDigest() string {
+	if m.AliasOf.IsNull() {
+		return ""
+	}
+	return m.Digest.ValueString()
+}
+
+// sourceDigest returns the current digest of the tag with the given id.
+func (r *imageTagResource) sourceDigest(ctx context.Context, id string) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	tagList, err := r.prov.clients().Registry().Registry().ListTags(ctx, &registry.TagFilter{
+		Id: id,
+	})
+	if err != nil {
+		diags.Append(errorToDiagnostic(err, "failed to list image tags"))
+		return "", diags
+	}
+	if len(tagList.GetItems()) != 1 {
+		diags.AddAttributeError(path.Root("alias_of"), "invalid alias_of", fmt.Sprintf("source tag %q not found", id))
+		return "", diags
+	}
+	return tagList.GetItems()[0].GetDigest(), diags
+}
+
 // Create creates the resource and sets the initial Terraform state.
 func (r *imageTagResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	// Read the plan data into the resource model.
@@ -112,10 +214,11 @@ func (r *imageTagResource) Create(ctx context.Context, req resource.CreateReques
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	repo, err := r.prov.client.Registry().Registry().CreateTag(ctx, &registry.CreateTagRequest{
+	repo, err := r.prov.clients().Registry().Registry().CreateTag(ctx, &registry.CreateTagRequest{
 		RepoId: plan.RepoID.ValueString(),
 		Tag: &registry.Tag{
 			Name:    plan.Name.ValueString(),
+			Digest:  plan.aliasDigest(),
 			Bundles: bundles,
 		},
 	})
@@ -126,6 +229,7 @@ func (r *imageTagResource) Create(ctx context.Context, req resource.CreateReques
 
 	// Save tag details in the state.
 	plan.ID = types.StringValue(repo.Id)
+	plan.Digest = types.StringValue(repo.Digest)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
@@ -141,7 +245,7 @@ func (r *imageTagResource) Read(ctx context.Context, req resource.ReadRequest, r
 
 	// Query for the tag to update state
 	id := state.ID.ValueString()
-	tagList, err := r.prov.client.Registry().Registry().ListTags(ctx, &registry.TagFilter{
+	tagList, err := r.prov.clients().Registry().Registry().ListTags(ctx, &registry.TagFilter{
 		Id: id,
 	})
 	if err != nil {
@@ -164,6 +268,7 @@ func (r *imageTagResource) Read(ctx context.Context, req resource.ReadRequest, r
 	state.ID = types.StringValue(tag.Id)
 	state.RepoID = types.StringValue(uidp.Parent(tag.Id))
 	state.Name = types.StringValue(tag.Name)
+	state.Digest = types.StringValue(tag.Digest)
 
 	var diags diag.Diagnostics
 	state.Bundles, diags = types.ListValueFrom(ctx, types.StringType, tag.Bundles)
@@ -191,9 +296,10 @@ func (r *imageTagResource) Update(ctx context.Context, req resource.UpdateReques
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	tag, err := r.prov.client.Registry().Registry().UpdateTag(ctx, &registry.Tag{
+	tag, err := r.prov.clients().Registry().Registry().UpdateTag(ctx, &registry.Tag{
 		Id:      data.ID.ValueString(),
 		Name:    data.Name.ValueString(),
+		Digest:  data.aliasDigest(),
 		Bundles: bundles,
 	})
 	if err != nil {
@@ -204,6 +310,7 @@ func (r *imageTagResource) Update(ctx context.Context, req resource.UpdateReques
 	// Update the state with values returned from the API.
 	data.ID = types.StringValue(tag.Id)
 	data.Name = types.StringValue(tag.Name)
+	data.Digest = types.StringValue(tag.Digest)
 
 	var diags diag.Diagnostics
 	data.Bundles, diags = types.ListValueFrom(ctx, types.StringType, tag.Bundles)
@@ -215,12 +322,12 @@ func (r *imageTagResource) Update(ctx context.Context, req resource.UpdateReques
 }
 
 // Delete is purposefully a no-op so tags aren't accidentally deleted with terraform.
-// Instead, delete them with normal OCI calls (e.g. "crane delete").
+// Instead, delete them with normal OCI calls (e.g. "crane delete"), or set allow_destroy in the provider config.
 func (r *imageTagResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	// When not running acceptance tests, add an error to resp so Terraform does not automatically remove this resource from state.
+	// Unless deletes are explicitly allowed, add an error to resp so Terraform does not automatically remove this resource from state.
 	// See https://developer.hashicorp.com/terraform/plugin/framework/resources/delete#caveats for details.
-	if !r.prov.testing {
-		resp.Diagnostics.AddError("not implemented", "Image tags cannot be deleted through Terraform. Use `crane delete` to manually delete.")
+	if !r.prov.destroyAllowed() {
+		resp.Diagnostics.AddError("not implemented", "Image tags cannot be deleted through Terraform. Use `crane delete` to manually delete. Alternatively, set allow_destroy = true in the provider configuration.")
 		return
 	}
 
@@ -230,10 +337,10 @@ func (r *imageTagResource) Delete(ctx context.Context, req resource.DeleteReques
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	tflog.Info(ctx, fmt.Sprintf("ACCEPTANCE TEST: delete image tag request: %s", state.ID))
+	tflog.Info(ctx, fmt.Sprintf("delete image tag request: %s", state.ID))
 
 	id := state.ID.ValueString()
-	_, err := r.prov.client.Registry().Registry().DeleteTag(ctx, &registry.DeleteTagRequest{
+	_, err := r.prov.clients().Registry().Registry().DeleteTag(ctx, &registry.DeleteTagRequest{
 		Id: id,
 	})
 	if err != nil {
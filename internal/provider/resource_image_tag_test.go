@@ -6,10 +6,18 @@ SPDX-License-Identifier: Apache-2.0
 package provider
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"testing"
 
+	registry "chainguard.dev/sdk/proto/platform/registry/v1"
+	registrytest "chainguard.dev/sdk/proto/platform/registry/v1/test"
+	platformtest "chainguard.dev/sdk/proto/platform/test"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	tfresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
@@ -53,9 +61,10 @@ func TestImageTag(t *testing.T) {
 			},
 			// ImportState testing.
 			{
-				ResourceName:      "chainguard_image_tag.tag_example",
-				ImportState:       true,
-				ImportStateVerify: true,
+				ResourceName:            "chainguard_image_tag.tag_example",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"alias_of"},
 			},
 			// Update and Read testing.
 			{
@@ -89,3 +98,222 @@ resource "chainguard_image_tag" "tag_example" {
 `
 	return fmt.Sprintf(tmpl, tag.parentID, tag.name, tag.bundles, tag.name, tag.bundles)
 }
+
+func TestImageTag_AliasOf(t *testing.T) {
+	parentID := os.Getenv("TF_ACC_GROUP_ID")
+	name := acctest.RandString(10)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create an alias of the first source tag.
+			{
+				Config: testImageTagAlias(parentID, name, "first"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(`chainguard_image_tag.alias`, `alias_of`, `chainguard_image_tag.first`, `id`),
+					resource.TestCheckResourceAttrPair(`chainguard_image_tag.alias`, `digest`, `chainguard_image_tag.first`, `digest`),
+				),
+			},
+			// Re-point the alias at the second source tag. Tags created here have
+			// no digest, so TestImageTagModifyPlan_AliasOf covers the alias
+			// following a source whose digest moves.
+			{
+				Config: testImageTagAlias(parentID, name, "second"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(`chainguard_image_tag.alias`, `alias_of`, `chainguard_image_tag.second`, `id`),
+					resource.TestCheckResourceAttrPair(`chainguard_image_tag.alias`, `digest`, `chainguard_image_tag.second`, `digest`),
+				),
+			},
+		},
+	})
+}
+
+func testImageTagAlias(parentID, name, source string) string {
+	const tmpl = `
+resource "chainguard_image_repo" "alias_example" {
+  parent_id = %q
+  name      = %q
+}
+
+resource "chainguard_image_tag" "first" {
+  repo_id = chainguard_image_repo.alias_example.id
+  name    = "first"
+}
+
+resource "chainguard_image_tag" "second" {
+  repo_id = chainguard_image_repo.alias_example.id
+  name    = "second"
+}
+
+resource "chainguard_image_tag" "alias" {
+  repo_id  = chainguard_image_repo.alias_example.id
+  name     = "latest"
+  alias_of = chainguard_image_tag.%s.id
+}
+`
+	return fmt.Sprintf(tmpl, parentID, name, source)
+}
+
+func TestImageTagModifyPlan_AliasOf(t *testing.T) {
+	const (
+		repoID  = "0123456789abcdef0123456789abcdef01234567/0123456789abcdef"
+		aliasID = repoID + "/000000000000000a"
+		first   = repoID + "/000000000000000b"
+		second  = repoID + "/000000000000000c"
+
+		digestA = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+		digestB = "sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+		digestC = "sha256:cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc"
+	)
+	tags := func(firstDigest string) []registrytest.TagsOnList {
+		return []registrytest.TagsOnList{{
+			Given: &registry.TagFilter{Id: first},
+			List:  &registry.TagList{Items: []*registry.Tag{{Id: first, Name: "first", Digest: firstDigest}}},
+		}, {
+			Given: &registry.TagFilter{Id: second},
+			List:  &registry.TagList{Items: []*registry.Tag{{Id: second, Name: "second", Digest: digestC}}},
+		}}
+	}
+
+	tests := map[string]struct {
+		firstDigest string
+		aliasOf     string
+		want        string
+	}{
+		"source unchanged": {
+			firstDigest: digestA,
+			aliasOf:     first,
+			want:        digestA,
+		},
+		"source moved": {
+			// The first tag was moved outside Terraform; alias_of is unchanged.
+			firstDigest: digestB,
+			aliasOf:     first,
+			want:        digestB,
+		},
+		"alias re-pointed": {
+			firstDigest: digestA,
+			aliasOf:     second,
+			want:        digestC,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			clients := &platformtest.MockPlatformClients{
+				RegistryClient: registrytest.MockRegistryClients{
+					RegistryClient: registrytest.MockRegistryClient{
+						OnListTags: tags(test.firstDigest),
+						OnUpdateTag: []registrytest.TagOnUpdate{{
+							Given:   &registry.Tag{Id: aliasID, Name: "latest", Digest: test.want},
+							Updated: &registry.Tag{Id: aliasID, Name: "latest", Digest: test.want},
+						}},
+					},
+				},
+			}
+			r := &imageTagResource{managedResource{prov: &providerData{client: clients}}}
+
+			// The alias was last applied while the first tag was at digestA.
+			state := testResourceState(t, r, map[string]any{
+				"id":       aliasID,
+				"repo_id":  repoID,
+				"name":     "latest",
+				"alias_of": first,
+				"digest":   digestA,
+			})
+			// The prior digest is carried over by UseStateForUnknown.
+			plan := testResourcePlan(t, r, map[string]any{
+				"id":       aliasID,
+				"repo_id":  repoID,
+				"name":     "latest",
+				"alias_of": test.aliasOf,
+				"digest":   digestA,
+			})
+
+			mresp := &tfresource.ModifyPlanResponse{Plan: plan}
+			r.ModifyPlan(ctx, tfresource.ModifyPlanRequest{Plan: plan, State: state}, mresp)
+			if mresp.Diagnostics.HasError() {
+				t.Fatalf("ModifyPlan() = %v", mresp.Diagnostics)
+			}
+			var planned types.String
+			if diags := mresp.Plan.GetAttribute(ctx, path.Root("digest"), &planned); diags.HasError() {
+				t.Fatalf("Plan.GetAttribute() = %v", diags)
+			}
+			if got := planned.ValueString(); got != test.want {
+				t.Fatalf("planned digest = %q, wanted %q", got, test.want)
+			}
+			if test.want == digestA {
+				// Nothing moved, so there is nothing to update.
+				return
+			}
+
+			uresp := &tfresource.UpdateResponse{State: tfsdk.State{Schema: state.Schema, Raw: state.Raw}}
+			r.Update(ctx, tfresource.UpdateRequest{Plan: mresp.Plan, State: state}, uresp)
+			if uresp.Diagnostics.HasError() {
+				t.Fatalf("Update() = %v", uresp.Diagnostics)
+			}
+			var updated imageTagResourceModel
+			if diags := uresp.State.Get(ctx, &updated); diags.HasError() {
+				t.Fatalf("State.Get() = %v", diags)
+			}
+			if got := updated.Digest.ValueString(); got != test.want {
+				t.Errorf("updated digest = %q, wanted %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestImageTagUpdate_PlainTagKeepsDigest(t *testing.T) {
+	const (
+		repoID = "0123456789abcdef0123456789abcdef01234567/0123456789abcdef"
+		tagID  = repoID + "/000000000000000a"
+
+		// The digest last refreshed into state; the registry has since
+		// pushed another image to the tag.
+		stale  = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+		pushed = "sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	)
+	clients := &platformtest.MockPlatformClients{
+		RegistryClient: registrytest.MockRegistryClients{
+			RegistryClient: registrytest.MockRegistryClient{
+				// A plain tag is updated without a digest, so it isn't moved.
+				OnUpdateTag: []registrytest.TagOnUpdate{{
+					Given:   &registry.Tag{Id: tagID, Name: "latest", Bundles: []string{"xx"}},
+					Updated: &registry.Tag{Id: tagID, Name: "latest", Digest: pushed, Bundles: []string{"xx"}},
+				}},
+			},
+		},
+	}
+	ctx := context.Background()
+	r := &imageTagResource{managedResource{prov: &providerData{client: clients}}}
+
+	state := testResourceState(t, r, map[string]any{
+		"id":      tagID,
+		"repo_id": repoID,
+		"name":    "latest",
+		"bundles": []string{"aa"},
+		"digest":  stale,
+	})
+	plan := testResourcePlan(t, r, map[string]any{
+		"id":      tagID,
+		"repo_id": repoID,
+		"name":    "latest",
+		"bundles": []string{"xx"},
+		"digest":  stale,
+	})
+	resp := &tfresource.UpdateResponse{State: state}
+	r.Update(ctx, tfresource.UpdateRequest{Plan: plan, State: state}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Update() = %v", resp.Diagnostics)
+	}
+
+	var updated imageTagResourceModel
+	if diags := resp.State.Get(ctx, &updated); diags.HasError() {
+		t.Fatalf("State.Get() = %v", diags)
+	}
+	if got := updated.Digest.ValueString(); got != pushed {
+		t.Errorf("updated digest = %q, wanted %q", got, pushed)
+	}
+}
@@ -117,7 +117,7 @@ func (r *roleResource) Create(ctx context.Context, req resource.CreateRequest, r
 		return
 	}
 
-	role, err := r.prov.client.IAM().Roles().Create(ctx, &iam.CreateRoleRequest{
+	role, err := r.prov.clients().IAM().Roles().Create(ctx, &iam.CreateRoleRequest{
 		ParentId: plan.ParentID.ValueString(),
 		Role: &iam.Role{
 			Name:         plan.Name.ValueString(),
@@ -147,7 +147,7 @@ func (r *roleResource) Read(ctx context.Context, req resource.ReadRequest, resp
 
 	// Query for the role to update state
 	roleID := state.ID.ValueString()
-	roleList, err := r.prov.client.IAM().Roles().List(ctx, &iam.RoleFilter{
+	roleList, err := r.prov.clients().IAM().Roles().List(ctx, &iam.RoleFilter{
 		Id: roleID,
 	})
 	if err != nil {
@@ -199,7 +199,7 @@ func (r *roleResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		return
 	}
 
-	role, err := r.prov.client.IAM().Roles().Update(ctx, &iam.Role{
+	role, err := r.prov.clients().IAM().Roles().Update(ctx, &iam.Role{
 		Id:           data.ID.ValueString(),
 		Name:         data.Name.ValueString(),
 		Description:  data.Description.ValueString(),
@@ -234,7 +234,7 @@ func (r *roleResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 	tflog.Info(ctx, fmt.Sprintf("delete role request: %s", state.ID))
 
 	id := state.ID.ValueString()
-	_, err := r.prov.client.IAM().Roles().Delete(ctx, &iam.DeleteRoleRequest{
+	_, err := r.prov.clients().IAM().Roles().Delete(ctx, &iam.DeleteRoleRequest{
 		Id: id,
 	})
 	if err != nil {
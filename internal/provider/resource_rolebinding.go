@@ -101,7 +101,7 @@ func (r *rolebindingResource) Create(ctx context.Context, req resource.CreateReq
 	tflog.Info(ctx, fmt.Sprintf("create rolebinding request: group=%s, role=%s, identity=%s", plan.Group, plan.Role, plan.Identity))
 
 	// Create the rolebinding.
-	binding, err := r.prov.client.IAM().RoleBindings().Create(ctx, &iam.CreateRoleBindingRequest{
+	binding, err := r.prov.clients().IAM().RoleBindings().Create(ctx, &iam.CreateRoleBindingRequest{
 		Parent: plan.Group.ValueString(),
 		RoleBinding: &iam.RoleBinding{
 			Identity: plan.Identity.ValueString(),
@@ -130,7 +130,7 @@ func (r *rolebindingResource) Read(ctx context.Context, req resource.ReadRequest
 
 	// Query for the role to update state
 	rbID := state.ID.ValueString()
-	bindingList, err := r.prov.client.IAM().RoleBindings().List(ctx, &iam.RoleBindingFilter{
+	bindingList, err := r.prov.clients().IAM().RoleBindings().List(ctx, &iam.RoleBindingFilter{
 		Id: rbID,
 	})
 	if err != nil {
@@ -145,10 +145,10 @@ func (r *rolebindingResource) Read(ctx context.Context, req resource.ReadRequest
 
 	case c == 1:
 		binding := bindingList.GetItems()[0]
-		state.ID = types.StringValue(binding.Id)
-		state.Group = types.StringValue(binding.Group.Id)
-		state.Identity = types.StringValue(binding.Identity)
-		state.Role = types.StringValue(binding.Role.Id)
+		state.ID = types.StringValue(binding.GetId())
+		state.Group = types.StringValue(binding.GetGroup().GetId())
+		state.Identity = types.StringValue(binding.GetIdentity())
+		state.Role = types.StringValue(binding.GetRole().GetId())
 
 		// Set state
 		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
@@ -169,7 +169,7 @@ func (r *rolebindingResource) Update(ctx context.Context, req resource.UpdateReq
 	}
 	tflog.Info(ctx, fmt.Sprintf("update rolebinding request: id=%s", data.ID))
 
-	binding, err := r.prov.client.IAM().RoleBindings().Update(ctx, &iam.RoleBinding{
+	binding, err := r.prov.clients().IAM().RoleBindings().Update(ctx, &iam.RoleBinding{
 		Id:       data.ID.ValueString(),
 		Identity: data.Identity.ValueString(),
 		Role:     data.Role.ValueString(),
@@ -197,7 +197,7 @@ func (r *rolebindingResource) Delete(ctx context.Context, req resource.DeleteReq
 	tflog.Info(ctx, fmt.Sprintf("delete rolebinding request: id=%s", state.ID))
 
 	id := state.ID.ValueString()
-	_, err := r.prov.client.IAM().RoleBindings().Delete(ctx, &iam.DeleteRoleBindingRequest{
+	_, err := r.prov.clients().IAM().RoleBindings().Delete(ctx, &iam.DeleteRoleBindingRequest{
 		Id: id,
 	})
 	if err != nil {
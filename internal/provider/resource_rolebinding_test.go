@@ -6,13 +6,20 @@ SPDX-License-Identifier: Apache-2.0
 package provider
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"regexp"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	tfresource "github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	iam "chainguard.dev/sdk/proto/platform/iam/v1"
+	iamtest "chainguard.dev/sdk/proto/platform/iam/v1/test"
+	platformtest "chainguard.dev/sdk/proto/platform/test"
 )
 
 func TestAccRolebindingResource(t *testing.T) {
@@ -86,3 +93,57 @@ resource "chainguard_rolebinding" "test" {
 `
 	return fmt.Sprintf(tmpl, groupID, subgroup, roleID)
 }
+
+func TestRolebindingImportState(t *testing.T) {
+	const (
+		group     = "0123456789abcdef0123456789abcdef01234567/0123456789abcdef"
+		bindingID = group + "/000000000000000a"
+		identity  = "0123456789abcdef0123456789abcdef01234567/1111111111111111"
+		role      = "0123456789abcdef0123456789abcdef01234567/2222222222222222"
+	)
+	clients := &platformtest.MockPlatformClients{
+		IAMClient: iamtest.MockIAMClient{
+			RoleBindingsClient: iamtest.MockRoleBindingsClient{
+				OnList: []iamtest.RoleBindingOnList{{
+					Given: &iam.RoleBindingFilter{Id: bindingID},
+					List: &iam.RoleBindingList{Items: []*iam.RoleBindingList_Binding{{
+						Id:       bindingID,
+						Identity: identity,
+						Role:     &iam.Role{Id: role},
+						Group:    &iam.Group{Id: group},
+					}}},
+				}},
+			},
+		},
+	}
+	ctx := context.Background()
+	r := &rolebindingResource{managedResource{prov: &providerData{client: clients}}}
+
+	// Import only sets the id, so Read must reconstruct everything else.
+	iresp := &tfresource.ImportStateResponse{State: testResourceState(t, r, nil)}
+	r.ImportState(ctx, tfresource.ImportStateRequest{ID: bindingID}, iresp)
+	if iresp.Diagnostics.HasError() {
+		t.Fatalf("ImportState() = %v", iresp.Diagnostics)
+	}
+
+	resp := &tfresource.ReadResponse{State: iresp.State}
+	r.Read(ctx, tfresource.ReadRequest{State: iresp.State}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read() = %v", resp.Diagnostics)
+	}
+
+	for attr, want := range map[string]string{
+		"id":       bindingID,
+		"group":    group,
+		"identity": identity,
+		"role":     role,
+	} {
+		var got string
+		if diags := resp.State.GetAttribute(ctx, path.Root(attr), &got); diags.HasError() {
+			t.Fatalf("GetAttribute(%s) = %v", attr, diags)
+		}
+		if got != want {
+			t.Errorf("%s = %q, wanted %q", attr, got, want)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	common "chainguard.dev/sdk/proto/platform/common/v1"
+	iam "chainguard.dev/sdk/proto/platform/iam/v1"
+	"github.com/chainguard-dev/terraform-provider-chainguard/internal/validators"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource              = &rolebindingsResource{}
+	_ resource.ResourceWithConfigure = &rolebindingsResource{}
+)
+
+// NewRolebindingsResource is a helper function to simplify the provider implementation.
+func NewRolebindingsResource() resource.Resource {
+	return &rolebindingsResource{}
+}
+
+// rolebindingsResource is the resource implementation.
+type rolebindingsResource struct {
+	managedResource
+}
+
+type rolebindingsResourceModel struct {
+	Group      types.String      `tfsdk:"group"`
+	Role       types.String      `tfsdk:"role"`
+	Identities []string          `tfsdk:"identities"`
+	Bindings   map[string]string `tfsdk:"bindings"`
+}
+
+func (r *rolebindingsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.configure(ctx, req, resp)
+}
+
+// Metadata returns the resource type name.
+func (r *rolebindingsResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_rolebindings"
+}
+
+// Schema defines the schema for the resource.
+func (r *rolebindingsResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "A set of IAM rolebindings granting one role in a group to many identities in the Chainguard platform.",
+		Attributes: map[string]schema.Attribute{
+			"group": schema.StringAttribute{
+				Description:   "The id of the group the role is granted in.",
+				Required:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+				Validators:    []validator.String{validators.UIDP(false /* allowRootSentinel */)},
+			},
+			"role": schema.StringAttribute{
+				Description:   "The id of the role to grant.",
+				Required:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+				Validators:    []validator.String{validators.UIDP(false /* allowRootSentinel */)},
+			},
+			"identities": schema.SetAttribute{
+				Description: "The ids of the identities to grant the role to. Rolebindings are added and removed individually as this set changes. Rolebindings which fail to be created with the resource are reported as warnings and retried on the next apply.",
+				Required:    true,
+				ElementType: types.StringType,
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(validators.UIDP(false /* allowRootSentinel */)),
+				},
+			},
+			"bindings": schema.MapAttribute{
+				Description: "The id of the rolebinding for each identity, keyed by identity id.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *rolebindingsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Read the plan data into the resource model.
+	var plan rolebindingsResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Info(ctx, fmt.Sprintf("create rolebindings request: group=%s, role=%s, count=%d", plan.Group, plan.Role, len(plan.Identities)))
+
+	// Record every binding that was created, even if others fail, so
+	// nothing is orphaned by a partial failure.
+	state := rolebindingsResourceModel{
+		Group:    plan.Group,
+		Role:     plan.Role,
+		Bindings: make(map[string]string, len(plan.Identities)),
+	}
+	var diags diag.Diagnostics
+	r.reconcile(ctx, &state, plan.Identities, &diags)
+
+	// Failed bindings are warnings, so the resource isn't tainted and
+	// replaced along with the bindings that succeeded. Identities must match
+	// the plan; Read drops those without a binding so the next apply retries
+	// them.
+	resp.Diagnostics.Append(asWarnings(diags)...)
+	state.Identities = plan.Identities
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *rolebindingsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Read the current state into the resource model.
+	var state rolebindingsResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Info(ctx, fmt.Sprintf("read rolebindings request: group=%s, role=%s", state.Group, state.Role))
+
+	bindingList, err := r.prov.clients().IAM().RoleBindings().List(ctx, &iam.RoleBindingFilter{
+		Uidp: &common.UIDPFilter{
+			ChildrenOf: state.Group.ValueString(),
+		},
+	})
+	if err != nil {
+		resp.Diagnostics.Append(errorToDiagnostic(err, "failed to list rolebindings"))
+		return
+	}
+	byID := make(map[string]*iam.RoleBindingList_Binding, len(bindingList.GetItems()))
+	for _, b := range bindingList.GetItems() {
+		byID[b.GetId()] = b
+	}
+
+	for identity, id := range state.Bindings {
+		// Drop bindings deleted, or repointed, outside TF so they are
+		// recreated.
+		if b, ok := byID[id]; !ok || b.GetIdentity() != identity || b.GetRole().GetId() != state.Role.ValueString() {
+			tflog.Info(ctx, fmt.Sprintf("rolebinding %s for identity %s was changed outside terraform", id, identity))
+			delete(state.Bindings, identity)
+		}
+	}
+	state.Identities = sortedKeys(state.Bindings)
+
+	// Set state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *rolebindingsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state rolebindingsResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Info(ctx, fmt.Sprintf("update rolebindings request: group=%s, role=%s", plan.Group, plan.Role))
+
+	// Reconcile starting from the current state so that it reflects exactly
+	// the operations that succeeded.
+	if state.Bindings == nil {
+		state.Bindings = make(map[string]string, len(plan.Identities))
+	}
+	r.reconcile(ctx, &state, plan.Identities, &resp.Diagnostics)
+
+	// Set state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *rolebindingsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Read the current state into the resource model.
+	var state rolebindingsResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Info(ctx, fmt.Sprintf("delete rolebindings request: group=%s, role=%s", state.Group, state.Role))
+
+	r.reconcile(ctx, &state, nil, &resp.Diagnostics)
+
+	// Keep any bindings that failed to delete in state so they are retried.
+	if resp.Diagnostics.HasError() {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	}
+}
+
+// reconcile deletes the bindings in state for identities not in want, and
+// creates bindings for the identities in want which have none, one at a time.
+// Failures are reported to diags individually, and state is updated to
+// reflect exactly the operations which succeeded.
+func (r *rolebindingsResource) reconcile(ctx context.Context, state *rolebindingsResourceModel, want []string, diags *diag.Diagnostics) {
+	wanted := make(map[string]bool, len(want))
+	for _, identity := range want {
+		wanted[identity] = true
+	}
+
+	for _, identity := range sortedKeys(state.Bindings) {
+		if wanted[identity] {
+			continue
+		}
+		id := state.Bindings[identity]
+		_, err := r.prov.clients().IAM().RoleBindings().Delete(ctx, &iam.DeleteRoleBindingRequest{Id: id})
+		if err != nil && status.Code(err) != codes.NotFound {
+			diags.Append(errorToDiagnostic(err, fmt.Sprintf("failed to delete rolebinding %s for identity %s", id, identity)))
+			continue
+		}
+		delete(state.Bindings, identity)
+	}
+
+	for _, identity := range sortedKeys(wanted) {
+		if _, ok := state.Bindings[identity]; ok {
+			continue
+		}
+		binding, err := r.prov.clients().IAM().RoleBindings().Create(ctx, &iam.CreateRoleBindingRequest{
+			Parent: state.Group.ValueString(),
+			RoleBinding: &iam.RoleBinding{
+				Identity: identity,
+				Role:     state.Role.ValueString(),
+			},
+		})
+		if err != nil {
+			diags.Append(errorToDiagnostic(err, fmt.Sprintf("failed to create rolebinding for identity %s", identity)))
+			continue
+		}
+		state.Bindings[identity] = binding.GetId()
+	}
+
+	state.Identities = sortedKeys(state.Bindings)
+}
@@ -0,0 +1,292 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	tfresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+
+	common "chainguard.dev/sdk/proto/platform/common/v1"
+	iam "chainguard.dev/sdk/proto/platform/iam/v1"
+	iamtest "chainguard.dev/sdk/proto/platform/iam/v1/test"
+	platformtest "chainguard.dev/sdk/proto/platform/test"
+)
+
+func TestAccResourceRolebindings(t *testing.T) {
+	group := os.Getenv(EnvAccGroupID)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read
+			{
+				Config: testAccResourceRolebindings(group, "ci-a", "ci-b"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(`chainguard_rolebindings.test`, `identities.#`, "2"),
+					resource.TestCheckResourceAttr(`chainguard_rolebindings.test`, `bindings.%`, "2"),
+					resource.TestCheckTypeSetElemAttrPair(`chainguard_rolebindings.test`, `identities.*`, `chainguard_identities.ci`, `identities.ci-a.id`),
+					resource.TestCheckTypeSetElemAttrPair(`chainguard_rolebindings.test`, `identities.*`, `chainguard_identities.ci`, `identities.ci-b.id`),
+				),
+			},
+			// Add one identity and remove another, in place.
+			{
+				Config: testAccResourceRolebindings(group, "ci-a", "ci-c"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(`chainguard_rolebindings.test`, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(`chainguard_rolebindings.test`, `identities.#`, "2"),
+					resource.TestCheckResourceAttr(`chainguard_rolebindings.test`, `bindings.%`, "2"),
+					resource.TestCheckTypeSetElemAttrPair(`chainguard_rolebindings.test`, `identities.*`, `chainguard_identities.ci`, `identities.ci-a.id`),
+					resource.TestCheckTypeSetElemAttrPair(`chainguard_rolebindings.test`, `identities.*`, `chainguard_identities.ci`, `identities.ci-c.id`),
+				),
+			},
+			// Remove all identities.
+			{
+				Config: testAccResourceRolebindings(group),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(`chainguard_rolebindings.test`, `identities.#`, "0"),
+					resource.TestCheckResourceAttr(`chainguard_rolebindings.test`, `bindings.%`, "0"),
+				),
+			},
+		},
+	})
+}
+
+// testAccResourceRolebindings binds the viewer role to the named identities,
+// out of a fixed set of identities.
+func testAccResourceRolebindings(group string, bound ...string) string {
+	refs := make([]string, 0, len(bound))
+	for _, name := range bound {
+		refs = append(refs, fmt.Sprintf("chainguard_identities.ci.identities[%q].id", name))
+	}
+
+	tmpl := `
+resource "chainguard_identities" "ci" {
+  parent_id  = %q
+  identities = {
+    "ci-a" = { claim_match = { issuer = "https://issuer.example.com", subject = "rolebindings-a" } }
+    "ci-b" = { claim_match = { issuer = "https://issuer.example.com", subject = "rolebindings-b" } }
+    "ci-c" = { claim_match = { issuer = "https://issuer.example.com", subject = "rolebindings-c" } }
+  }
+}
+
+data "chainguard_role" "viewer" {
+  name   = "viewer"
+  parent = "/"
+}
+
+resource "chainguard_rolebindings" "test" {
+  group      = %q
+  role       = data.chainguard_role.viewer.items[0].id
+  identities = [%s]
+}
+`
+	return fmt.Sprintf(tmpl, group, group, strings.Join(refs, ", "))
+}
+
+func TestRolebindingsCreate_PartialFailure(t *testing.T) {
+	const (
+		group = "0123456789abcdef0123456789abcdef01234567/0123456789abcdef"
+		role  = "0123456789abcdef0123456789abcdef01234567/00000000000000ff"
+		idA   = "0123456789abcdef0123456789abcdef01234567/000000000000000a"
+		idB   = "0123456789abcdef0123456789abcdef01234567/000000000000000b"
+	)
+	create := func(identity string) *iam.CreateRoleBindingRequest {
+		return &iam.CreateRoleBindingRequest{Parent: group, RoleBinding: &iam.RoleBinding{Identity: identity, Role: role}}
+	}
+	clients := &platformtest.MockPlatformClients{
+		IAMClient: iamtest.MockIAMClient{
+			RoleBindingsClient: iamtest.MockRoleBindingsClient{
+				OnCreate: []iamtest.RoleBindingOnCreate{{
+					Given:   create(idA),
+					Created: &iam.RoleBinding{Id: group + "/b-a", Identity: idA, Role: role},
+				}, {
+					Given: create(idB),
+					Error: errors.New("boom"),
+				}},
+				OnList: []iamtest.RoleBindingOnList{{
+					Given: &iam.RoleBindingFilter{Uidp: &common.UIDPFilter{ChildrenOf: group}},
+					List: &iam.RoleBindingList{Items: []*iam.RoleBindingList_Binding{
+						{Id: group + "/b-a", Identity: idA, Role: &iam.Role{Id: role}},
+					}},
+				}},
+			},
+		},
+	}
+	ctx := context.Background()
+	r := &rolebindingsResource{managedResource{prov: &providerData{client: clients}}}
+
+	plan := testResourcePlan(t, r, map[string]any{
+		"group":      group,
+		"role":       role,
+		"identities": []string{idA, idB},
+	})
+	cresp := &tfresource.CreateResponse{State: tfsdk.State{Schema: plan.Schema, Raw: plan.Raw}}
+	r.Create(ctx, tfresource.CreateRequest{Plan: plan}, cresp)
+
+	// A binding failing to be created must not taint the others.
+	if cresp.Diagnostics.HasError() {
+		t.Fatalf("Create() = %v, wanted only warnings", cresp.Diagnostics)
+	}
+	if got := cresp.Diagnostics.WarningsCount(); got != 1 {
+		t.Errorf("Create() warnings = %d, wanted 1: %v", got, cresp.Diagnostics)
+	}
+
+	var created rolebindingsResourceModel
+	if diags := cresp.State.Get(ctx, &created); diags.HasError() {
+		t.Fatalf("State.Get() = %v", diags)
+	}
+	if diff := cmp.Diff(map[string]string{idA: group + "/b-a"}, created.Bindings); diff != "" {
+		t.Errorf("bindings (-want +got):\n%s", diff)
+	}
+	// Identities match the plan, or Terraform rejects the result.
+	if diff := cmp.Diff([]string{idA, idB}, created.Identities); diff != "" {
+		t.Errorf("identities (-want +got):\n%s", diff)
+	}
+
+	// Read drops the identity without a binding, so the next plan retries it.
+	rresp := &tfresource.ReadResponse{State: cresp.State}
+	r.Read(ctx, tfresource.ReadRequest{State: cresp.State}, rresp)
+	if rresp.Diagnostics.HasError() {
+		t.Fatalf("Read() = %v", rresp.Diagnostics)
+	}
+	var read rolebindingsResourceModel
+	if diags := rresp.State.Get(ctx, &read); diags.HasError() {
+		t.Fatalf("State.Get() = %v", diags)
+	}
+	if diff := cmp.Diff([]string{idA}, read.Identities); diff != "" {
+		t.Errorf("identities after Read (-want +got):\n%s", diff)
+	}
+}
+
+func TestRolebindingsUpdate_PartialFailure(t *testing.T) {
+	const (
+		group = "0123456789abcdef0123456789abcdef01234567/0123456789abcdef"
+		role  = "0123456789abcdef0123456789abcdef01234567/00000000000000ff"
+		idA   = "0123456789abcdef0123456789abcdef01234567/000000000000000a"
+		idB   = "0123456789abcdef0123456789abcdef01234567/000000000000000b"
+		idC   = "0123456789abcdef0123456789abcdef01234567/000000000000000c"
+		idD   = "0123456789abcdef0123456789abcdef01234567/000000000000000d"
+	)
+	create := func(identity string) *iam.CreateRoleBindingRequest {
+		return &iam.CreateRoleBindingRequest{Parent: group, RoleBinding: &iam.RoleBinding{Identity: identity, Role: role}}
+	}
+	clients := &platformtest.MockPlatformClients{
+		IAMClient: iamtest.MockIAMClient{
+			RoleBindingsClient: iamtest.MockRoleBindingsClient{
+				OnDelete: []iamtest.RoleBindingOnDelete{{
+					Given: &iam.DeleteRoleBindingRequest{Id: group + "/b-a"},
+					Error: errors.New("boom"),
+				}},
+				OnCreate: []iamtest.RoleBindingOnCreate{{
+					Given:   create(idC),
+					Created: &iam.RoleBinding{Id: group + "/b-c", Identity: idC, Role: role},
+				}, {
+					Given: create(idD),
+					Error: errors.New("boom"),
+				}},
+			},
+		},
+	}
+	ctx := context.Background()
+	r := &rolebindingsResource{managedResource{prov: &providerData{client: clients}}}
+
+	state := testResourceState(t, r, map[string]any{
+		"group":      group,
+		"role":       role,
+		"identities": []string{idA, idB},
+		"bindings":   map[string]string{idA: group + "/b-a", idB: group + "/b-b"},
+	})
+	plan := testResourcePlan(t, r, map[string]any{
+		"group":      group,
+		"role":       role,
+		"identities": []string{idB, idC, idD},
+	})
+	resp := &tfresource.UpdateResponse{State: state}
+	r.Update(ctx, tfresource.UpdateRequest{Plan: plan, State: state}, resp)
+
+	// Removing a and adding d fail, and are both reported.
+	if got := resp.Diagnostics.ErrorsCount(); got != 2 {
+		t.Fatalf("Update() errors = %d, wanted 2: %v", got, resp.Diagnostics)
+	}
+
+	var got rolebindingsResourceModel
+	if diags := resp.State.Get(ctx, &got); diags.HasError() {
+		t.Fatalf("State.Get() = %v", diags)
+	}
+	want := map[string]string{idA: group + "/b-a", idB: group + "/b-b", idC: group + "/b-c"}
+	if diff := cmp.Diff(want, got.Bindings); diff != "" {
+		t.Errorf("bindings (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{idA, idB, idC}, got.Identities); diff != "" {
+		t.Errorf("identities (-want +got):\n%s", diff)
+	}
+}
+
+func TestRolebindingsRead(t *testing.T) {
+	const (
+		group = "0123456789abcdef0123456789abcdef01234567/0123456789abcdef"
+		role  = "0123456789abcdef0123456789abcdef01234567/00000000000000ff"
+		other = "0123456789abcdef0123456789abcdef01234567/00000000000000ee"
+		idA   = "0123456789abcdef0123456789abcdef01234567/000000000000000a"
+		idB   = "0123456789abcdef0123456789abcdef01234567/000000000000000b"
+		idC   = "0123456789abcdef0123456789abcdef01234567/000000000000000c"
+	)
+	clients := &platformtest.MockPlatformClients{
+		IAMClient: iamtest.MockIAMClient{
+			RoleBindingsClient: iamtest.MockRoleBindingsClient{
+				OnList: []iamtest.RoleBindingOnList{{
+					Given: &iam.RoleBindingFilter{Uidp: &common.UIDPFilter{ChildrenOf: group}},
+					List: &iam.RoleBindingList{Items: []*iam.RoleBindingList_Binding{
+						{Id: group + "/b-a", Identity: idA, Role: &iam.Role{Id: role}},
+						// b's binding was repointed to another role outside TF.
+						{Id: group + "/b-b", Identity: idB, Role: &iam.Role{Id: other}},
+						// c's binding was deleted outside TF.
+					}},
+				}},
+			},
+		},
+	}
+	ctx := context.Background()
+	r := &rolebindingsResource{managedResource{prov: &providerData{client: clients}}}
+
+	state := testResourceState(t, r, map[string]any{
+		"group":      group,
+		"role":       role,
+		"identities": []string{idA, idB, idC},
+		"bindings":   map[string]string{idA: group + "/b-a", idB: group + "/b-b", idC: group + "/b-c"},
+	})
+	resp := &tfresource.ReadResponse{State: state}
+	r.Read(ctx, tfresource.ReadRequest{State: state}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read() = %v", resp.Diagnostics)
+	}
+
+	var got rolebindingsResourceModel
+	if diags := resp.State.Get(ctx, &got); diags.HasError() {
+		t.Fatalf("State.Get() = %v", diags)
+	}
+	if diff := cmp.Diff(map[string]string{idA: group + "/b-a"}, got.Bindings); diff != "" {
+		t.Errorf("bindings (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{idA}, got.Identities); diff != "" {
+		t.Errorf("identities (-want +got):\n%s", diff)
+	}
+}
@@ -98,7 +98,7 @@ func (r *subscriptionResource) Create(ctx context.Context, req resource.CreateRe
 	}
 	tflog.Info(ctx, fmt.Sprintf("create subscription request: parent_id=%s, sink=%s", plan.ParentID, plan.Sink))
 
-	sub, err := r.prov.client.IAM().Subscriptions().Create(ctx, &events.CreateSubscriptionRequest{
+	sub, err := r.prov.clients().IAM().Subscriptions().Create(ctx, &events.CreateSubscriptionRequest{
 		ParentId: plan.ParentID.ValueString(),
 		Subscription: &events.Subscription{
 			Sink: plan.Sink.ValueString(),
@@ -124,7 +124,7 @@ func (r *subscriptionResource) Read(ctx context.Context, req resource.ReadReques
 	}
 	tflog.Info(ctx, fmt.Sprintf("read subscription request: %s", state.ID))
 
-	subList, err := r.prov.client.IAM().Subscriptions().List(ctx, &events.SubscriptionFilter{
+	subList, err := r.prov.clients().IAM().Subscriptions().List(ctx, &events.SubscriptionFilter{
 		Id: state.ID.ValueString(),
 	})
 	if err != nil {
@@ -168,7 +168,7 @@ func (r *subscriptionResource) Delete(ctx context.Context, req resource.DeleteRe
 	tflog.Info(ctx, fmt.Sprintf("delete subscription request: %s", state.ID))
 
 	id := state.ID.ValueString()
-	_, err := r.prov.client.IAM().Subscriptions().Delete(ctx, &events.DeleteSubscriptionRequest{
+	_, err := r.prov.clients().IAM().Subscriptions().Delete(ctx, &events.DeleteSubscriptionRequest{
 		Id: id,
 	})
 	if err != nil {
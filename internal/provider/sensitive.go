@@ -0,0 +1,53 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// maskSensitive returns a context in which every string held by an attribute
+// of raw marked sensitive in its schema is masked from tflog messages and
+// fields. lookup is the AttributeAtTerraformPath method of the schema of a
+// tfsdk.Config, Plan or State, and raw its value, e.g.
+//
+//	ctx = maskSensitive(ctx, req.Plan.Schema.AttributeAtTerraformPath, req.Plan.Raw)
+func maskSensitive[A interface{ IsSensitive() bool }](ctx context.Context, lookup func(context.Context, *tftypes.AttributePath) (A, error), raw tftypes.Value) context.Context {
+	var secrets []string
+	_ = tftypes.Walk(raw, func(p *tftypes.AttributePath, v tftypes.Value) (bool, error) {
+		// Paths to elements of collections have no attribute of their own.
+		attr, err := lookup(ctx, p)
+		if err != nil || !attr.IsSensitive() {
+			return true, nil
+		}
+		// Everything beneath a sensitive attribute is sensitive.
+		secrets = append(secrets, stringValues(v)...)
+		return false, nil
+	})
+	if len(secrets) == 0 {
+		return ctx
+	}
+	return tflog.MaskLogStrings(ctx, secrets...)
+}
+
+// stringValues returns every known, non-empty string held in v.
+func stringValues(v tftypes.Value) []string {
+	var values []string
+	_ = tftypes.Walk(v, func(_ *tftypes.AttributePath, v tftypes.Value) (bool, error) {
+		if !v.Type().Is(tftypes.String) || !v.IsKnown() || v.IsNull() {
+			return true, nil
+		}
+		var s string
+		if err := v.As(&s); err == nil && s != "" {
+			values = append(values, s)
+		}
+		return true, nil
+	})
+	return values
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestMaskSensitive(t *testing.T) {
+	const (
+		secret = "hunter2-client-secret"
+		issuer = "https://issuer.example.com"
+	)
+	state := testResourceState(t, &identityProviderResource{}, map[string]any{
+		"name": "example",
+		"oidc": oidcResourceModel{
+			Issuer:           types.StringValue(issuer),
+			ClientID:         types.StringValue("client"),
+			ClientSecret:     types.StringValue(secret),
+			AdditionalScopes: types.ListNull(types.StringType),
+		},
+	})
+
+	var out bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &out)
+	ctx = maskSensitive(ctx, state.Schema.AttributeAtTerraformPath, state.Raw)
+
+	tflog.Info(ctx, "client secret is "+secret, map[string]any{
+		"model":  state.Raw.String(),
+		"secret": secret,
+	})
+	tflog.Error(ctx, "failed with model", map[string]any{"model": state.Raw.String()})
+
+	logs := out.String()
+	if strings.Contains(logs, secret) {
+		t.Errorf("logs contain the client secret:\n%s", logs)
+	}
+	// Values which aren't sensitive are still logged.
+	if !strings.Contains(logs, issuer) {
+		t.Errorf("logs do not contain the issuer:\n%s", logs)
+	}
+}
+
+func TestMaskSensitive_Nothing(t *testing.T) {
+	state := testResourceState(t, &identityProviderResource{}, map[string]any{"name": "example"})
+
+	ctx := context.Background()
+	if got := maskSensitive(ctx, state.Schema.AttributeAtTerraformPath, state.Raw); got != ctx {
+		t.Errorf("maskSensitive() returned a new context with nothing to mask")
+	}
+}
@@ -14,6 +14,14 @@ type LoginConfig struct {
 	// Audience is the audience of the Chainguard token.
 	Audience string
 
+	// CacheDir is the directory in which to cache tokens. If empty, the
+	// user cache directory shared with chainctl is used.
+	CacheDir string
+
+	// DisableCache keeps tokens in memory only, instead of caching them
+	// on disk.
+	DisableCache bool
+
 	// Disabled determines if this package should attempt to refresh missing
 	// and expired tokens automatically.
 	Disabled bool
@@ -42,3 +50,12 @@ type LoginConfig struct {
 	// and exchanged for access tokens.
 	UseRefreshTokens bool
 }
+
+// ForAudience returns a copy of cfg for fetching tokens with the given
+// audience. An empty audience keeps the configured one.
+func (cfg LoginConfig) ForAudience(audience string) LoginConfig {
+	if audience != "" {
+		cfg.Audience = audience
+	}
+	return cfg
+}
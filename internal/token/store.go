@@ -0,0 +1,131 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package token
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"chainguard.dev/sdk/auth"
+	sdktoken "chainguard.dev/sdk/auth/token"
+)
+
+// store persists Chainguard tokens by kind and audience.
+type store interface {
+	load(kind sdktoken.Kind, audience string) ([]byte, error)
+	save(tok []byte, kind sdktoken.Kind, audience string) error
+}
+
+// newStore returns the token store selected by cfg: in memory when caching is
+// disabled, under CacheDir when set, and otherwise the user cache directory
+// shared with chainctl.
+func newStore(cfg LoginConfig) store {
+	switch {
+	case cfg.DisableCache:
+		return memory
+	case cfg.CacheDir != "":
+		return dirStore(cfg.CacheDir)
+	default:
+		return sdkStore{}
+	}
+}
+
+// sdkStore stores tokens in the user cache directory, like chainctl.
+type sdkStore struct{}
+
+func (sdkStore) load(kind sdktoken.Kind, audience string) ([]byte, error) {
+	return sdktoken.Load(kind, audience)
+}
+
+func (sdkStore) save(tok []byte, kind sdktoken.Kind, audience string) error {
+	return sdktoken.Save(tok, kind, audience)
+}
+
+// dirStore stores tokens under a custom directory, with the same layout as
+// the user cache directory.
+type dirStore string
+
+func (d dirStore) path(kind sdktoken.Kind, audience string) string {
+	a := strings.ReplaceAll(audience, "/", "-")
+	// Windows does not allow : in directory names.
+	if runtime.GOOS == "windows" {
+		a = strings.ReplaceAll(a, ":", "-")
+	}
+	return filepath.Join(string(d), a, string(kind))
+}
+
+func (d dirStore) load(kind sdktoken.Kind, audience string) ([]byte, error) {
+	b, err := os.ReadFile(d.path(kind, audience))
+	if err != nil {
+		return nil, fmt.Errorf("reading token file: %w", err)
+	}
+	return b, nil
+}
+
+func (d dirStore) save(tok []byte, kind sdktoken.Kind, audience string) error {
+	p := d.path(kind, audience)
+	if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+		return fmt.Errorf("creating token directory: %w", err)
+	}
+	if err := os.WriteFile(p, tok, 0600); err != nil {
+		return fmt.Errorf("writing token file: %w", err)
+	}
+	return nil
+}
+
+// memory holds tokens for the life of the provider process when on-disk
+// caching is disabled. It is guarded by lock.
+var memory = memStore{}
+
+type memStore map[string][]byte
+
+func (m memStore) load(kind sdktoken.Kind, audience string) ([]byte, error) {
+	b, ok := m[string(kind)+"|"+audience]
+	if !ok {
+		return nil, fmt.Errorf("no %s in memory for audience %q", kind, audience)
+	}
+	return b, nil
+}
+
+func (m memStore) save(tok []byte, kind sdktoken.Kind, audience string) error {
+	m[string(kind)+"|"+audience] = tok
+	return nil
+}
+
+// remainingLife returns the amount of time remaining before the access token
+// for the given audience expires, less the given duration. Returns 0 for
+// expired and non-existent tokens.
+func remainingLife(s store, audience string, less time.Duration) time.Duration {
+	tok, err := s.load(sdktoken.KindAccess, audience)
+	if err != nil {
+		return 0
+	}
+	expiry, err := auth.ExtractExpiry(string(tok))
+	if err != nil {
+		return 0
+	}
+	if life := time.Until(expiry.Add(-less)); life > 0 {
+		return life
+	}
+	return 0
+}
+
+// ValidateCacheDir checks that dir exists or can be created, and is writable.
+func ValidateCacheDir(dir string) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("creating token cache directory: %w", err)
+	}
+	f, err := os.CreateTemp(dir, ".write-check-")
+	if err != nil {
+		return fmt.Errorf("token cache directory %q is not writable: %w", dir, err)
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package token
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	sdktoken "chainguard.dev/sdk/auth/token"
+)
+
+// testJWT returns an unsigned JWT which expires at exp.
+func testJWT(exp time.Time) []byte {
+	enc := base64.RawURLEncoding
+	header := enc.EncodeToString([]byte(`{"alg":"none"}`))
+	claims := enc.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, exp.Unix())))
+	return []byte(header + "." + claims + ".")
+}
+
+func TestNewStore(t *testing.T) {
+	dir := t.TempDir()
+	tests := map[string]struct {
+		cfg  LoginConfig
+		want store
+	}{
+		"default":       {cfg: LoginConfig{}, want: sdkStore{}},
+		"cache dir":     {cfg: LoginConfig{CacheDir: dir}, want: dirStore(dir)},
+		"disable cache": {cfg: LoginConfig{CacheDir: dir, DisableCache: true}, want: memory},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := newStore(test.cfg)
+			if fmt.Sprintf("%T", got) != fmt.Sprintf("%T", test.want) {
+				t.Fatalf("newStore() = %T, wanted %T", got, test.want)
+			}
+			if d, ok := got.(dirStore); ok && d != test.want {
+				t.Errorf("newStore() = %q, wanted %q", d, test.want)
+			}
+		})
+	}
+}
+
+func TestMemStore(t *testing.T) {
+	const audience = "https://console-api.example.dev"
+	s := memStore{}
+
+	if _, err := s.load(sdktoken.KindAccess, audience); err == nil {
+		t.Fatal("load() of a missing token succeeded")
+	}
+	if got := remainingLife(s, audience, time.Minute); got != 0 {
+		t.Errorf("remainingLife() of a missing token = %v, wanted 0", got)
+	}
+
+	access, refresh := testJWT(time.Now().Add(time.Hour)), []byte("refresh")
+	if err := saveTokens(s, string(access), string(refresh), audience); err != nil {
+		t.Fatalf("saveTokens() = %v", err)
+	}
+	if got, err := s.load(sdktoken.KindAccess, audience); err != nil || string(got) != string(access) {
+		t.Errorf("load(access) = %q, %v; wanted %q", got, err, access)
+	}
+	if got, err := s.load(sdktoken.KindRefresh, audience); err != nil || string(got) != string(refresh) {
+		t.Errorf("load(refresh) = %q, %v; wanted %q", got, err, refresh)
+	}
+	if _, err := s.load(sdktoken.KindAccess, "https://other.example.dev"); err == nil {
+		t.Error("load() for another audience succeeded")
+	}
+	if got := remainingLife(s, audience, time.Minute); got < 58*time.Minute || got > time.Hour {
+		t.Errorf("remainingLife() = %v, wanted about 59m", got)
+	}
+
+	// Expired tokens have no remaining life.
+	if err := s.save(testJWT(time.Now().Add(-time.Minute)), sdktoken.KindAccess, audience); err != nil {
+		t.Fatalf("save() = %v", err)
+	}
+	if got := remainingLife(s, audience, 0); got != 0 {
+		t.Errorf("remainingLife() of an expired token = %v, wanted 0", got)
+	}
+}
+
+func TestDisableCache_NoFiles(t *testing.T) {
+	// Point the user cache directory somewhere empty, so any on-disk write
+	// would be visible.
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(home, "cache"))
+
+	const audience = "https://console-api.example.dev"
+	s := newStore(LoginConfig{CacheDir: filepath.Join(home, "tokens"), DisableCache: true})
+	t.Cleanup(func() {
+		delete(memory, string(sdktoken.KindAccess)+"|"+audience)
+	})
+	if err := s.save(testJWT(time.Now().Add(time.Hour)), sdktoken.KindAccess, audience); err != nil {
+		t.Fatalf("save() = %v", err)
+	}
+	if got := remainingLife(s, audience, 0); got <= 0 {
+		t.Errorf("remainingLife() = %v, wanted > 0", got)
+	}
+
+	entries, err := os.ReadDir(home)
+	if err != nil {
+		t.Fatalf("ReadDir() = %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("tokens were written to disk: %v", entries)
+	}
+}
+
+func TestDirStore(t *testing.T) {
+	const audience = "https://console-api.example.dev"
+	dir := t.TempDir()
+	s := dirStore(dir)
+
+	if err := s.save([]byte("token"), sdktoken.KindAccess, audience); err != nil {
+		t.Fatalf("save() = %v", err)
+	}
+	got, err := s.load(sdktoken.KindAccess, audience)
+	if err != nil || string(got) != "token" {
+		t.Errorf("load() = %q, %v; wanted %q", got, err, "token")
+	}
+	fi, err := os.Stat(filepath.Join(dir, "https:--console-api.example.dev", string(sdktoken.KindAccess)))
+	if err != nil {
+		t.Fatalf("Stat() = %v", err)
+	}
+	if got := fi.Mode().Perm(); got != 0600 {
+		t.Errorf("token file mode = %v, wanted %v", got, os.FileMode(0600))
+	}
+}
+
+func TestValidateCacheDir(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		dir     string
+		wantErr bool
+	}{
+		"existing": {dir: t.TempDir()},
+		"created":  {dir: filepath.Join(dir, "a", "b")},
+		"file":     {dir: file, wantErr: true},
+		"in file":  {dir: filepath.Join(file, "sub"), wantErr: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateCacheDir(test.dir)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("ValidateCacheDir() = %v, wanted error: %v", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			entries, err := os.ReadDir(test.dir)
+			if err != nil {
+				t.Fatalf("ReadDir() = %v", err)
+			}
+			if len(entries) != 0 {
+				t.Errorf("ValidateCacheDir() left files behind: %v", entries)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ package token
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"sync"
@@ -31,19 +32,31 @@ const (
 	tokenLifeBuffer = time.Minute
 )
 
+// lock guards the token stores.
 var lock sync.RWMutex
 
+// exchange is overridden in tests.
+var exchange = exchangeToken
+
+// ErrNoIssuer is returned when a token must be obtained, but no issuer is
+// configured to obtain it from.
+var ErrNoIssuer = errors.New("no issuer configured to log in with")
+
 // Get retrieves a Chainguard token, refreshing it if expired/non-existent or forceRefresh == true.
 // If automatic authentication is disabled, returns an unauthenticated error.
+// Tokens are cached per audience, so callers needing tokens for several
+// endpoints can share one LoginConfig by way of ForAudience.
 func Get(ctx context.Context, cfg LoginConfig, forceRefresh bool) ([]byte, error) {
+	s := newStore(cfg)
+
 	// Get the remaining life of the current token.
 	lock.RLock()
-	life := sdktoken.RemainingLife(sdktoken.KindAccess, cfg.Audience, tokenLifeBuffer)
+	life := remainingLife(s, cfg.Audience, tokenLifeBuffer)
 	lock.RUnlock()
 
 	// If token is expired or not found, or we're forcing a refresh, login and save a new one.
 	if life <= 0 || forceRefresh {
-		err := refreshChainguardToken(ctx, cfg, life)
+		err := refreshChainguardToken(ctx, s, cfg, life)
 		if err != nil {
 			return nil, err
 		}
@@ -51,12 +64,12 @@ func Get(ctx context.Context, cfg LoginConfig, forceRefresh bool) ([]byte, error
 
 	lock.RLock()
 	defer lock.RUnlock()
-	return sdktoken.Load(sdktoken.KindAccess, cfg.Audience)
+	return s.load(sdktoken.KindAccess, cfg.Audience)
 }
 
 // refreshChainguardToken attempts to get a new Chainguard token either through user browser flow,
 // or by exchanging a given OIDC token, unless auto-login is disabled.
-func refreshChainguardToken(ctx context.Context, cfg LoginConfig, life time.Duration) error {
+func refreshChainguardToken(ctx context.Context, s store, cfg LoginConfig, life time.Duration) error {
 	// Bail if auto-login is disabled.
 	if cfg.Disabled {
 		tflog.Info(ctx, "automatic authentication disabled")
@@ -68,10 +81,15 @@ func refreshChainguardToken(ctx context.Context, cfg LoginConfig, life time.Dura
 	defer lock.Unlock()
 
 	// Check that the token wasn't refreshed by another thread
-	if sdktoken.RemainingLife(sdktoken.KindAccess, cfg.Audience, tokenLifeBuffer) > life {
+	if remainingLife(s, cfg.Audience, tokenLifeBuffer) > life {
 		return nil
 	}
 
+	// Every way of getting a new token goes through the issuer.
+	if cfg.Issuer == "" {
+		return ErrNoIssuer
+	}
+
 	tflog.Info(ctx, "refreshing Chainguard token", map[string]interface{}{
 		"UseRefreshTokens": cfg.UseRefreshTokens,
 	})
@@ -82,16 +100,16 @@ func refreshChainguardToken(ctx context.Context, cfg LoginConfig, life time.Dura
 
 	// If configured to use refresh tokens, attempt to exchange it for a new access token.
 	if cfg.UseRefreshTokens {
-		accessToken, refreshToken, err = exchangeRefreshToken(ctx, cfg)
+		accessToken, refreshToken, err = exchangeRefreshToken(ctx, s, cfg)
 		if err == nil && accessToken != "" && refreshToken != "" {
-			return saveTokens(accessToken, refreshToken, cfg.Audience)
+			return saveTokens(s, accessToken, refreshToken, cfg.Audience)
 		}
 		// If refresh token exchange failed, fall through to login flow
 		tflog.Warn(ctx, fmt.Sprintf("failed to exchange refresh token: %s", err.Error()))
 	}
 
 	if cfg.IdentityToken != "" {
-		accessToken, err = exchangeToken(ctx, cfg.IdentityToken, cfg)
+		accessToken, err = exchange(ctx, cfg.IdentityToken, cfg)
 	} else {
 		accessToken, refreshToken, err = getChainguardToken(ctx, cfg)
 	}
@@ -99,15 +117,15 @@ func refreshChainguardToken(ctx context.Context, cfg LoginConfig, life time.Dura
 		return fmt.Errorf("failed to get Chainguard token: %w", err)
 	}
 
-	return saveTokens(accessToken, refreshToken, cfg.Audience)
+	return saveTokens(s, accessToken, refreshToken, cfg.Audience)
 }
 
-func saveTokens(accessToken, refreshToken, audience string) error {
-	if err := sdktoken.Save([]byte(accessToken), sdktoken.KindAccess, audience); err != nil {
+func saveTokens(s store, accessToken, refreshToken, audience string) error {
+	if err := s.save([]byte(accessToken), sdktoken.KindAccess, audience); err != nil {
 		return fmt.Errorf("failed to save Chainguard token: %w", err)
 	}
 	if refreshToken != "" {
-		if err := sdktoken.Save([]byte(refreshToken), sdktoken.KindRefresh, audience); err != nil {
+		if err := s.save([]byte(refreshToken), sdktoken.KindRefresh, audience); err != nil {
 			return fmt.Errorf("failed to save refresh token: %w", err)
 		}
 	}
@@ -136,9 +154,9 @@ func getChainguardToken(ctx context.Context, cfg LoginConfig) (accessToken strin
 	return login.Login(loginCtx, opts...)
 }
 
-func exchangeRefreshToken(ctx context.Context, cfg LoginConfig) (cgToken string, refreshToken string, err error) {
+func exchangeRefreshToken(ctx context.Context, s store, cfg LoginConfig) (cgToken string, refreshToken string, err error) {
 	tflog.Info(ctx, "exchanging refresh token for access token")
-	refreshTokenBytes, err := sdktoken.Load(sdktoken.KindRefresh, cfg.Audience)
+	refreshTokenBytes, err := s.load(sdktoken.KindRefresh, cfg.Audience)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to load refresh token: %w", err)
 	}
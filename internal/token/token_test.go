@@ -0,0 +1,116 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package token
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sdktoken "chainguard.dev/sdk/auth/token"
+)
+
+func TestGet_Audiences(t *testing.T) {
+	audiences := []string{"https://console-api.example.dev", "https://apk.example.dev"}
+
+	exchanged := map[string]int{}
+	exchange = func(_ context.Context, _ string, cfg LoginConfig) (string, error) {
+		exchanged[cfg.Audience]++
+		// Make tokens for each audience distinct.
+		return string(testJWT(time.Now().Add(time.Hour + time.Duration(len(exchanged))*time.Second))), nil
+	}
+	t.Cleanup(func() {
+		exchange = exchangeToken
+		for _, aud := range audiences {
+			delete(memory, string(sdktoken.KindAccess)+"|"+aud)
+		}
+	})
+
+	cfg := LoginConfig{
+		Issuer:        "https://issuer.example.dev",
+		Audience:      audiences[0],
+		DisableCache:  true,
+		IdentityToken: "identity-token",
+	}
+	ctx := context.Background()
+	got := map[string]string{}
+	for _, aud := range audiences {
+		tok, err := Get(ctx, cfg.ForAudience(aud), false /* forceRefresh */)
+		if err != nil {
+			t.Fatalf("Get(%q) = %v", aud, err)
+		}
+		got[aud] = string(tok)
+	}
+	if got[audiences[0]] == got[audiences[1]] {
+		t.Errorf("Get() returned the same token for %v", audiences)
+	}
+
+	// Tokens are cached per audience, so fetching them again does not evict
+	// or re-exchange either one.
+	for _, aud := range audiences {
+		tok, err := Get(ctx, cfg.ForAudience(aud), false /* forceRefresh */)
+		if err != nil {
+			t.Fatalf("Get(%q) = %v", aud, err)
+		}
+		if string(tok) != got[aud] {
+			t.Errorf("Get(%q) = %q, wanted the cached %q", aud, tok, got[aud])
+		}
+		if n := exchanged[aud]; n != 1 {
+			t.Errorf("token for %q exchanged %d times, wanted 1", aud, n)
+		}
+	}
+}
+
+func TestGet_NoIssuer(t *testing.T) {
+	const audience = "https://api.chainguard.internal"
+	exchange = func(context.Context, string, LoginConfig) (string, error) {
+		t.Fatal("exchange called without an issuer")
+		return "", nil
+	}
+	t.Cleanup(func() {
+		exchange = exchangeToken
+		delete(memory, string(sdktoken.KindAccess)+"|"+audience)
+	})
+
+	cfg := LoginConfig{
+		Audience:      audience,
+		DisableCache:  true,
+		IdentityToken: "identity-token",
+	}
+	ctx := context.Background()
+	if _, err := Get(ctx, cfg, false /* forceRefresh */); !errors.Is(err, ErrNoIssuer) {
+		t.Errorf("Get() = %v, wanted %v", err, ErrNoIssuer)
+	}
+
+	// A cached token needs no issuer.
+	want := testJWT(time.Now().Add(time.Hour))
+	if err := newStore(cfg).save(want, sdktoken.KindAccess, audience); err != nil {
+		t.Fatalf("save() = %v", err)
+	}
+	got, err := Get(ctx, cfg, false /* forceRefresh */)
+	if err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("Get() = %q, wanted the cached %q", got, want)
+	}
+}
+
+func TestLoginConfig_ForAudience(t *testing.T) {
+	cfg := LoginConfig{Audience: "https://console-api.example.dev", Issuer: "https://issuer.example.dev"}
+
+	if got := cfg.ForAudience("").Audience; got != cfg.Audience {
+		t.Errorf("ForAudience(\"\").Audience = %q, wanted %q", got, cfg.Audience)
+	}
+	other := cfg.ForAudience("https://apk.example.dev")
+	if other.Audience != "https://apk.example.dev" || other.Issuer != cfg.Issuer {
+		t.Errorf("ForAudience() = %+v, wanted audience %q and issuer %q", other, "https://apk.example.dev", cfg.Issuer)
+	}
+	if cfg.Audience != "https://console-api.example.dev" {
+		t.Errorf("ForAudience() modified the receiver: %+v", cfg)
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package validators
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ validator.Map = &exactClaims{}
+	_ validator.Map = &claimPatterns{}
+)
+
+// regexpMeta are the characters that suggest a value was written as a
+// regular expression. "." is deliberately excluded: it is common in literal
+// claim values like hostnames and email addresses.
+const regexpMeta = `\^$*+?()[]{}|`
+
+// hasRegexpMeta reports whether s contains regular expression metacharacters.
+func hasRegexpMeta(s string) bool {
+	return strings.ContainsAny(s, regexpMeta)
+}
+
+// ExactClaims warns when a value of an exact-match claims map looks like a
+// regular expression, in case the user meant to use claim patterns.
+func ExactClaims() validator.Map {
+	return exactClaims{}
+}
+
+type exactClaims struct{}
+
+func (v exactClaims) Description(_ context.Context) string {
+	return "Warn when an exact claim value contains regular expression metacharacters."
+}
+
+func (v exactClaims) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v exactClaims) ValidateMap(_ context.Context, req validator.MapRequest, resp *validator.MapResponse) {
+	// Attributes may be optional, and thus null, which should not fail validation.
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	for k, e := range req.ConfigValue.Elements() {
+		s, ok := e.(types.String)
+		if !ok || s.IsNull() || s.IsUnknown() {
+			continue
+		}
+		if hasRegexpMeta(s.ValueString()) {
+			resp.Diagnostics.AddAttributeWarning(req.Path.AtMapKey(k),
+				fmt.Sprintf("claim %q looks like a regular expression", k),
+				fmt.Sprintf("The value %q contains regular expression metacharacters but is matched exactly. Use claim_patterns if a pattern match was intended.", s.ValueString()))
+		}
+	}
+}
+
+// ClaimPatterns warns when a value of a claim patterns map contains no
+// regular expression metacharacters, in case the user meant to use exact claims.
+func ClaimPatterns() validator.Map {
+	return claimPatterns{}
+}
+
+type claimPatterns struct{}
+
+func (v claimPatterns) Description(_ context.Context) string {
+	return "Warn when a claim pattern contains no regular expression metacharacters."
+}
+
+func (v claimPatterns) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v claimPatterns) ValidateMap(_ context.Context, req validator.MapRequest, resp *validator.MapResponse) {
+	// Attributes may be optional, and thus null, which should not fail validation.
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	for k, e := range req.ConfigValue.Elements() {
+		s, ok := e.(types.String)
+		if !ok || s.IsNull() || s.IsUnknown() {
+			continue
+		}
+		if !hasRegexpMeta(s.ValueString()) {
+			resp.Diagnostics.AddAttributeWarning(req.Path.AtMapKey(k),
+				fmt.Sprintf("claim pattern %q has no regular expression metacharacters", k),
+				fmt.Sprintf("The pattern %q appears to be a literal value. Use claims if an exact match was intended.", s.ValueString()))
+		}
+	}
+}
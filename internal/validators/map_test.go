@@ -0,0 +1,118 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package validators
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestExactClaimsValidateMap(t *testing.T) {
+	tests := map[string]struct {
+		input    map[string]attr.Value
+		wantWarn int
+	}{
+		"literal values": {
+			input: map[string]attr.Value{
+				"email": types.StringValue("octo@chainguard.dev"),
+				"ref":   types.StringValue("refs/heads/main"),
+			},
+		},
+		"pattern value": {
+			input: map[string]attr.Value{
+				"ref": types.StringValue("refs/heads/.*"),
+			},
+			wantWarn: 1,
+		},
+		"anchored value": {
+			input: map[string]attr.Value{
+				"email": types.StringValue("octo@chainguard.dev"),
+				"sub":   types.StringValue("^repo:chainguard-dev/.+$"),
+			},
+			wantWarn: 1,
+		},
+		"unknown value": {
+			input: map[string]attr.Value{
+				"ref": types.StringUnknown(),
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := validator.MapRequest{
+				Path:        path.Root("claims"),
+				ConfigValue: types.MapValueMust(types.StringType, test.input),
+			}
+			resp := &validator.MapResponse{}
+
+			ExactClaims().ValidateMap(context.Background(), req, resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("ExactClaims.ValidateMap() unexpected error: %v", resp.Diagnostics)
+			}
+			if got := resp.Diagnostics.WarningsCount(); got != test.wantWarn {
+				t.Fatalf("ExactClaims.ValidateMap() mismatch, want=%d got=%d warnings", test.wantWarn, got)
+			}
+		})
+	}
+}
+
+func TestClaimPatternsValidateMap(t *testing.T) {
+	tests := map[string]struct {
+		input    map[string]attr.Value
+		wantWarn int
+	}{
+		"pattern values": {
+			input: map[string]attr.Value{
+				"ref": types.StringValue("refs/heads/.*"),
+				"sub": types.StringValue("^repo:chainguard-dev/(foo|bar)$"),
+			},
+		},
+		"literal value": {
+			input: map[string]attr.Value{
+				"ref": types.StringValue("refs/heads/main"),
+			},
+			wantWarn: 1,
+		},
+		"dotted literal value": {
+			input: map[string]attr.Value{
+				"email": types.StringValue("octo@chainguard.dev"),
+				"ref":   types.StringValue("refs/tags/v[0-9]+"),
+			},
+			wantWarn: 1,
+		},
+		"unknown value": {
+			input: map[string]attr.Value{
+				"ref": types.StringUnknown(),
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := validator.MapRequest{
+				Path:        path.Root("claim_patterns"),
+				ConfigValue: types.MapValueMust(types.StringType, test.input),
+			}
+			resp := &validator.MapResponse{}
+
+			ClaimPatterns().ValidateMap(context.Background(), req, resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("ClaimPatterns.ValidateMap() unexpected error: %v", resp.Diagnostics)
+			}
+			if got := resp.Diagnostics.WarningsCount(); got != test.wantWarn {
+				t.Fatalf("ClaimPatterns.ValidateMap() mismatch, want=%d got=%d warnings", test.wantWarn, got)
+			}
+		})
+	}
+}
@@ -7,11 +7,15 @@ package validators
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"regexp"
 	"strings"
+	"time"
 
+	"github.com/go-jose/go-jose/v4"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
@@ -20,16 +24,56 @@ import (
 	"chainguard.dev/sdk/validation"
 )
 
+// timeNow is overridden in tests.
+var timeNow = time.Now
+
 var (
+	_ validator.String = &annotationKey{}
 	_ validator.String = &capability{}
+	_ validator.String = &futureRFC3339{}
 	_ validator.String = &ifParentDefined{}
 	_ validator.String = &isURL{}
+	_ validator.String = &jwks{}
 	_ validator.String = &name{}
+	_ validator.String = &ociName{}
 	_ validator.String = &uidpVal{}
+	_ validator.String = &uidpChildOf{}
 	_ validator.String = &validateStringFuncs{}
 	_ validator.String = &validRegExp{}
 )
 
+// Reverse domain notation: at least two dot-separated DNS labels.
+var annotationKeyPattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9-]*[A-Za-z0-9])?(\.[A-Za-z0-9]([A-Za-z0-9-]*[A-Za-z0-9])?)+$`)
+
+// AnnotationKey validates the string value is an OCI annotation key in
+// reverse domain notation (e.g. org.opencontainers.image.revision).
+func AnnotationKey() validator.String {
+	return annotationKey{}
+}
+
+type annotationKey struct{}
+
+func (v annotationKey) Description(_ context.Context) string {
+	return "Check that the given string is an annotation key in reverse domain notation."
+}
+
+func (v annotationKey) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v annotationKey) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	// Attributes may be optional, and thus null, which should not fail validation.
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	key := req.ConfigValue.ValueString()
+	if !annotationKeyPattern.MatchString(key) {
+		resp.Diagnostics.AddError("failed annotation key validation",
+			fmt.Sprintf("%q is not in reverse domain notation (e.g. org.opencontainers.image.revision)", key))
+	}
+}
+
 // Capability validates the string value is a valid role capability.
 func Capability() validator.String {
 	return capability{}
@@ -62,6 +106,58 @@ func (v capability) ValidateString(_ context.Context, req validator.StringReques
 	}
 }
 
+// FutureRFC3339 validates the string value is an RFC3339 timestamp at least minLead in the future.
+// If requireUTC is true, the timestamp must also be expressed in UTC (e.g. 2006-01-02T15:04:05Z).
+func FutureRFC3339(minLead time.Duration, requireUTC bool) validator.String {
+	return futureRFC3339{minLead: minLead, requireUTC: requireUTC}
+}
+
+type futureRFC3339 struct {
+	minLead    time.Duration
+	requireUTC bool
+}
+
+func (v futureRFC3339) Description(_ context.Context) string {
+	d := fmt.Sprintf("Check that the given string is an RFC3339 timestamp at least %s in the future", v.minLead)
+	if v.requireUTC {
+		d += ", expressed in UTC"
+	}
+	return d + "."
+}
+
+func (v futureRFC3339) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v futureRFC3339) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	// Attributes may be optional, and thus null, which should not fail validation.
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	raw := req.ConfigValue.ValueString()
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		resp.Diagnostics.AddError("failed RFC3339 validation", fmt.Sprintf("failed to parse %s: %s", raw, err.Error()))
+		return
+	}
+
+	if _, offset := t.Zone(); v.requireUTC && offset != 0 {
+		resp.Diagnostics.AddError("failed RFC3339 validation",
+			fmt.Sprintf("%q must be expressed in UTC (e.g. %s)", raw, t.UTC().Format(time.RFC3339)))
+	}
+
+	if earliest := timeNow().Add(v.minLead); t.Before(earliest) {
+		switch {
+		case v.minLead == 0:
+			resp.Diagnostics.AddError("failed RFC3339 validation", fmt.Sprintf("%q is in the past", raw))
+		default:
+			resp.Diagnostics.AddError("failed RFC3339 validation",
+				fmt.Sprintf("%q must be at least %s in the future", raw, v.minLead))
+		}
+	}
+}
+
 // IfParentDefined executes the given set of validators only if the parent of the attribute this
 // validator is defined for is itself defined.
 // This is useful for validating attributes within a block that is mutually exclusive with other blocks.
@@ -141,6 +237,46 @@ func (v isURL) ValidateString(_ context.Context, req validator.StringRequest, re
 	}
 }
 
+// JWKS validates the string value is a JSON Web Key Set containing at least one valid public key.
+func JWKS() validator.String {
+	return jwks{}
+}
+
+type jwks struct{}
+
+func (v jwks) Description(_ context.Context) string {
+	return "Check that the given string is a JSON Web Key Set (JWKS) of public keys."
+}
+
+func (v jwks) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v jwks) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	// Attributes may be optional, and thus null, which should not fail validation.
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	var set jose.JSONWebKeySet
+	if err := json.Unmarshal([]byte(req.ConfigValue.ValueString()), &set); err != nil {
+		resp.Diagnostics.AddError("failed JWKS validation", fmt.Sprintf("failed to parse JSON web key set: %s", err.Error()))
+		return
+	}
+	if len(set.Keys) == 0 {
+		resp.Diagnostics.AddError("failed JWKS validation", "JSON web key set must contain at least one key")
+		return
+	}
+	for i, k := range set.Keys {
+		switch {
+		case !k.Valid():
+			resp.Diagnostics.AddError("failed JWKS validation", fmt.Sprintf("key %d (kid %q) is not a valid JSON web key", i, k.KeyID))
+		case !k.IsPublic():
+			resp.Diagnostics.AddError("failed JWKS validation", fmt.Sprintf("key %d (kid %q) is not a public key", i, k.KeyID))
+		}
+	}
+}
+
 // Name validates the string value is a valid Chainguard name.
 func Name() validator.String {
 	return name{}
@@ -169,6 +305,39 @@ func (v name) ValidateString(_ context.Context, req validator.StringRequest, res
 	}
 }
 
+// From https://github.com/opencontainers/distribution-spec/blob/main/spec.md#pulling-manifests
+var ociNamePattern = regexp.MustCompile(`^[a-z0-9]+((\.|_|__|-+)[a-z0-9]+)*(/[a-z0-9]+((\.|_|__|-+)[a-z0-9]+)*)*$`)
+
+// OCIName validates the string value is a valid OCI repository name: lowercase
+// alphanumeric components joined by '.', '_', '__' or '-' separators, optionally
+// nested with '/'. This is stricter than Name, which is meant for IAM resources.
+func OCIName() validator.String {
+	return ociName{}
+}
+
+type ociName struct{}
+
+func (v ociName) Description(_ context.Context) string {
+	return "Check a given name is a valid OCI repository name."
+}
+
+func (v ociName) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v ociName) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	// Attributes may be optional, and thus null, which should not fail validation.
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	name := req.ConfigValue.ValueString()
+	if !ociNamePattern.MatchString(name) {
+		resp.Diagnostics.AddError("failed name validation",
+			fmt.Sprintf("%s is not a valid OCI repository name: must match %q", name, ociNamePattern.String()))
+	}
+}
+
 // UIDP validates the string value is a valid Chainguard UIDP.
 // allowRootSentinel allows "/" as a valid UIDP, which for some endpoints signals root.
 func UIDP(allowRootSentinel bool) validator.String {
@@ -206,6 +375,55 @@ func (v uidpVal) ValidateString(_ context.Context, req validator.StringRequest,
 	}
 }
 
+// UIDPChildOf validates the string value is a descendant of the UIDP held by the
+// attribute at parentPath (e.g. path.MatchRoot("parent_id")). Expressions are relative
+// to the attribute being validated, and validation is skipped while the parent is unknown.
+func UIDPChildOf(parentPath path.Expression) validator.String {
+	return uidpChildOf{parentPath: parentPath}
+}
+
+type uidpChildOf struct {
+	parentPath path.Expression
+}
+
+func (v uidpChildOf) Description(_ context.Context) string {
+	return fmt.Sprintf("Check that the given string is a UIDP descending from the UIDP at %s.", v.parentPath)
+}
+
+func (v uidpChildOf) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v uidpChildOf) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	// Attributes may be optional, and thus null, which should not fail validation.
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	id := strings.TrimSpace(req.ConfigValue.ValueString())
+	matches, diags := req.Config.PathMatches(ctx, req.PathExpression.Merge(v.parentPath))
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	for _, p := range matches {
+		var parent types.String
+		if diags := req.Config.GetAttribute(ctx, p, &parent); diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+		// The parent may not be known until apply, in which case there's nothing to check yet.
+		if parent.IsNull() || parent.IsUnknown() {
+			continue
+		}
+		if !uidp.IsAncestor(parent.ValueString(), id) {
+			resp.Diagnostics.AddError("failed uidp validation",
+				fmt.Sprintf("%s is not a descendant of %s (%s)", id, parent.ValueString(), p))
+		}
+	}
+}
+
 type ValidateStringFunc func(string) error
 
 type validateStringFuncs struct {
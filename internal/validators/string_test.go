@@ -7,12 +7,82 @@ package validators
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
 	"testing"
+	"time"
 
+	"github.com/go-jose/go-jose/v4"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
+func TestAnnotationKeyValidateString(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		wantErr bool
+	}{
+		"oci": {
+			input:   "org.opencontainers.image.revision",
+			wantErr: false,
+		},
+		"hyphenated": {
+			input:   "dev.chainguard.build-time",
+			wantErr: false,
+		},
+		"mixed case": {
+			input:   "com.example.gitSha",
+			wantErr: false,
+		},
+		"single label": {
+			input:   "revision",
+			wantErr: true,
+		},
+		"empty label": {
+			input:   "org..example",
+			wantErr: true,
+		},
+		"trailing dot": {
+			input:   "org.example.",
+			wantErr: true,
+		},
+		"leading hyphen": {
+			input:   "org.-example",
+			wantErr: true,
+		},
+		"slash": {
+			input:   "example.com/revision",
+			wantErr: true,
+		},
+		"empty": {
+			input:   "",
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := validator.StringRequest{
+				ConfigValue: types.StringValue(test.input),
+			}
+			resp := &validator.StringResponse{}
+
+			AnnotationKey().ValidateString(context.Background(), req, resp)
+
+			if resp.Diagnostics.HasError() != test.wantErr {
+				t.Fatalf("AnnotationKey.ValidateString() mismatch, want=%t got=%t",
+					test.wantErr, resp.Diagnostics.HasError())
+			}
+		})
+	}
+}
+
 func Test_isURL_ValidateString(t *testing.T) {
 	tests := map[string]struct {
 		input   string
@@ -149,3 +219,303 @@ func TestUIDPValidateString(t *testing.T) {
 		})
 	}
 }
+
+func TestUIDPChildOfValidateString(t *testing.T) {
+	const group = "fb694596eb1678321f94eec283e1e0be690f655c"
+
+	tests := map[string]struct {
+		parent  tftypes.Value
+		input   string
+		wantErr bool
+	}{
+		"direct child": {
+			parent:  tftypes.NewValue(tftypes.String, group),
+			input:   group + "/7542b4e1600377ce",
+			wantErr: false,
+		},
+		"grandchild": {
+			parent:  tftypes.NewValue(tftypes.String, group),
+			input:   group + "/7542b4e1600377ce/3b9e1a7c1f2d4e5a",
+			wantErr: false,
+		},
+		"different group": {
+			parent:  tftypes.NewValue(tftypes.String, group),
+			input:   "0123456789abcdef0123456789abcdef01234567/7542b4e1600377ce",
+			wantErr: true,
+		},
+		"parent itself": {
+			parent:  tftypes.NewValue(tftypes.String, group),
+			input:   group,
+			wantErr: true,
+		},
+		"sibling": {
+			parent:  tftypes.NewValue(tftypes.String, group+"/7542b4e1600377ce"),
+			input:   group + "/3b9e1a7c1f2d4e5a",
+			wantErr: true,
+		},
+		"unknown parent": {
+			parent:  tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+			input:   "0123456789abcdef0123456789abcdef01234567/7542b4e1600377ce",
+			wantErr: false,
+		},
+		"null parent": {
+			parent:  tftypes.NewValue(tftypes.String, nil),
+			input:   "0123456789abcdef0123456789abcdef01234567/7542b4e1600377ce",
+			wantErr: false,
+		},
+	}
+
+	s := schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"parent_id": schema.StringAttribute{Optional: true},
+			"id":        schema.StringAttribute{Optional: true},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			req := validator.StringRequest{
+				Path:           path.Root("id"),
+				PathExpression: path.MatchRoot("id"),
+				ConfigValue:    types.StringValue(test.input),
+				Config: tfsdk.Config{
+					Schema: s,
+					Raw: tftypes.NewValue(s.Type().TerraformType(ctx), map[string]tftypes.Value{
+						"parent_id": test.parent,
+						"id":        tftypes.NewValue(tftypes.String, test.input),
+					}),
+				},
+			}
+			resp := &validator.StringResponse{}
+
+			UIDPChildOf(path.MatchRoot("parent_id")).ValidateString(ctx, req, resp)
+
+			if resp.Diagnostics.HasError() != test.wantErr {
+				t.Fatalf("UIDPChildOf.ValidateString() mismatch, want=%t got=%t: %v",
+					test.wantErr, resp.Diagnostics.HasError(), resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestFutureRFC3339ValidateString(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	t.Cleanup(func() { timeNow = time.Now })
+
+	tests := map[string]struct {
+		minLead    time.Duration
+		requireUTC bool
+		input      string
+		wantErr    bool
+	}{
+		"future UTC": {
+			minLead:    5 * time.Minute,
+			requireUTC: true,
+			input:      "2024-03-01T13:00:00Z",
+			wantErr:    false,
+		},
+		"explicit zero offset": {
+			requireUTC: true,
+			input:      "2024-03-01T13:00:00+00:00",
+			wantErr:    false,
+		},
+		"non-UTC offset": {
+			requireUTC: true,
+			input:      "2024-03-01T13:00:00-05:00",
+			wantErr:    true,
+		},
+		"non-UTC offset allowed": {
+			requireUTC: false,
+			input:      "2024-03-01T13:00:00-05:00",
+			wantErr:    false,
+		},
+		"exactly the minimum lead": {
+			minLead: 5 * time.Minute,
+			input:   "2024-03-01T12:05:00Z",
+			wantErr: false,
+		},
+		"just inside the minimum lead": {
+			minLead: 5 * time.Minute,
+			input:   "2024-03-01T12:04:59Z",
+			wantErr: true,
+		},
+		"lead time computed across zones": {
+			minLead: 5 * time.Minute,
+			input:   "2024-03-01T07:04:59-05:00",
+			wantErr: true,
+		},
+		"in the past": {
+			input:   "2024-03-01T11:59:59Z",
+			wantErr: true,
+		},
+		"not RFC3339": {
+			input:   "2024-03-01 13:00:00",
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := validator.StringRequest{
+				ConfigValue: types.StringValue(test.input),
+			}
+			resp := &validator.StringResponse{}
+
+			FutureRFC3339(test.minLead, test.requireUTC).ValidateString(context.Background(), req, resp)
+
+			if resp.Diagnostics.HasError() != test.wantErr {
+				t.Fatalf("FutureRFC3339.ValidateString() mismatch, want=%t got=%t: %v",
+					test.wantErr, resp.Diagnostics.HasError(), resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestJWKSValidateString(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() = %v", err)
+	}
+	jwk := func(key any) jose.JSONWebKey {
+		return jose.JSONWebKey{Key: key, KeyID: "kid", Algorithm: string(jose.ES256), Use: "sig"}
+	}
+	marshal := func(set jose.JSONWebKeySet) string {
+		b, err := json.Marshal(set)
+		if err != nil {
+			t.Fatalf("json.Marshal() = %v", err)
+		}
+		return string(b)
+	}
+
+	tests := map[string]struct {
+		input   string
+		wantErr bool
+	}{
+		"valid public key": {
+			input:   marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{jwk(priv.Public())}}),
+			wantErr: false,
+		},
+		"private key": {
+			input:   marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{jwk(priv)}}),
+			wantErr: true,
+		},
+		"empty keys array": {
+			input:   `{"keys":[]}`,
+			wantErr: true,
+		},
+		"missing keys": {
+			input:   `{"kty":"EC"}`,
+			wantErr: true,
+		},
+		"keys not an array": {
+			input:   `{"keys":{}}`,
+			wantErr: true,
+		},
+		"malformed key": {
+			input:   `{"keys":[{"kty":"EC","crv":"P-256","x":"nope","y":"nope"}]}`,
+			wantErr: true,
+		},
+		"not JSON": {
+			input:   "keys",
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := validator.StringRequest{
+				ConfigValue: types.StringValue(test.input),
+			}
+			resp := &validator.StringResponse{}
+
+			JWKS().ValidateString(context.Background(), req, resp)
+
+			if resp.Diagnostics.HasError() != test.wantErr {
+				t.Fatalf("JWKS.ValidateString() mismatch, want=%t got=%t: %v",
+					test.wantErr, resp.Diagnostics.HasError(), resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestOCINameValidateString(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		wantErr bool
+	}{
+		"simple": {
+			input:   "nginx",
+			wantErr: false,
+		},
+		"leading digit": {
+			input:   "2048-game",
+			wantErr: false,
+		},
+		"all separators": {
+			input:   "a.b_c__d-e--f",
+			wantErr: false,
+		},
+		"nested": {
+			input:   "tools/kubectl",
+			wantErr: false,
+		},
+		"uppercase": {
+			input:   "Nginx",
+			wantErr: true,
+		},
+		"leading underscore": {
+			input:   "_nginx",
+			wantErr: true,
+		},
+		"trailing underscore": {
+			input:   "nginx_",
+			wantErr: true,
+		},
+		"triple underscore": {
+			input:   "a___b",
+			wantErr: true,
+		},
+		"leading separator": {
+			input:   "-nginx",
+			wantErr: true,
+		},
+		"double dot": {
+			input:   "a..b",
+			wantErr: true,
+		},
+		"empty path component": {
+			input:   "tools//kubectl",
+			wantErr: true,
+		},
+		"trailing slash": {
+			input:   "tools/",
+			wantErr: true,
+		},
+		"tag suffix": {
+			input:   "nginx:latest",
+			wantErr: true,
+		},
+		"empty": {
+			input:   "",
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := validator.StringRequest{
+				ConfigValue: types.StringValue(test.input),
+			}
+			resp := &validator.StringResponse{}
+
+			OCIName().ValidateString(context.Background(), req, resp)
+
+			if resp.Diagnostics.HasError() != test.wantErr {
+				t.Fatalf("OCIName.ValidateString() mismatch, want=%t got=%t",
+					test.wantErr, resp.Diagnostics.HasError())
+			}
+		})
+	}
+}